@@ -0,0 +1,62 @@
+package gosundheit
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultStableExecutions = 5
+
+// adaptivePeriod tracks the current period of an adaptively scheduled check (see Config.MaxExecutionPeriod)
+type adaptivePeriod struct {
+	lock    sync.Mutex
+	current time.Duration
+	passes  int
+}
+
+// adaptiveScheduling returns true when the task is adaptively scheduled
+func (t *checkTask) adaptiveScheduling() bool {
+	return t.cfg.MaxExecutionPeriod > t.cfg.ExecutionPeriod
+}
+
+// period returns the current period of the task: the configured ExecutionPeriod, unless the task is adaptively scheduled.
+func (t *checkTask) period() time.Duration {
+	if !t.adaptiveScheduling() {
+		return t.cfg.ExecutionPeriod
+	}
+
+	t.adaptive.lock.Lock()
+	defer t.adaptive.lock.Unlock()
+	return t.adaptive.current
+}
+
+// adaptPeriod adapts the period of an adaptively scheduled task to the result of its execution:
+// the period is doubled once the task passed StableExecutions times in a row, and is reset after a failure.
+func (t *checkTask) adaptPeriod(result Result) {
+	if !t.adaptiveScheduling() {
+		return
+	}
+
+	t.adaptive.lock.Lock()
+	defer t.adaptive.lock.Unlock()
+	switch {
+	case !result.IsHealthy():
+		t.adaptive.current = t.cfg.ExecutionPeriod
+		t.adaptive.passes = 0
+	case result.Degraded:
+		t.adaptive.passes = 0
+	default:
+		t.adaptive.passes++
+		stable := t.cfg.StableExecutions
+		if stable == 0 {
+			stable = defaultStableExecutions
+		}
+		if t.adaptive.passes >= stable {
+			t.adaptive.passes = 0
+			t.adaptive.current *= 2
+			if t.adaptive.current > t.cfg.MaxExecutionPeriod {
+				t.adaptive.current = t.cfg.MaxExecutionPeriod
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package gosundheit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestAdaptPeriod(t *testing.T) {
+	cfg := &Config{ExecutionPeriod: time.Second, MaxExecutionPeriod: 5 * time.Second, StableExecutions: 2}
+	task := &checkTask{cfg: cfg, adaptive: adaptivePeriod{current: cfg.ExecutionPeriod}}
+	passed := Result{}
+	degraded := Result{Degraded: true}
+	failed := Result{Error: errors.New("down")}
+
+	var periods []time.Duration
+	for _, result := range []Result{passed, passed, passed, degraded, passed, passed, passed, passed, passed, passed, failed, passed} {
+		task.adaptPeriod(result)
+		periods = append(periods, task.period())
+	}
+	assert.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, // doubled after 2 passes
+		2 * time.Second, 2 * time.Second, // a degraded execution restarts the count
+		2 * time.Second, 4 * time.Second,
+		4 * time.Second, 5 * time.Second, // capped by the max period
+		5 * time.Second, 5 * time.Second,
+		time.Second, time.Second, // reset after a failure
+	}, periods)
+}
+
+func TestAdaptPeriod_disabled(t *testing.T) {
+	for _, cfg := range []*Config{
+		{ExecutionPeriod: time.Second},
+		{ExecutionPeriod: time.Second, MaxExecutionPeriod: time.Second, StableExecutions: 1},
+	} {
+		task := &checkTask{cfg: cfg, adaptive: adaptivePeriod{current: cfg.ExecutionPeriod}}
+		for i := 0; i < 10; i++ {
+			task.adaptPeriod(Result{})
+		}
+		assert.Equal(t, time.Second, task.period())
+	}
+}
+
+func TestAdaptiveScheduling(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.Error(t, h.RegisterCheck(&Config{Check: checks.NewScriptedCheck("db"), MaxExecutionPeriod: -time.Second}))
+	assert.Error(t, h.RegisterCheck(&Config{Check: checks.NewScriptedCheck("db"), ExecutionPeriod: time.Minute, StableExecutions: -1}))
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:              checks.NewScriptedCheck("db", checks.PassResult("ok")),
+		ExecutionPeriod:    time.Minute,
+		MaxExecutionPeriod: time.Hour,
+		InitialDelay:       time.Hour,
+	}))
+	task := h.(*health).checkTasks["db"]
+	assert.Equal(t, time.Minute, task.period())
+
+	for i := 0; i < defaultStableExecutions; i++ {
+		_, err := h.Trigger("db")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2*time.Minute, task.period(), "the period is doubled after the default stable executions")
+}
@@ -0,0 +1,46 @@
+package gosundheit
+
+import (
+	"sync/atomic"
+)
+
+// healthAggregate maintains the number of failing checks as results are updated,
+// so the aggregated health is evaluated in O(1) without locking.
+type healthAggregate struct {
+	// failing is the number of failing checks that affect the health of all instances
+	failing int64
+	// failingLeaderOnly is the number of failing checks that affect only the health of the leader
+	failingLeaderOnly int64
+}
+
+// update accounts for a check result transition, where a non existing result is considered passing.
+// Warning checks never affect the health.
+func (a *healthAggregate) update(cfg *Config, prevHealthy, healthy bool) {
+	if prevHealthy == healthy || cfg.Severity == Warning {
+		return
+	}
+
+	delta := int64(1)
+	if healthy {
+		delta = -1
+	}
+	if cfg.LeaderPolicy == AnyInstance {
+		atomic.AddInt64(&a.failing, delta)
+	} else {
+		atomic.AddInt64(&a.failingLeaderOnly, delta)
+	}
+}
+
+// failingChecks returns the number of failing checks affecting the health.
+func (a *healthAggregate) failingChecks(leader bool) int64 {
+	failing := atomic.LoadInt64(&a.failing)
+	if leader {
+		failing += atomic.LoadInt64(&a.failingLeaderOnly)
+	}
+	return failing
+}
+
+// healthy returns true iff no check affecting the health is failing.
+func (a *healthAggregate) healthy(leader bool) bool {
+	return a.failingChecks(leader) == 0
+}
@@ -0,0 +1,30 @@
+package gosundheit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthAggregate(t *testing.T) {
+	var a healthAggregate
+	anyInstance := &Config{}
+	leaderOnly := &Config{LeaderPolicy: LeaderOnlyHealth}
+
+	assert.True(t, a.healthy(true), "empty aggregate is healthy")
+
+	a.update(anyInstance, true, false)
+	a.update(leaderOnly, true, false)
+	assert.Equal(t, int64(2), a.failingChecks(true), "leader failing checks")
+	assert.Equal(t, int64(1), a.failingChecks(false), "non leader failing checks")
+
+	a.update(anyInstance, false, false)
+	assert.Equal(t, int64(2), a.failingChecks(true), "repeated failures are counted once")
+
+	a.update(anyInstance, false, true)
+	assert.False(t, a.healthy(true), "leader is unhealthy while leader only check fails")
+	assert.True(t, a.healthy(false), "non leader is healthy while leader only check fails")
+
+	a.update(leaderOnly, false, true)
+	assert.True(t, a.healthy(true), "all checks recovered")
+}
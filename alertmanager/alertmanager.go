@@ -0,0 +1,293 @@
+// Package alertmanager emits alerts of failing checks to the Prometheus Alertmanager API,
+// bridging the in-process health directly into the existing alert routing.
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultAlertName      = "HealthCheckFailing"
+	defaultResendInterval = time.Minute
+	defaultTimeout        = 5 * time.Second
+	batchesBufferSize     = 64
+	alertsPath            = "/api/v2/alerts"
+)
+
+// Alert is an alert, as posted to the Alertmanager API.
+type Alert struct {
+	// Labels identify the alert: the alert name, the check name, the check classification and owner when configured,
+	// and the labels configured using WithLabels
+	Labels map[string]string `json:"labels"`
+	// Annotations are the summary, the check error, description and runbook URL
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// StartsAt is the time of the first failure
+	StartsAt time.Time `json:"startsAt"`
+	// EndsAt is the time of the recovery, nil while the alert is firing
+	EndsAt       *time.Time `json:"endsAt,omitempty"`
+	GeneratorURL string     `json:"generatorURL,omitempty"`
+}
+
+// Option configures the Listener
+type Option func(*Listener)
+
+// WithClient sets the HTTP client used to post the alerts; defaults to http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(l *Listener) {
+		l.client = client
+	}
+}
+
+// WithAlertName sets the alertname label of the alerts; defaults to "HealthCheckFailing".
+func WithAlertName(name string) Option {
+	return func(l *Listener) {
+		l.alertName = name
+	}
+}
+
+// WithLabels adds labels to all the alerts, e.g. the service and instance names, for routing.
+func WithLabels(labels map[string]string) Option {
+	return func(l *Listener) {
+		for name, value := range labels {
+			l.labels[name] = value
+		}
+	}
+}
+
+// WithGeneratorURL sets the generator URL of the alerts, e.g. the health endpoint of the service.
+func WithGeneratorURL(url string) Option {
+	return func(l *Listener) {
+		l.generatorURL = url
+	}
+}
+
+// WithResendInterval sets the interval of re-posting the firing alerts, which must be shorter than
+// the resolve_timeout of Alertmanager, so it doesn't resolve them; defaults to 1m.
+func WithResendInterval(interval time.Duration) Option {
+	return func(l *Listener) {
+		l.resendInterval = interval
+	}
+}
+
+// WithTimeout sets the timeout of posting the alerts; defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(l *Listener) {
+		l.timeout = timeout
+	}
+}
+
+// WithErrorHandler sets a handler of the posting errors, which are discarded by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Listener) {
+		l.onError = handler
+	}
+}
+
+// Listener is a gosundheit.HealthListener that posts an alert per failing check to Alertmanager.
+// An alert fires on the first failure of a check, is re-posted periodically while the check keeps failing,
+// and is resolved once the check recovers, or is deregistered. Silenced failures, and failures within the grace period, don't fire.
+// Alerts are posted in the background, so the checks are never blocked by Alertmanager.
+type Listener struct {
+	endpoint       string
+	client         *http.Client
+	alertName      string
+	labels         map[string]string
+	generatorURL   string
+	resendInterval time.Duration
+	timeout        time.Duration
+	onError        func(error)
+	now            func() time.Time
+
+	lock     sync.Mutex
+	firing   map[string]*Alert
+	lastSent time.Time
+	batches  chan []Alert
+	closed   bool
+	done     chan struct{}
+}
+
+// NewListener returns a Listener posting to the Alertmanager at the given URL, e.g. "http://alertmanager:9093",
+// which should be closed once the health is no longer used.
+func NewListener(url string, opts ...Option) *Listener {
+	l := &Listener{
+		endpoint:       strings.TrimSuffix(url, "/") + alertsPath,
+		client:         http.DefaultClient,
+		alertName:      defaultAlertName,
+		labels:         make(map[string]string),
+		resendInterval: defaultResendInterval,
+		timeout:        defaultTimeout,
+		onError:        func(error) {},
+		now:            time.Now,
+		firing:         make(map[string]*Alert),
+		batches:        make(chan []Alert, batchesBufferSize),
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	go l.post()
+	return l
+}
+
+// OnResultsUpdated enqueues the new and resolved alerts, along with the firing alerts, once changed or once the resend interval passed.
+func (l *Listener) OnResultsUpdated(results map[string]gosundheit.Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+
+	now := l.now()
+	var resolved []Alert
+	for name, alert := range l.firing {
+		if result, ok := results[name]; ok && isFiring(result) {
+			continue
+		}
+		endsAt := now
+		alert.EndsAt = &endsAt
+		resolved = append(resolved, *alert)
+		delete(l.firing, name)
+	}
+
+	changed := len(resolved) > 0
+	for name, result := range results {
+		if !isFiring(result) {
+			continue
+		}
+		alert, ok := l.firing[name]
+		if !ok {
+			alert = l.newAlert(name, result, now)
+			l.firing[name] = alert
+			changed = true
+		}
+		alert.Annotations = annotationsOf(name, result)
+	}
+	if !changed && now.Sub(l.lastSent) < l.resendInterval {
+		return
+	}
+
+	batch := resolved
+	for _, alert := range l.firing {
+		batch = append(batch, *alert)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	sort.Slice(batch, func(i, j int) bool {
+		return batch[i].Labels["check"] < batch[j].Labels["check"]
+	})
+	l.lastSent = now
+	l.enqueue(batch)
+}
+
+func isFiring(result gosundheit.Result) bool {
+	return result.Executed() && !result.IsHealthy() && !result.Silenced && !result.InGracePeriod
+}
+
+func (l *Listener) newAlert(name string, result gosundheit.Result, now time.Time) *Alert {
+	labels := make(map[string]string, len(l.labels)+4)
+	for label, value := range l.labels {
+		labels[label] = value
+	}
+	labels["alertname"] = l.alertName
+	labels["check"] = name
+	if result.Classification != "" {
+		labels["classification"] = result.Classification
+	}
+	if result.Owner != "" {
+		labels["owner"] = result.Owner
+	}
+
+	startsAt := now
+	if result.TimeOfFirstFailure != nil {
+		startsAt = *result.TimeOfFirstFailure
+	}
+	return &Alert{Labels: labels, StartsAt: startsAt, GeneratorURL: l.generatorURL}
+}
+
+func annotationsOf(name string, result gosundheit.Result) map[string]string {
+	annotations := map[string]string{
+		"summary": fmt.Sprintf("check %s is failing", name),
+		"error":   result.Error.Error(),
+	}
+	if result.Description != "" {
+		annotations["description"] = result.Description
+	}
+	if result.RunbookURL != "" {
+		annotations["runbook_url"] = result.RunbookURL
+	}
+	return annotations
+}
+
+// enqueue adds the batch to the queue, dropping the oldest batch when the queue is full. Callers must hold the lock.
+func (l *Listener) enqueue(batch []Alert) {
+	for {
+		select {
+		case l.batches <- batch:
+			return
+		default:
+			select {
+			case <-l.batches:
+			default:
+			}
+		}
+	}
+}
+
+func (l *Listener) post() {
+	defer close(l.done)
+	for batch := range l.batches {
+		if err := l.postBatch(batch); err != nil {
+			l.onError(err)
+		}
+	}
+}
+
+func (l *Listener) postBatch(batch []Alert) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the alerts")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, l.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post the alerts")
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("alerts rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close stops accepting results updates, and returns once the pending alerts are posted.
+func (l *Listener) Close() {
+	l.lock.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.batches)
+	}
+	l.lock.Unlock()
+	<-l.done
+}
@@ -0,0 +1,125 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+type fakeAlertmanager struct {
+	lock    sync.Mutex
+	batches [][]Alert
+	status  int
+}
+
+func (a *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/api/v2/alerts" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var batch []Alert
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.batches = append(a.batches, batch)
+	if a.status != 0 {
+		w.WriteHeader(a.status)
+	}
+}
+
+func TestListener(t *testing.T) {
+	alertmanager := &fakeAlertmanager{}
+	server := httptest.NewServer(alertmanager)
+	defer server.Close()
+
+	listener := NewListener(server.URL+"/",
+		WithLabels(map[string]string{"service": "orders"}),
+		WithGeneratorURL("http://orders-1/health"),
+		WithResendInterval(time.Minute))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	listener.now = func() time.Time { return now }
+
+	down := errors.New("down")
+	since := now.Add(-time.Second)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Details: "didn't run yet", Error: down},
+		"cache": {Error: down, Silenced: true},
+	})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, TimeOfFirstFailure: &since, Classification: "storage", RunbookURL: "https://runbooks/db"},
+		"cache": {Error: down, InGracePeriod: true},
+	})
+	// unchanged, within the resend interval
+	now = now.Add(time.Second)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("still down")}, "cache": {}})
+	// resent
+	now = now.Add(time.Minute)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("still down")}, "cache": {}})
+	// resolved
+	now = now.Add(time.Second)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {}, "cache": {}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {}, "cache": {}})
+	listener.Close()
+
+	labels := map[string]string{"alertname": "HealthCheckFailing", "check": "db", "classification": "storage", "service": "orders"}
+	if assert.Len(t, alertmanager.batches, 3) {
+		assert.Equal(t, []Alert{{
+			Labels:       labels,
+			Annotations:  map[string]string{"summary": "check db is failing", "error": "down", "runbook_url": "https://runbooks/db"},
+			StartsAt:     since,
+			GeneratorURL: "http://orders-1/health",
+		}}, alertmanager.batches[0])
+
+		assert.Equal(t, "still down", alertmanager.batches[1][0].Annotations["error"])
+		assert.Nil(t, alertmanager.batches[1][0].EndsAt)
+
+		resolved := alertmanager.batches[2]
+		if assert.Len(t, resolved, 1) {
+			assert.Equal(t, labels, resolved[0].Labels, "the alert is resolved by the same labels")
+			assert.Equal(t, since, resolved[0].StartsAt)
+			if assert.NotNil(t, resolved[0].EndsAt) {
+				assert.Equal(t, now, *resolved[0].EndsAt)
+			}
+		}
+	}
+}
+
+func TestListener_deregisteredCheck(t *testing.T) {
+	alertmanager := &fakeAlertmanager{}
+	server := httptest.NewServer(alertmanager)
+	defer server.Close()
+
+	listener := NewListener(server.URL, WithAlertName("OrdersUnhealthy"))
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("down")}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{})
+	listener.Close()
+
+	if assert.Len(t, alertmanager.batches, 2) {
+		assert.Equal(t, "OrdersUnhealthy", alertmanager.batches[1][0].Labels["alertname"])
+		assert.NotNil(t, alertmanager.batches[1][0].EndsAt, "the alert of a deregistered check is resolved")
+	}
+}
+
+func TestListener_errors(t *testing.T) {
+	alertmanager := &fakeAlertmanager{status: http.StatusBadRequest}
+	server := httptest.NewServer(alertmanager)
+	defer server.Close()
+
+	errs := make(chan error, 1)
+	listener := NewListener(server.URL, WithErrorHandler(func(err error) { errs <- err }))
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("down")}})
+	listener.Close()
+
+	assert.EqualError(t, <-errs, "alerts rejected with status 400")
+}
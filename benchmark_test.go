@@ -0,0 +1,94 @@
+package gosundheit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+const benchmarkChecks = 500
+
+func newBenchmarkHealth(b *testing.B) *health {
+	h := New().(*health)
+	for i := 0; i < benchmarkChecks; i++ {
+		err := h.RegisterCheck(&Config{
+			Check: &checks.CustomCheck{
+				CheckName: fmt.Sprintf("check.%d", i),
+				CheckFunc: func() (details interface{}, err error) { return successMsg, nil },
+			},
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	return h
+}
+
+func BenchmarkResults(b *testing.B) {
+	h := newBenchmarkHealth(b)
+	defer h.DeregisterAll()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.Results()
+		}
+	})
+}
+
+func BenchmarkIsHealthy(b *testing.B) {
+	h := newBenchmarkHealth(b)
+	defer h.DeregisterAll()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.IsHealthy()
+		}
+	})
+}
+
+func BenchmarkResultsJSON(b *testing.B) {
+	h := newBenchmarkHealth(b)
+	defer h.DeregisterAll()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.ResultsJSON()
+		}
+	})
+}
+
+func BenchmarkResultsWithUpdates(b *testing.B) {
+	h := newBenchmarkHealth(b)
+	defer h.DeregisterAll()
+	task := h.checkTasks["check.0"]
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%100 == 0 {
+				h.updateResult(task, checks.ExecutionResult{Details: successMsg}, 0, time.Now())
+			}
+			h.Results()
+			i++
+		}
+	})
+}
+
+func BenchmarkUpdateResult(b *testing.B) {
+	h := newBenchmarkHealth(b)
+	defer h.DeregisterAll()
+	task := h.checkTasks["check.0"]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.updateResult(task, checks.ExecutionResult{Details: successMsg}, 0, time.Now())
+	}
+}
@@ -0,0 +1,185 @@
+package gosundheit
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// ConfigBuilder builds a check Config fluently, and validates it, e.g.:
+//
+//	cfg, err := gosundheit.NewCheckConfig(check).
+//		Every(30 * time.Second).
+//		InitialDelay(5 * time.Second).
+//		Timeout(2 * time.Second).
+//		Critical().
+//		Build()
+type ConfigBuilder struct {
+	cfg Config
+}
+
+// NewCheckConfig returns a builder of the Config of the given check
+func NewCheckConfig(check checks.Check) *ConfigBuilder {
+	return &ConfigBuilder{cfg: Config{Check: check}}
+}
+
+// Every sets the ExecutionPeriod, which is required
+func (b *ConfigBuilder) Every(period time.Duration) *ConfigBuilder {
+	b.cfg.ExecutionPeriod = period
+	return b
+}
+
+// FixedDelay sets the SchedulingMode to FixedDelay
+func (b *ConfigBuilder) FixedDelay() *ConfigBuilder {
+	b.cfg.SchedulingMode = FixedDelay
+	return b
+}
+
+// InitialDelay sets the InitialDelay
+func (b *ConfigBuilder) InitialDelay(delay time.Duration) *ConfigBuilder {
+	b.cfg.InitialDelay = delay
+	return b
+}
+
+// InitiallyPassing sets InitiallyPassing
+func (b *ConfigBuilder) InitiallyPassing() *ConfigBuilder {
+	b.cfg.InitiallyPassing = true
+	return b
+}
+
+// Timeout sets the execution Timeout
+func (b *ConfigBuilder) Timeout(timeout time.Duration) *ConfigBuilder {
+	b.cfg.Timeout = timeout
+	return b
+}
+
+// Critical sets the Severity to Critical, which is the default
+func (b *ConfigBuilder) Critical() *ConfigBuilder {
+	b.cfg.Severity = Critical
+	return b
+}
+
+// Warning sets the Severity to Warning
+func (b *ConfigBuilder) Warning() *ConfigBuilder {
+	b.cfg.Severity = Warning
+	return b
+}
+
+// Silences adds silence windows
+func (b *ConfigBuilder) Silences(windows ...SilenceWindow) *ConfigBuilder {
+	b.cfg.Silences = append(b.cfg.Silences, windows...)
+	return b
+}
+
+// Retries sets the Retries, the RetryDelay and the RetryBackoff
+func (b *ConfigBuilder) Retries(retries int, delay time.Duration, backoff float64) *ConfigBuilder {
+	b.cfg.Retries = retries
+	b.cfg.RetryDelay = delay
+	b.cfg.RetryBackoff = backoff
+	return b
+}
+
+// HedgeDelay sets the HedgeDelay
+func (b *ConfigBuilder) HedgeDelay(delay time.Duration) *ConfigBuilder {
+	b.cfg.HedgeDelay = delay
+	return b
+}
+
+// GracePeriod sets the GracePeriod
+func (b *ConfigBuilder) GracePeriod(period time.Duration) *ConfigBuilder {
+	b.cfg.GracePeriod = period
+	return b
+}
+
+// ConcurrencyGroup sets the ConcurrencyGroup
+func (b *ConfigBuilder) ConcurrencyGroup(group string) *ConfigBuilder {
+	b.cfg.ConcurrencyGroup = group
+	return b
+}
+
+// Classification sets the Classification
+func (b *ConfigBuilder) Classification(classification string) *ConfigBuilder {
+	b.cfg.Classification = classification
+	return b
+}
+
+// Description sets the Description
+func (b *ConfigBuilder) Description(description string) *ConfigBuilder {
+	b.cfg.Description = description
+	return b
+}
+
+// RunbookURL sets the RunbookURL
+func (b *ConfigBuilder) RunbookURL(url string) *ConfigBuilder {
+	b.cfg.RunbookURL = url
+	return b
+}
+
+// Owner sets the Owner
+func (b *ConfigBuilder) Owner(owner string) *ConfigBuilder {
+	b.cfg.Owner = owner
+	return b
+}
+
+// Annotation adds an annotation
+func (b *ConfigBuilder) Annotation(key, value string) *ConfigBuilder {
+	if b.cfg.Annotations == nil {
+		b.cfg.Annotations = make(map[string]string)
+	}
+	b.cfg.Annotations[key] = value
+	return b
+}
+
+// Capabilities adds capabilities gated by the check
+func (b *ConfigBuilder) Capabilities(capabilities ...string) *ConfigBuilder {
+	b.cfg.Capabilities = append(b.cfg.Capabilities, capabilities...)
+	return b
+}
+
+// Weight sets the Weight
+func (b *ConfigBuilder) Weight(weight float64) *ConfigBuilder {
+	b.cfg.Weight = weight
+	return b
+}
+
+// DeregisterOnPass sets DeregisterOnPass
+func (b *ConfigBuilder) DeregisterOnPass() *ConfigBuilder {
+	b.cfg.DeregisterOnPass = true
+	return b
+}
+
+// TTL sets the TTL
+func (b *ConfigBuilder) TTL(ttl time.Duration) *ConfigBuilder {
+	b.cfg.TTL = ttl
+	return b
+}
+
+// LeaderPolicy sets the LeaderPolicy
+func (b *ConfigBuilder) LeaderPolicy(policy LeaderPolicy) *ConfigBuilder {
+	b.cfg.LeaderPolicy = policy
+	return b
+}
+
+// Build validates the configuration, and returns a copy of it.
+// Build fails when the check is missing, the ExecutionPeriod isn't positive, or any other field is invalid.
+func (b *ConfigBuilder) Build() (*Config, error) {
+	if err := validateConfig(&b.cfg); err != nil {
+		return nil, err
+	}
+	if b.cfg.InitialDelay < 0 || b.cfg.TTL < 0 {
+		return nil, errors.Errorf("misconfigured check %s initial delay %v ttl %v, must not be negative", b.cfg.Check.Name(), b.cfg.InitialDelay, b.cfg.TTL)
+	}
+
+	cfg := b.cfg
+	if cfg.Annotations != nil {
+		cfg.Annotations = make(map[string]string, len(b.cfg.Annotations))
+		for key, value := range b.cfg.Annotations {
+			cfg.Annotations[key] = value
+		}
+	}
+	cfg.Capabilities = append([]string(nil), b.cfg.Capabilities...)
+	cfg.Silences = append([]SilenceWindow(nil), b.cfg.Silences...)
+	return &cfg, nil
+}
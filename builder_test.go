@@ -0,0 +1,68 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestConfigBuilder(t *testing.T) {
+	check := &checks.CustomCheck{CheckName: "db.check"}
+	builder := NewCheckConfig(check).
+		Every(30*time.Second).
+		FixedDelay().
+		InitialDelay(5*time.Second).
+		Timeout(2*time.Second).
+		Warning().
+		Critical().
+		Retries(2, 100*time.Millisecond, 2).
+		ConcurrencyGroup("admin.pool").
+		GracePeriod(time.Minute).
+		Classification("readiness").
+		Owner("storage-team").
+		Annotation("pager", "storage").
+		Capabilities("writes")
+
+	cfg, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{
+		Check:            check,
+		ExecutionPeriod:  30 * time.Second,
+		SchedulingMode:   FixedDelay,
+		InitialDelay:     5 * time.Second,
+		Timeout:          2 * time.Second,
+		Severity:         Critical,
+		Retries:          2,
+		RetryDelay:       100 * time.Millisecond,
+		RetryBackoff:     2,
+		ConcurrencyGroup: "admin.pool",
+		GracePeriod:      time.Minute,
+		Classification:   "readiness",
+		Owner:            "storage-team",
+		Annotations:      map[string]string{"pager": "storage"},
+		Capabilities:     []string{"writes"},
+	}, cfg)
+
+	builder.Annotation("pager", "dba").Capabilities("reads")
+	assert.Equal(t, map[string]string{"pager": "storage"}, cfg.Annotations, "built configs are not modified by the builder")
+	assert.Equal(t, []string{"writes"}, cfg.Capabilities, "built configs are not modified by the builder")
+}
+
+func TestConfigBuilderValidation(t *testing.T) {
+	check := &checks.CustomCheck{CheckName: "db.check"}
+	for name, builder := range map[string]*ConfigBuilder{
+		"missing check":            NewCheckConfig(nil).Every(time.Second),
+		"missing execution period": NewCheckConfig(check),
+		"negative timeout":         NewCheckConfig(check).Every(time.Second).Timeout(-time.Second),
+		"negative initial delay":   NewCheckConfig(check).Every(time.Second).InitialDelay(-time.Second),
+		"negative weight":          NewCheckConfig(check).Every(time.Second).Weight(-1),
+		"hedged concurrency group": NewCheckConfig(check).Every(time.Second).HedgeDelay(time.Millisecond).ConcurrencyGroup("admin"),
+	} {
+		cfg, err := builder.Build()
+		assert.Error(t, err, name)
+		assert.Nil(t, cfg, name)
+	}
+}
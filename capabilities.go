@@ -0,0 +1,40 @@
+package gosundheit
+
+import (
+	"sort"
+)
+
+func appendCapabilities(capabilities map[string]bool, added []string) map[string]bool {
+	if len(added) == 0 {
+		return capabilities
+	}
+	if capabilities == nil {
+		capabilities = make(map[string]bool, len(added))
+	}
+	for _, capability := range added {
+		capabilities[capability] = true
+	}
+	return capabilities
+}
+
+// impactedCapabilities returns the sorted capabilities impacted by the failing checks,
+// ignoring leader only checks on non leader instances.
+func (s *resultsSnapshot) impactedCapabilities(leader bool) []string {
+	capabilities := make([]string, 0, len(s.impacted)+len(s.impactedLeaderOnly))
+	for capability := range s.impacted {
+		capabilities = append(capabilities, capability)
+	}
+	if leader {
+		for capability := range s.impactedLeaderOnly {
+			if !s.impacted[capability] {
+				capabilities = append(capabilities, capability)
+			}
+		}
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+func (h *health) ImpactedCapabilities() []string {
+	return h.currentSnapshot().impactedCapabilities(h.isLeader())
+}
@@ -0,0 +1,49 @@
+package gosundheit
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestImpactedCapabilities(t *testing.T) {
+	leader := int32(1)
+	h := New(WithLeaderElection(func() bool { return atomic.LoadInt32(&leader) == 1 }))
+	defer h.DeregisterAll()
+
+	assert.Empty(t, h.ImpactedCapabilities(), "impacted capabilities with no checks")
+
+	register := func(name string, passing bool, policy LeaderPolicy, capabilities ...string) {
+		_ = h.RegisterCheck(&Config{
+			Check: &checks.CustomCheck{
+				CheckName: name,
+				CheckFunc: func() (details interface{}, err error) {
+					if !passing {
+						err = errors.New(failedMsg)
+					}
+					return
+				},
+			},
+			ExecutionPeriod: time.Hour,
+			LeaderPolicy:    policy,
+			Capabilities:    capabilities,
+		})
+	}
+	register("search.index", true, AnyInstance, "search")
+	register("primary.db", false, AnyInstance, "writes", "checkout")
+	register("replica.db", false, LeaderOnlyHealth, "reports", "writes")
+
+	// await first execution
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []string{"checkout", "reports", "writes"}, h.ImpactedCapabilities(), "impacted capabilities on the leader")
+	atomic.StoreInt32(&leader, 0)
+	assert.Equal(t, []string{"checkout", "writes"}, h.ImpactedCapabilities(), "impacted capabilities on non leaders")
+
+	results, _ := h.Results()
+	assert.Equal(t, []string{"writes", "checkout"}, results["primary.db"].Capabilities, "result capabilities")
+}
@@ -0,0 +1,93 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// DefaultQuery is a lightweight query, served by the coordinator node without reading any user data.
+const DefaultQuery = "SELECT now() FROM system.local"
+
+// QueryInfo describes the execution of a query.
+type QueryInfo struct {
+	// Coordinator is the address of the node that coordinated the query.
+	Coordinator string
+	// Latency is the latency reported by the driver; when zero, the check measures the latency itself.
+	Latency time.Duration
+}
+
+// Session is the minimal CQL session API required by the session check.
+// Implementations are usually thin adapters over a driver session (e.g. `*gocql.Session`, using a query observer
+// for reporting the coordinator).
+type Session interface {
+	// Query executes the given statement, consuming its results, and reports how it was executed.
+	Query(ctx context.Context, stmt string) (QueryInfo, error)
+}
+
+// CheckConfig configures a check that verifies a CQL session is able to execute queries.
+type CheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Session is required, and is used for executing the query.
+	Session Session
+	// Query is the statement to execute, defaults to DefaultQuery.
+	Query string
+	// Timeout is the timeout used for executing the query, defaults to "1s".
+	Timeout time.Duration
+}
+
+// Details are the details reported by the session check.
+type Details struct {
+	// Coordinator is the address of the node that coordinated the query, when reported by the session.
+	Coordinator string `json:"coordinator,omitempty"`
+	// Latency is the coordinator latency of the query, when it succeeded.
+	Latency string `json:"latency,omitempty"`
+}
+
+type sessionCheck struct {
+	config *CheckConfig
+}
+
+// NewSessionCheck creates a new CQL session check defined by the given config.
+func NewSessionCheck(config CheckConfig) (checks.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Session == nil {
+		return nil, errors.Errorf("Session must not be nil")
+	}
+	if config.Query == "" {
+		config.Query = DefaultQuery
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &sessionCheck{config: &config}, nil
+}
+
+func (check *sessionCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *sessionCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	info, err := check.config.Session.Query(ctx, check.config.Query)
+	result := Details{Coordinator: info.Coordinator}
+	if err != nil {
+		return result, errors.Errorf("failed to execute query: %v", err)
+	}
+	if info.Latency == 0 {
+		info.Latency = time.Since(start)
+	}
+	result.Latency = info.Latency.String()
+	return result, nil
+}
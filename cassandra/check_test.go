@@ -0,0 +1,56 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSessionCheckRequiredFields(t *testing.T) {
+	check, err := NewSessionCheck(CheckConfig{Session: &sessionStub{}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewSessionCheck(CheckConfig{CheckName: "meh"})
+	assert.Nil(t, check, "nil Session should yield nil check")
+	assert.Error(t, err, "nil Session should yield error")
+}
+
+func TestSessionCheck(t *testing.T) {
+	session := &sessionStub{info: QueryInfo{Coordinator: "10.0.0.1:9042", Latency: 3 * time.Millisecond}}
+	check, err := NewSessionCheck(CheckConfig{CheckName: "cassandra.check", Session: session})
+	assert.NoError(t, err)
+	assert.Equal(t, "cassandra.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass")
+	assert.Equal(t, Details{Coordinator: "10.0.0.1:9042", Latency: "3ms"}, details)
+	assert.Equal(t, DefaultQuery, session.stmt, "default query")
+	assert.True(t, session.hasDeadline, "query should have a deadline")
+
+	session.info.Latency = 0
+	details, err = check.Execute()
+	assert.NoError(t, err, "check should pass")
+	assert.NotEmpty(t, details.(Details).Latency, "latency should be measured when not reported")
+
+	session.err = errors.New("gocql: no hosts available in the pool")
+	details, err = check.Execute()
+	assert.EqualError(t, err, "failed to execute query: gocql: no hosts available in the pool")
+	assert.Equal(t, Details{Coordinator: "10.0.0.1:9042"}, details)
+}
+
+type sessionStub struct {
+	info        QueryInfo
+	err         error
+	stmt        string
+	hasDeadline bool
+}
+
+func (s *sessionStub) Query(ctx context.Context, stmt string) (QueryInfo, error) {
+	s.stmt = stmt
+	_, s.hasDeadline = ctx.Deadline()
+	return s.info, s.err
+}
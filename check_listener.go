@@ -18,6 +18,13 @@ type CheckListener interface {
 	OnCheckCompleted(name string, result Result)
 }
 
+// CheckDeregisteredListener is an optional interface, which a CheckListener may implement
+// in order to be notified when checks are deregistered.
+type CheckDeregisteredListener interface {
+	// OnCheckDeregistered is called once the check with the specified name is deregistered, and its results are removed.
+	OnCheckDeregistered(name string)
+}
+
 type CheckListeners []CheckListener
 
 func (c CheckListeners) OnCheckRegistered(name string, result Result) {
@@ -37,3 +44,11 @@ func (c CheckListeners) OnCheckCompleted(name string, result Result) {
 		listener.OnCheckCompleted(name, result)
 	}
 }
+
+func (c CheckListeners) OnCheckDeregistered(name string) {
+	for _, listener := range c {
+		if l, ok := listener.(CheckDeregisteredListener); ok {
+			l.OnCheckDeregistered(name)
+		}
+	}
+}
@@ -1,27 +1,162 @@
 package gosundheit
 
 import (
+	"context"
+	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/AppsFlyer/go-sundheit/checks"
 )
 
 type checkTask struct {
-	stopChan chan bool
-	ticker   *time.Ticker
-	check    checks.Check
+	// cost is atomically accessed, so it comes first, for alignment on 32 bit platforms
+	cost costTracker
+	// stopChan is closed once the task is unscheduled
+	stopChan chan struct{}
+	stopOnce sync.Once
+	// panics is the number of consecutive executions that panicked, and quarantined is set once the task is quarantined
+	panics      int32
+	quarantined int32
+	// active is done once the task is unscheduled, and is no longer executing
+	active sync.WaitGroup
+	check  checks.Check
+	cfg    *Config
+	// expiry deregisters the task once its TTL elapses, if configured
+	expiry *time.Timer
+	// registered is the registration time, from which the grace period is measured
+	registered time.Time
+	// adaptive is the current period of the task, when adaptively scheduled
+	adaptive adaptivePeriod
+
+	// shared scheduler state, guarded by the scheduler lock
+	nextRun    time.Time
+	queueIndex int
+	executing  bool
+	cancelled  bool
+}
+
+// execute runs the check, retrying failures as configured, and measures the duration of all attempts using the given time source.
+// The duration is the latency of the last attempt instead, when measured by the check itself.
+// The duration of all attempts is accounted for in the cost of the task either way.
+// Retries stop once the task is unscheduled, or once the given context is done.
+func (t *checkTask) execute(ctx context.Context, now func() time.Time) (outcome checks.ExecutionResult, duration time.Duration) {
+	startTime := now()
+	outcome = t.attempt(ctx)
+	delay := t.cfg.RetryDelay
+	for retry := 0; outcome.Err != nil && retry < t.cfg.Retries; retry++ {
+		if !t.waitRetry(ctx, delay) {
+			break
+		}
+		if t.cfg.RetryBackoff > 1 {
+			delay = time.Duration(float64(delay) * t.cfg.RetryBackoff)
+		}
+		outcome = t.attempt(ctx)
+	}
+	duration = now().Sub(startTime)
+	t.cost.record(duration)
+	if outcome.Latency > 0 {
+		duration = outcome.Latency
+	}
+
+	return
 }
 
+// stop closes stopChan, once
 func (t *checkTask) stop() {
-	if t.ticker != nil {
-		t.ticker.Stop()
+	t.stopOnce.Do(func() {
+		close(t.stopChan)
+	})
+}
+
+// waitRetry waits for the given delay, and returns false when the task was unscheduled, or the context was done meanwhile
+func (t *checkTask) waitRetry(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-t.stopChan:
+		return false
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
 }
 
-func (t *checkTask) execute() (details interface{}, duration time.Duration, err error) {
-	startTime := time.Now()
-	details, err = t.check.Execute()
-	duration = time.Since(startTime)
+// attempt executes the check once, within the configured timeout, and the deadline of the given context
+func (t *checkTask) attempt(parent context.Context) checks.ExecutionResult {
+	if t.cfg.Timeout <= 0 && parent.Done() == nil {
+		return t.hedgedAttempt(parent)
+	}
 
-	return
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if t.cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, t.cfg.Timeout)
+	}
+	defer cancel()
+
+	// buffered, so the abandoned attempt completes without blocking
+	outcomes := make(chan checks.ExecutionResult, 1)
+	go func() {
+		outcomes <- t.hedgedAttempt(ctx)
+	}()
+
+	select {
+	case outcome := <-outcomes:
+		return outcome
+	case <-ctx.Done():
+		if parent.Err() != nil {
+			return checks.ExecutionResult{Err: errors.Wrap(parent.Err(), "check execution abandoned")}
+		}
+		return checks.ExecutionResult{Err: errors.Errorf("check timed out after %v", t.cfg.Timeout)}
+	}
+}
+
+// hedgedAttempt executes the check once, hedging the execution when configured
+func (t *checkTask) hedgedAttempt(ctx context.Context) checks.ExecutionResult {
+	if t.cfg.HedgeDelay <= 0 {
+		return t.run(ctx)
+	}
+
+	// buffered, so the abandoned attempt completes without blocking
+	outcomes := make(chan checks.ExecutionResult, 2)
+	run := func() {
+		outcomes <- t.run(ctx)
+	}
+	go run()
+
+	hedge := time.NewTimer(t.cfg.HedgeDelay)
+	defer hedge.Stop()
+	select {
+	case outcome := <-outcomes:
+		return outcome
+	case <-hedge.C:
+		go run()
+	}
+	return <-outcomes
+}
+
+// run executes the check, using ExecuteWithContext() when the check implements checks.ContextCheck.
+// Panics of the check are recovered, and fail the execution.
+func (t *checkTask) run(ctx context.Context) (outcome checks.ExecutionResult) {
+	defer func() {
+		if value := recover(); value != nil {
+			outcome = checks.ExecutionResult{Err: &panicError{value: value}, Status: checks.StatusFailing}
+		}
+	}()
+
+	check, ok := t.check.(checks.ContextCheck)
+	if !ok {
+		details, err := t.check.Execute()
+		return checks.ExecutionResult{Details: details, Err: err}
+	}
+
+	outcome = check.ExecuteWithContext(ctx)
+	if outcome.Err != nil {
+		outcome.Status = checks.StatusFailing
+	} else if outcome.Status == checks.StatusFailing {
+		outcome.Err = errors.Errorf("check reported a failing status")
+	}
+	return outcome
 }
@@ -0,0 +1,127 @@
+package checks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClockSyncStatus is the synchronization status of the system clock, as reported by the kernel or a time daemon.
+type ClockSyncStatus struct {
+	// Synchronized is true when the clock is synchronized to a time source.
+	Synchronized bool
+	// EstimatedError is the estimated error of the clock.
+	EstimatedError time.Duration
+	// MaxError is the maximal error of the clock.
+	MaxError time.Duration
+}
+
+// ClockSyncSource reads the synchronization status of the system clock.
+// See AdjtimexClockSource for reading the status from the kernel (Linux only),
+// or plug in a source reading e.g. `chronyc tracking`.
+type ClockSyncSource func() (ClockSyncStatus, error)
+
+// ClockCheckConfig configures a check for the synchronization and monotonicity of the system clock.
+type ClockCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Source reads the clock synchronization status, defaults to AdjtimexClockSource.
+	Source ClockSyncSource
+	// MaxEstimatedError is the maximal estimated clock error allowed, defaults to "100ms".
+	MaxEstimatedError time.Duration
+	// MaxStep is optional; when defined, the check fails when the wall clock was stepped (e.g. set manually)
+	// by more than MaxStep relative to the monotonic clock since the previous execution.
+	MaxStep time.Duration
+}
+
+// ClockDetails are the details reported by the clock check.
+type ClockDetails struct {
+	Synchronized   bool   `json:"synchronized"`
+	EstimatedError string `json:"estimated_error"`
+	MaxError       string `json:"max_error"`
+	// Step is the wall clock step observed since the previous execution, when MaxStep is defined.
+	Step string `json:"step,omitempty"`
+}
+
+type clockCheck struct {
+	config *ClockCheckConfig
+	// wall reads the wall clock, and mono reads the monotonic clock
+	wall func() time.Time
+	mono func() time.Duration
+
+	lock     sync.Mutex
+	sampled  bool
+	lastWall time.Time
+	lastMono time.Duration
+}
+
+// NewClockCheck creates a new clock check defined by the given config.
+// The check fails when the clock isn't synchronized, or its estimated error exceeds the configured threshold.
+func NewClockCheck(config ClockCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.MaxEstimatedError < 0 || config.MaxStep < 0 {
+		return nil, errors.Errorf("MaxEstimatedError and MaxStep must not be negative")
+	}
+	if config.Source == nil {
+		config.Source = AdjtimexClockSource
+	}
+	if config.MaxEstimatedError == 0 {
+		config.MaxEstimatedError = 100 * time.Millisecond
+	}
+
+	start := time.Now()
+	return &clockCheck{
+		config: &config,
+		wall:   func() time.Time { return time.Now().Round(0) },
+		mono:   func() time.Duration { return time.Since(start) },
+	}, nil
+}
+
+func (check *clockCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *clockCheck) Execute() (details interface{}, err error) {
+	status, err := check.config.Source()
+	if err != nil {
+		return nil, errors.Errorf("failed to read clock status: %v", err)
+	}
+
+	result := ClockDetails{
+		Synchronized:   status.Synchronized,
+		EstimatedError: status.EstimatedError.String(),
+		MaxError:       status.MaxError.String(),
+	}
+	if check.config.MaxStep > 0 {
+		step := check.step()
+		result.Step = step.String()
+		if step > check.config.MaxStep || step < -check.config.MaxStep {
+			return result, errors.Errorf("wall clock was stepped by %v", step)
+		}
+	}
+	if !status.Synchronized {
+		return result, errors.Errorf("clock is not synchronized")
+	}
+	if status.EstimatedError > check.config.MaxEstimatedError {
+		return result, errors.Errorf("estimated clock error %v exceeds %v", status.EstimatedError, check.config.MaxEstimatedError)
+	}
+	return result, nil
+}
+
+// step returns the difference between the wall clock and the monotonic clock elapsed times since the previous call
+func (check *clockCheck) step() time.Duration {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	wall, mono := check.wall(), check.mono()
+	var step time.Duration
+	if check.sampled {
+		step = wall.Sub(check.lastWall) - (mono - check.lastMono)
+	}
+	check.sampled, check.lastWall, check.lastMono = true, wall, mono
+	return step
+}
@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"syscall"
+	"time"
+)
+
+// the adjtimex clock state and status flag reported for an unsynchronized clock, see adjtimex(2)
+const (
+	adjtimexTimeError = 5
+	adjtimexStaUnsync = 0x0040
+)
+
+// AdjtimexClockSource reads the clock synchronization status from the kernel, using adjtimex(2).
+// The kernel is kept up to date by time daemons such as ntpd, chronyd and systemd-timesyncd.
+func AdjtimexClockSource() (ClockSyncStatus, error) {
+	var buf syscall.Timex
+	state, err := syscall.Adjtimex(&buf)
+	if err != nil {
+		return ClockSyncStatus{}, err
+	}
+	return ClockSyncStatus{
+		Synchronized:   state != adjtimexTimeError && buf.Status&adjtimexStaUnsync == 0,
+		EstimatedError: time.Duration(buf.Esterror) * time.Microsecond,
+		MaxError:       time.Duration(buf.Maxerror) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import (
+	"github.com/pkg/errors"
+)
+
+// AdjtimexClockSource reads the clock synchronization status from the kernel, using adjtimex(2).
+// adjtimex is only available on Linux, so on other platforms it always fails.
+func AdjtimexClockSource() (ClockSyncStatus, error) {
+	return ClockSyncStatus{}, errors.New("adjtimex is not supported on this platform")
+}
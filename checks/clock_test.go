@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClockCheckRequiredFields(t *testing.T) {
+	check, err := NewClockCheck(ClockCheckConfig{})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewClockCheck(ClockCheckConfig{CheckName: "clock", MaxStep: -time.Second})
+	assert.Nil(t, check, "negative MaxStep should yield nil check")
+	assert.Error(t, err, "negative MaxStep should yield error")
+}
+
+func TestClockCheck(t *testing.T) {
+	var status ClockSyncStatus
+	var sourceErr error
+	check, err := NewClockCheck(ClockCheckConfig{
+		CheckName: "clock.check",
+		Source: func() (ClockSyncStatus, error) {
+			return status, sourceErr
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "clock.check", check.Name(), "check name")
+
+	status = ClockSyncStatus{Synchronized: true, EstimatedError: 2 * time.Millisecond, MaxError: 50 * time.Millisecond}
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, ClockDetails{Synchronized: true, EstimatedError: "2ms", MaxError: "50ms"}, details)
+
+	status.EstimatedError = 150 * time.Millisecond
+	_, err = check.Execute()
+	assert.EqualError(t, err, "estimated clock error 150ms exceeds 100ms")
+
+	status.Synchronized = false
+	_, err = check.Execute()
+	assert.EqualError(t, err, "clock is not synchronized")
+
+	sourceErr = errors.New("operation not permitted")
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to read clock status: operation not permitted")
+}
+
+func TestClockCheck_step(t *testing.T) {
+	check, err := NewClockCheck(ClockCheckConfig{
+		CheckName: "clock.check",
+		Source: func() (ClockSyncStatus, error) {
+			return ClockSyncStatus{Synchronized: true}, nil
+		},
+		MaxStep: time.Second,
+	})
+	assert.NoError(t, err)
+
+	wall := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mono time.Duration
+	check.(*clockCheck).wall = func() time.Time { return wall }
+	check.(*clockCheck).mono = func() time.Duration { return mono }
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "0s", details.(ClockDetails).Step)
+
+	wall, mono = wall.Add(10*time.Second), mono+10*time.Second
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "0s", details.(ClockDetails).Step)
+
+	wall, mono = wall.Add(-50*time.Second), mono+10*time.Second
+	details, err = check.Execute()
+	assert.EqualError(t, err, "wall clock was stepped by -1m0s")
+	assert.Equal(t, "-1m0s", details.(ClockDetails).Step)
+}
+
+func TestAdjtimexClockSource(t *testing.T) {
+	_, err := AdjtimexClockSource()
+	if runtime.GOOS == "linux" {
+		assert.NoError(t, err, "adjtimex should be readable without privileges")
+	} else {
+		assert.Error(t, err)
+	}
+}
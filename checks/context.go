@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContextCheck is an optional interface of checks, which are executed with a context, and report a rich ExecutionResult:
+// checks implementing it may measure their own latency (e.g. of the probed dependency only), and report a degraded status.
+// Their ExecuteWithContext() is called instead of Execute().
+type ContextCheck interface {
+	Check
+	// ExecuteWithContext runs a single time check. The context is done once the check execution timed out.
+	ExecuteWithContext(ctx context.Context) ExecutionResult
+}
+
+// ExecutionResult is the outcome of a ContextCheck execution
+type ExecutionResult struct {
+	// Details is an optional details object
+	Details interface{}
+	// Err is the failure of the check; a non nil Err always fails the check, regardless of the Status
+	Err error
+	// Latency is the latency measured by the check; when zero, the execution duration measured by the framework is reported
+	Latency time.Duration
+	// Timings is an optional breakdown of the execution duration by phase (e.g. TimingDNS, TimingConnect),
+	// which speeds up the triage of latency issues
+	Timings map[string]time.Duration
+	// Status is the status of the check; defaults to StatusPassing
+	Status Status
+}
+
+// Status is the status reported by a check in its ExecutionResult
+type Status int
+
+const (
+	// StatusPassing checks pass
+	StatusPassing Status = iota
+	// StatusDegraded checks pass, but report a degraded service, e.g. a high latency or a reduced capacity
+	StatusDegraded
+	// StatusFailing checks fail, even when no Err is reported
+	StatusFailing
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPassing:
+		return "Passing"
+	case StatusDegraded:
+		return "Degraded"
+	case StatusFailing:
+		return "Failing"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
@@ -0,0 +1,14 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusString(t *testing.T) {
+	assert.Equal(t, "Passing", StatusPassing.String())
+	assert.Equal(t, "Degraded", StatusDegraded.String())
+	assert.Equal(t, "Failing", StatusFailing.String())
+	assert.Equal(t, "Status(7)", Status(7).String())
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// JSONValidator validates a decoded JSON document, e.g. against a JSON schema.
+// Implementations are usually thin adapters over a JSON schema library.
+type JSONValidator interface {
+	Validate(document interface{}) error
+}
+
+// JSONValidatorFunc type is an adapter to allow the use of ordinary functions as JSONValidators.
+type JSONValidatorFunc func(document interface{}) error
+
+// Validate calls f(document).
+func (f JSONValidatorFunc) Validate(document interface{}) error {
+	return f(document)
+}
+
+// ContractCheckConfig configures a check that performs a small request against a dependency,
+// and validates its JSON response, so breaking upstream deploys are caught early.
+type ContractCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Endpoint is required, and holds the HTTP settings of the request (URL, method, body, expected status,
+	// client, timeout and request options, e.g. WithBearerToken).
+	// Its `CheckName` and `ExpectedBody` fields are ignored.
+	Endpoint HTTPCheckConfig
+	// Validator is required, and validates the decoded response body.
+	Validator JSONValidator
+	// MaxBodySize is the maximal response body size to read, defaults to 1MiB.
+	MaxBodySize int64
+}
+
+type contractCheck struct {
+	config *ContractCheckConfig
+	probe  *httpCheck
+}
+
+// NewContractCheck creates a new API contract check defined by the given config.
+// The check fails when the response status is unexpected, the body isn't valid JSON, or the validator rejects it.
+func NewContractCheck(config ContractCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Validator == nil {
+		return nil, errors.Errorf("Validator must not be nil")
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 1 << 20
+	}
+
+	endpoint := config.Endpoint
+	endpoint.CheckName = config.CheckName
+	endpoint.ExpectedBody = ""
+	probe, err := NewHTTPCheck(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contractCheck{config: &config, probe: probe.(*httpCheck)}, nil
+}
+
+func (check *contractCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *contractCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+// ExecuteWithContext executes the check, reporting the timings of the request, as the HTTP check does
+func (check *contractCheck) ExecuteWithContext(ctx context.Context) ExecutionResult {
+	ctx, timings := withHTTPTimings(ctx)
+	details, err := check.execute(ctx)
+	return ExecutionResult{Details: details, Err: err, Timings: timings.get()}
+}
+
+func (check *contractCheck) execute(ctx context.Context) (details interface{}, err error) {
+	details = check.probe.config.URL
+	resp, err := check.probe.fetchURL(ctx)
+	if err != nil {
+		return details, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != check.probe.config.ExpectedStatus {
+		return details, errors.Errorf("unexpected status code: '%v' expected: '%v'",
+			resp.StatusCode, check.probe.config.ExpectedStatus)
+	}
+
+	var document interface{}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, check.config.MaxBodySize)).Decode(&document); err != nil {
+		return details, errors.Errorf("failed to parse response body: %v", err)
+	}
+	if err = check.config.Validator.Validate(document); err != nil {
+		return details, errors.Errorf("response does not match the contract: %v", err)
+	}
+	return check.probe.successDetails, nil
+}
+
+// WithBearerToken returns a RequestOption that authenticates the request using a bearer token.
+// The token is provided on each request, so it may be refreshed between executions.
+func WithBearerToken(token func() string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token())
+	}
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContractCheckRequiredFields(t *testing.T) {
+	validator := JSONValidatorFunc(func(interface{}) error { return nil })
+	check, err := NewContractCheck(ContractCheckConfig{
+		Endpoint:  HTTPCheckConfig{URL: "http://example.org"},
+		Validator: validator,
+	})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewContractCheck(ContractCheckConfig{
+		CheckName: "contract",
+		Endpoint:  HTTPCheckConfig{URL: "http://example.org"},
+	})
+	assert.Nil(t, check, "nil Validator should yield nil check")
+	assert.Error(t, err, "nil Validator should yield error")
+
+	check, err = NewContractCheck(ContractCheckConfig{
+		CheckName: "contract",
+		Validator: validator,
+	})
+	assert.Nil(t, check, "nil URL should yield nil check")
+	assert.Error(t, err, "nil URL should yield error")
+}
+
+func TestContractCheck(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer s3cr3t" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = rw.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	requireID := JSONValidatorFunc(func(document interface{}) error {
+		user, ok := document.(map[string]interface{})
+		if !ok {
+			return errors.New("expected an object")
+		}
+		if _, ok := user["id"].(string); !ok {
+			return errors.New("id must be a string")
+		}
+		return nil
+	})
+
+	check, err := NewContractCheck(ContractCheckConfig{
+		CheckName: "users.contract",
+		Endpoint: HTTPCheckConfig{
+			URL:     server.URL + "/users/health-probe",
+			Options: []RequestOption{WithBearerToken(func() string { return "s3cr3t" })},
+		},
+		Validator: requireID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "users.contract", check.Name(), "check name")
+
+	body = `{"id":"health-probe","name":"probe"}`
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "URL ["+server.URL+"/users/health-probe] is accessible", details)
+
+	body = `{"id":42}`
+	_, err = check.Execute()
+	assert.EqualError(t, err, "response does not match the contract: id must be a string")
+
+	body = `not json`
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to parse response body: invalid character 'o' in literal null (expecting 'u')")
+
+	check, _ = NewContractCheck(ContractCheckConfig{
+		CheckName: "users.contract",
+		Endpoint:  HTTPCheckConfig{URL: server.URL},
+		Validator: requireID,
+	})
+	_, err = check.Execute()
+	assert.EqualError(t, err, "unexpected status code: '401' expected: '200'")
+}
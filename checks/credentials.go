@@ -0,0 +1,257 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultAWSMetadataEndpoint is the address of the EC2 instance metadata service.
+	DefaultAWSMetadataEndpoint = "http://169.254.169.254"
+	// DefaultGCPMetadataEndpoint is the address of the GCE metadata server.
+	DefaultGCPMetadataEndpoint = "http://metadata.google.internal"
+	// AWSWebIdentityTokenFileEnv is the environment variable pointing at the IRSA (IAM roles for service accounts) token file.
+	AWSWebIdentityTokenFileEnv = "AWS_WEB_IDENTITY_TOKEN_FILE"
+)
+
+// Credentials describes the cloud credentials found by a CredentialsSource.
+type Credentials struct {
+	// Source describes where the credentials were found, e.g. the IAM role or the token file.
+	Source string
+	// Expiration is the expiration time of the credentials, zero when they don't expire.
+	Expiration time.Time
+}
+
+// CredentialsSource fetches the current cloud credentials.
+// See AWSMetadataCredentialsSource, GCPMetadataCredentialsSource and TokenFileCredentialsSource for the built-in sources,
+// or plug in a source wrapping an SDK credentials provider.
+type CredentialsSource func(ctx context.Context) (Credentials, error)
+
+// CredentialsCheckConfig configures a check for the presence and freshness of cloud credentials.
+type CredentialsCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Source is required, and is used for fetching the credentials.
+	Source CredentialsSource
+	// MinValidity is the minimal remaining validity of the credentials, defaults to "5m".
+	// Credentials expiring sooner than that are expected to be refreshed, so failing to refresh them fails the check.
+	MinValidity time.Duration
+	// Timeout is the timeout used for fetching the credentials, defaults to "1s".
+	Timeout time.Duration
+}
+
+// CredentialsDetails are the details reported by the credentials check.
+type CredentialsDetails struct {
+	// Source describes where the credentials were found.
+	Source string `json:"source,omitempty"`
+	// Expiration is the expiration time of the credentials, in RFC 3339 format, when they expire.
+	Expiration string `json:"expiration,omitempty"`
+	// ExpiresIn is the remaining validity of the credentials, when they expire.
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+type credentialsCheck struct {
+	config *CredentialsCheckConfig
+	now    func() time.Time
+}
+
+// NewCredentialsCheck creates a new cloud credentials check defined by the given config.
+// The check fails when the credentials can't be fetched, or when they expire within `MinValidity`.
+func NewCredentialsCheck(config CredentialsCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Source == nil {
+		return nil, errors.Errorf("Source must not be nil")
+	}
+	if config.MinValidity == 0 {
+		config.MinValidity = 5 * time.Minute
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &credentialsCheck{config: &config, now: time.Now}, nil
+}
+
+func (check *credentialsCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *credentialsCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	creds, err := check.config.Source(ctx)
+	result := CredentialsDetails{Source: creds.Source}
+	if err != nil {
+		return result, errors.Errorf("failed to fetch credentials: %v", err)
+	}
+	if creds.Expiration.IsZero() {
+		return result, nil
+	}
+
+	expiresIn := creds.Expiration.Sub(check.now()).Truncate(time.Second)
+	result.Expiration = creds.Expiration.UTC().Format(time.RFC3339)
+	result.ExpiresIn = expiresIn.String()
+	if expiresIn <= 0 {
+		return result, errors.Errorf("credentials expired at %s", result.Expiration)
+	}
+	if expiresIn < check.config.MinValidity {
+		return result, errors.Errorf("credentials expire in %v, less than %v", expiresIn, check.config.MinValidity)
+	}
+	return result, nil
+}
+
+// TokenFileCredentialsSource returns a source reading a projected service account token (a JWT) from the given file,
+// and reporting its expiration from the token's "exp" claim.
+// When path is empty, the file pointed at by the AWS_WEB_IDENTITY_TOKEN_FILE environment variable (i.e. IRSA) is used.
+func TokenFileCredentialsSource(path string) CredentialsSource {
+	return func(ctx context.Context) (Credentials, error) {
+		file := path
+		if file == "" {
+			file = os.Getenv(AWSWebIdentityTokenFileEnv)
+		}
+		if file == "" {
+			return Credentials{}, errors.Errorf("%s is not set", AWSWebIdentityTokenFileEnv)
+		}
+		creds := Credentials{Source: file}
+		token, err := ioutil.ReadFile(file)
+		if err != nil {
+			return creds, err
+		}
+		creds.Expiration, err = jwtExpiration(string(bytes.TrimSpace(token)))
+		return creds, err
+	}
+}
+
+func jwtExpiration(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.Errorf("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, errors.Errorf("failed to decode token claims: %v", err)
+	}
+	var claims struct {
+		Expiration int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, errors.Errorf("failed to parse token claims: %v", err)
+	}
+	if claims.Expiration == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Expiration, 0), nil
+}
+
+// AWSMetadataCredentialsSource returns a source fetching the instance profile credentials from the EC2 instance metadata
+// service at the given endpoint (defaults to DefaultAWSMetadataEndpoint), using an IMDSv2 session token.
+// When client is nil, http.DefaultClient is used.
+func AWSMetadataCredentialsSource(endpoint string, client *http.Client) CredentialsSource {
+	if endpoint == "" {
+		endpoint = DefaultAWSMetadataEndpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (Credentials, error) {
+		token, err := metadataRequest(ctx, client, http.MethodPut, endpoint+"/latest/api/token",
+			map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+		if err != nil {
+			return Credentials{}, errors.Errorf("failed to fetch metadata token: %v", err)
+		}
+		headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+		roles, err := metadataRequest(ctx, client, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/", headers)
+		if err != nil {
+			return Credentials{}, errors.Errorf("failed to fetch instance profile role: %v", err)
+		}
+		role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+		if role == "" {
+			return Credentials{}, errors.Errorf("no instance profile role is attached")
+		}
+
+		creds := Credentials{Source: role}
+		body, err := metadataRequest(ctx, client, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/"+role, headers)
+		if err != nil {
+			return creds, errors.Errorf("failed to fetch role credentials: %v", err)
+		}
+		var response struct {
+			Code       string    `json:"Code"`
+			Expiration time.Time `json:"Expiration"`
+		}
+		if err = json.Unmarshal(body, &response); err != nil {
+			return creds, errors.Errorf("failed to parse role credentials: %v", err)
+		}
+		if response.Code != "Success" {
+			return creds, errors.Errorf("role credentials code: '%s'", response.Code)
+		}
+		creds.Expiration = response.Expiration
+		return creds, nil
+	}
+}
+
+// GCPMetadataCredentialsSource returns a source fetching an access token of the default service account from the GCE metadata
+// server at the given endpoint (defaults to DefaultGCPMetadataEndpoint).
+// When client is nil, http.DefaultClient is used.
+func GCPMetadataCredentialsSource(endpoint string, client *http.Client) CredentialsSource {
+	if endpoint == "" {
+		endpoint = DefaultGCPMetadataEndpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (Credentials, error) {
+		creds := Credentials{Source: "default service account"}
+		body, err := metadataRequest(ctx, client, http.MethodGet, endpoint+"/computeMetadata/v1/instance/service-accounts/default/token",
+			map[string]string{"Metadata-Flavor": "Google"})
+		if err != nil {
+			return creds, errors.Errorf("failed to fetch access token: %v", err)
+		}
+		var response struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err = json.Unmarshal(body, &response); err != nil {
+			return creds, errors.Errorf("failed to parse access token: %v", err)
+		}
+		if response.AccessToken == "" {
+			return creds, errors.Errorf("empty access token")
+		}
+		creds.Expiration = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+		return creds, nil
+	}
+}
+
+func metadataRequest(ctx context.Context, client *http.Client, method, target string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code: '%v'", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,154 @@
+package checks
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCredentialsCheckRequiredFields(t *testing.T) {
+	check, err := NewCredentialsCheck(CredentialsCheckConfig{Source: staticCredentials(Credentials{})})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewCredentialsCheck(CredentialsCheckConfig{CheckName: "meh"})
+	assert.Nil(t, check, "nil Source should yield nil check")
+	assert.Error(t, err, "nil Source should yield error")
+}
+
+func TestCredentialsCheck(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	creds := Credentials{Source: "orders-role", Expiration: now.Add(time.Hour)}
+	check, err := NewCredentialsCheck(CredentialsCheckConfig{
+		CheckName: "aws.credentials",
+		Source:    func(ctx context.Context) (Credentials, error) { return creds, nil },
+	})
+	assert.NoError(t, err)
+	check.(*credentialsCheck).now = func() time.Time { return now }
+	assert.Equal(t, "aws.credentials", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "fresh credentials should pass")
+	assert.Equal(t, CredentialsDetails{Source: "orders-role", Expiration: "2020-01-01T13:00:00Z", ExpiresIn: "1h0m0s"}, details)
+
+	creds.Expiration = now.Add(time.Minute)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "credentials expire in 1m0s, less than 5m0s")
+
+	creds.Expiration = now.Add(-time.Minute)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "credentials expired at 2020-01-01T11:59:00Z")
+
+	creds.Expiration = time.Time{}
+	details, err = check.Execute()
+	assert.NoError(t, err, "non expiring credentials should pass")
+	assert.Equal(t, CredentialsDetails{Source: "orders-role"}, details)
+
+	check, err = NewCredentialsCheck(CredentialsCheckConfig{
+		CheckName: "aws.credentials",
+		Source: func(ctx context.Context) (Credentials, error) {
+			return Credentials{}, errors.New("no instance profile role is attached")
+		},
+	})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to fetch credentials: no instance profile role is attached")
+}
+
+func TestTokenFileCredentialsSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "token")
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":["sts.amazonaws.com"],"exp":1577883600}`))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("eyJhbGciOiJSUzI1NiJ9."+claims+".c2lnbmF0dXJl\n"), 0600))
+
+	creds, err := TokenFileCredentialsSource(path)(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Credentials{Source: path, Expiration: time.Unix(1577883600, 0)}, creds)
+
+	old, set := os.LookupEnv(AWSWebIdentityTokenFileEnv)
+	assert.NoError(t, os.Setenv(AWSWebIdentityTokenFileEnv, path))
+	defer func() {
+		if set {
+			_ = os.Setenv(AWSWebIdentityTokenFileEnv, old)
+		} else {
+			_ = os.Unsetenv(AWSWebIdentityTokenFileEnv)
+		}
+	}()
+	creds, err = TokenFileCredentialsSource("")(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, path, creds.Source, "token file should be taken from the environment")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not-a-token"), 0600))
+	_, err = TokenFileCredentialsSource(path)(context.Background())
+	assert.EqualError(t, err, "token is not a JWT")
+
+	_, err = TokenFileCredentialsSource(filepath.Join(dir, "missing"))(context.Background())
+	assert.Error(t, err, "missing token file should fail")
+}
+
+func TestAWSMetadataCredentialsSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/latest/api/token":
+			assert.Equal(t, http.MethodPut, req.Method)
+			assert.NotEmpty(t, req.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+			_, _ = rw.Write([]byte("session-token"))
+			return
+		}
+		if req.Header.Get("X-aws-ec2-metadata-token") != "session-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch req.URL.Path {
+		case "/latest/meta-data/iam/security-credentials/":
+			_, _ = rw.Write([]byte("orders-role\n"))
+		case "/latest/meta-data/iam/security-credentials/orders-role":
+			_, _ = rw.Write([]byte(`{"Code":"Success","AccessKeyId":"AKIA","Expiration":"2020-01-01T13:00:00Z"}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	creds, err := AWSMetadataCredentialsSource(server.URL, nil)(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "orders-role", creds.Source)
+	assert.True(t, time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC).Equal(creds.Expiration), "expiration")
+
+	_, err = AWSMetadataCredentialsSource(server.URL+"/missing", nil)(context.Background())
+	assert.EqualError(t, err, "failed to fetch metadata token: unexpected status code: '401'")
+}
+
+func TestGCPMetadataCredentialsSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Metadata-Flavor") != "Google" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = rw.Write([]byte(`{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	creds, err := GCPMetadataCredentialsSource(server.URL, server.Client())(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "default service account", creds.Source)
+	assert.WithinDuration(t, time.Now().Add(3599*time.Second), creds.Expiration, time.Minute)
+}
+
+func staticCredentials(creds Credentials) CredentialsSource {
+	return func(ctx context.Context) (Credentials, error) {
+		return creds, nil
+	}
+}
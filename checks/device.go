@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DeviceProber lists the available devices, e.g. by querying NVML or a device plugin.
+type DeviceProber func(ctx context.Context) (devices []string, err error)
+
+// DeviceCheckConfig configures a check that verifies the expected devices (e.g. GPUs or other accelerators) are visible.
+type DeviceCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Paths are device file glob patterns, e.g. "/dev/nvidia[0-9]*"; each pattern must match at least one device.
+	// Either Paths or Prober is required.
+	Paths []string
+	// Prober lists the available devices, and can't be used together with Paths.
+	Prober DeviceProber
+	// MinDevices is the minimal number of devices required for the check to pass, defaults to `1`.
+	MinDevices int
+	// Timeout is the timeout used for probing the devices using Prober, defaults to "1s".
+	Timeout time.Duration
+}
+
+type deviceCheck struct {
+	config *DeviceCheckConfig
+}
+
+// NewDeviceCheck creates a new device availability check defined by the given config.
+// The check reports the found devices in its details.
+func NewDeviceCheck(config DeviceCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if len(config.Paths) == 0 && config.Prober == nil {
+		return nil, errors.Errorf("either Paths or Prober must be defined")
+	}
+	if len(config.Paths) > 0 && config.Prober != nil {
+		return nil, errors.Errorf("Paths must not be used together with Prober")
+	}
+	for _, pattern := range config.Paths {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid device path pattern [%s]", pattern)
+		}
+	}
+	if config.MinDevices < 0 {
+		return nil, errors.Errorf("MinDevices must not be negative")
+	}
+	if config.MinDevices == 0 {
+		config.MinDevices = 1
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &deviceCheck{config: &config}, nil
+}
+
+func (check *deviceCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *deviceCheck) Execute() (details interface{}, err error) {
+	devices, err := check.devices()
+	if err != nil {
+		return devices, err
+	}
+	if len(devices) < check.config.MinDevices {
+		return devices, errors.Errorf("found %d devices, but requires at least %d", len(devices), check.config.MinDevices)
+	}
+	return devices, nil
+}
+
+func (check *deviceCheck) devices() ([]string, error) {
+	if check.config.Prober != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+		defer cancel()
+
+		devices, err := check.config.Prober(ctx)
+		if err != nil {
+			return devices, errors.Errorf("failed to probe devices: %v", err)
+		}
+		return devices, nil
+	}
+
+	found := make(map[string]bool)
+	var missing []string
+	for _, pattern := range check.config.Paths {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(matches) == 0 {
+			missing = append(missing, pattern)
+		}
+		for _, match := range matches {
+			found[match] = true
+		}
+	}
+
+	devices := make([]string, 0, len(found))
+	for device := range found {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	if len(missing) > 0 {
+		return devices, errors.Errorf("no devices found for: %s", strings.Join(missing, ", "))
+	}
+	return devices, nil
+}
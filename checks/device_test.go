@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeviceCheckRequiredFields(t *testing.T) {
+	check, err := NewDeviceCheck(DeviceCheckConfig{Paths: []string{"/dev/nvidia0"}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewDeviceCheck(DeviceCheckConfig{CheckName: "gpu"})
+	assert.Nil(t, check, "no Paths or Prober should yield nil check")
+	assert.Error(t, err, "no Paths or Prober should yield error")
+
+	check, err = NewDeviceCheck(DeviceCheckConfig{
+		CheckName: "gpu",
+		Paths:     []string{"/dev/nvidia0"},
+		Prober:    func(context.Context) ([]string, error) { return nil, nil },
+	})
+	assert.Nil(t, check, "both Paths and Prober should yield nil check")
+	assert.Error(t, err, "both Paths and Prober should yield error")
+
+	check, err = NewDeviceCheck(DeviceCheckConfig{CheckName: "gpu", Paths: []string{"/dev/nvidia["}})
+	assert.Nil(t, check, "invalid pattern should yield nil check")
+	assert.Error(t, err, "invalid pattern should yield error")
+}
+
+func TestDeviceCheck_paths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devices")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	for _, name := range []string{"nvidia0", "nvidia1", "nvidiactl"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	check, err := NewDeviceCheck(DeviceCheckConfig{
+		CheckName:  "gpu.check",
+		Paths:      []string{filepath.Join(dir, "nvidia[0-9]*"), filepath.Join(dir, "nvidiactl")},
+		MinDevices: 3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "gpu.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "nvidia0"), filepath.Join(dir, "nvidia1"), filepath.Join(dir, "nvidiactl")}, details)
+
+	check, _ = NewDeviceCheck(DeviceCheckConfig{
+		CheckName:  "gpu.check",
+		Paths:      []string{filepath.Join(dir, "nvidia[0-9]*")},
+		MinDevices: 4,
+	})
+	_, err = check.Execute()
+	assert.EqualError(t, err, "found 2 devices, but requires at least 4")
+
+	check, _ = NewDeviceCheck(DeviceCheckConfig{
+		CheckName: "gpu.check",
+		Paths:     []string{filepath.Join(dir, "nvidia0"), filepath.Join(dir, "nvidia-uvm")},
+	})
+	_, err = check.Execute()
+	assert.EqualError(t, err, "no devices found for: "+filepath.Join(dir, "nvidia-uvm"))
+}
+
+func TestDeviceCheck_prober(t *testing.T) {
+	var devices []string
+	var probeErr error
+	check, err := NewDeviceCheck(DeviceCheckConfig{
+		CheckName: "gpu.check",
+		Prober: func(ctx context.Context) ([]string, error) {
+			return devices, probeErr
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "found 0 devices, but requires at least 1")
+
+	devices = []string{"GPU-8d3c2f1e"}
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, devices, details)
+
+	probeErr = errors.New("NVML not loaded")
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to probe devices: NVML not loaded")
+}
@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ContextDialer opens connections, e.g. a *net.Dialer, a SOCKS proxy dialer (see NewSOCKS5Dialer), an SSH client tunnel,
+// or a service mesh dialer, so the network checks may reach their targets the same way the application traffic does.
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// DialContext calls f(ctx, network, addr), so a DialContextFunc is a ContextDialer.
+func (f DialContextFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// SOCKS5Auth holds the username/password credentials of a SOCKS5 proxy (RFC 1929)
+type SOCKS5Auth struct {
+	Username string
+	Password string
+}
+
+const (
+	socks5Version         = 5
+	socks5NoAuth          = 0
+	socks5PasswordAuth    = 2
+	socks5NoAcceptable    = 0xff
+	socks5PasswordVersion = 1
+	socks5Connect         = 1
+	socks5IPv4            = 1
+	socks5Domain          = 3
+	socks5IPv6            = 4
+)
+
+type socks5Dialer struct {
+	proxy   string
+	auth    *SOCKS5Auth
+	forward ContextDialer
+}
+
+// NewSOCKS5Dialer returns a ContextDialer that connects through the SOCKS5 proxy at the given address (host:port),
+// authenticating with auth when not nil. The proxy is dialed using forward, or a net.Dialer when forward is nil.
+// Host names are resolved by the proxy. Only TCP networks are supported.
+func NewSOCKS5Dialer(proxy string, auth *SOCKS5Auth, forward ContextDialer) ContextDialer {
+	if forward == nil {
+		forward = &net.Dialer{}
+	}
+	return &socks5Dialer{proxy: proxy, auth: auth, forward: forward}
+}
+
+func (d *socks5Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, errors.Errorf("unsupported SOCKS5 network %s", network)
+	}
+
+	conn, err := d.forward.DialContext(ctx, "tcp", d.proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial the SOCKS5 proxy %s", d.proxy)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := d.connect(conn, addr); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, "SOCKS5 proxy %s failed to connect to %s", d.proxy, addr)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// connect negotiates the authentication, and requests the proxy to connect to addr
+func (d *socks5Dialer) connect(conn net.Conn, addr string) error {
+	host, portValue, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return errors.Errorf("invalid port %s", portValue)
+	}
+
+	method := byte(socks5NoAuth)
+	if d.auth != nil {
+		method = socks5PasswordAuth
+	}
+	if _, err := conn.Write([]byte{socks5Version, 1, method}); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != socks5Version {
+		return errors.Errorf("unexpected SOCKS version %d", reply[0])
+	}
+	if reply[1] == socks5NoAcceptable || reply[1] != method {
+		return errors.New("no acceptable authentication method")
+	}
+	if d.auth != nil {
+		if err := d.authenticate(conn); err != nil {
+			return err
+		}
+	}
+
+	request := []byte{socks5Version, socks5Connect, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return errors.Errorf("host name %s is too long", host)
+		}
+		request = append(request, socks5Domain, byte(len(host)))
+		request = append(request, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		request = append(append(request, socks5IPv4), ip4...)
+	} else {
+		request = append(append(request, socks5IPv6), ip.To16()...)
+	}
+	request = append(request, 0, 0)
+	binary.BigEndian.PutUint16(request[len(request)-2:], uint16(port))
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	// the reply holds the version, status, reserved byte, and the bound address
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[1] != 0 {
+		return errors.Errorf("connect request rejected with status %d", header[1])
+	}
+	var boundLength int
+	switch header[3] {
+	case socks5IPv4:
+		boundLength = net.IPv4len
+	case socks5IPv6:
+		boundLength = net.IPv6len
+	case socks5Domain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return err
+		}
+		boundLength = int(length[0])
+	default:
+		return errors.Errorf("unexpected bound address type %d", header[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, boundLength+2))
+	return err
+}
+
+// authenticate negotiates the username/password authentication (RFC 1929)
+func (d *socks5Dialer) authenticate(conn net.Conn) error {
+	if len(d.auth.Username) > 255 || len(d.auth.Password) > 255 {
+		return errors.New("SOCKS5 credentials are too long")
+	}
+	request := []byte{socks5PasswordVersion, byte(len(d.auth.Username))}
+	request = append(request, d.auth.Username...)
+	request = append(request, byte(len(d.auth.Password)))
+	request = append(request, d.auth.Password...)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		return errors.New("authentication rejected")
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// socks5Proxy is a minimal SOCKS5 proxy, which records the requested addresses
+type socks5Proxy struct {
+	listener  net.Listener
+	auth      *SOCKS5Auth
+	lock      sync.Mutex
+	requested []string
+}
+
+func newSOCKS5Proxy(t *testing.T, auth *SOCKS5Auth) *socks5Proxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	proxy := &socks5Proxy{listener: listener, auth: auth}
+	go proxy.serve()
+	return proxy
+}
+
+func (p *socks5Proxy) serve() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *socks5Proxy) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	methods := make([]byte, header[1])
+	_, _ = io.ReadFull(conn, methods)
+	if p.auth == nil {
+		_, _ = conn.Write([]byte{5, 0})
+	} else {
+		_, _ = conn.Write([]byte{5, 2})
+		lengths := make([]byte, 2)
+		_, _ = io.ReadFull(conn, lengths)
+		username := make([]byte, lengths[1])
+		_, _ = io.ReadFull(conn, username)
+		_, _ = io.ReadFull(conn, lengths[:1])
+		password := make([]byte, lengths[0])
+		_, _ = io.ReadFull(conn, password)
+		if string(username) != p.auth.Username || string(password) != p.auth.Password {
+			_, _ = conn.Write([]byte{1, 1})
+			return
+		}
+		_, _ = conn.Write([]byte{1, 0})
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		_, _ = io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		_, _ = io.ReadFull(conn, length)
+		name := make([]byte, length[0])
+		_, _ = io.ReadFull(conn, name)
+		host = string(name)
+	}
+	port := make([]byte, 2)
+	_, _ = io.ReadFull(conn, port)
+	addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+	p.lock.Lock()
+	p.requested = append(p.requested, addr)
+	p.lock.Unlock()
+
+	target, err := net.Dial("tcp", addr)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer func() { _ = target.Close() }()
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+	go func() { _, _ = io.Copy(target, conn) }()
+	_, _ = io.Copy(conn, target)
+}
+
+func (p *socks5Proxy) requestedAddrs() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]string(nil), p.requested...)
+}
+
+func TestSOCKS5Dialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	proxy := newSOCKS5Proxy(t, nil)
+	defer func() { _ = proxy.listener.Close() }()
+	dialer := NewSOCKS5Dialer(proxy.listener.Addr().String(), nil, nil)
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Dialer: dialer})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.NoError(t, err)
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	assert.NoError(t, NewDialerPinger(dialer, "tcp", "localhost:"+port)(context.Background()))
+	assert.Equal(t, []string{server.Listener.Addr().String(), "localhost:" + port}, proxy.requestedAddrs(),
+		"host names are resolved by the proxy")
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_ = closed.Close()
+	err = NewDialerPinger(dialer, "tcp", closed.Addr().String())(context.Background())
+	assert.EqualError(t, err, "SOCKS5 proxy "+proxy.listener.Addr().String()+" failed to connect to "+closed.Addr().String()+
+		": connect request rejected with status 5")
+
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Dialer: dialer, DialContext: UnixSocketDialer("/tmp/meh.sock")})
+	assert.Error(t, err, "Dialer can't be used together with DialContext")
+}
+
+func TestSOCKS5Dialer_auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	proxy := newSOCKS5Proxy(t, &SOCKS5Auth{Username: "probe", Password: "secret"})
+	defer func() { _ = proxy.listener.Close() }()
+
+	ping := func(auth *SOCKS5Auth) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		dialer := NewSOCKS5Dialer(proxy.listener.Addr().String(), auth, nil)
+		return NewDialerPinger(dialer, "tcp", server.Listener.Addr().String())(ctx)
+	}
+	assert.NoError(t, ping(&SOCKS5Auth{Username: "probe", Password: "secret"}))
+	assert.Error(t, ping(&SOCKS5Auth{Username: "probe", Password: "wrong"}))
+	assert.Error(t, ping(nil), "no acceptable authentication method")
+}
@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TargetResolver resolves the current targets (e.g. "host:port" addresses) of a service.
+type TargetResolver func(ctx context.Context) (targets []string, err error)
+
+// TargetCheckFactory creates a check for a single resolved target.
+type TargetCheckFactory func(target string) (Check, error)
+
+// DiscoveryCheckConfig configures a check that resolves its targets before each execution,
+// so the check follows service discovery instead of hard-coded hostnames.
+type DiscoveryCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Resolver is required, and resolves the targets to check before each execution.
+	// See NewSRVResolver for resolving targets using DNS SRV records.
+	Resolver TargetResolver
+	// NewCheck is required, and creates the check executed against each one of the resolved targets.
+	NewCheck TargetCheckFactory
+	// ResolveTimeout is the timeout used for resolving the targets, defaults to "1s".
+	ResolveTimeout time.Duration
+	// Quorum is the fraction (0, 1] of targets that must pass for the check to pass, defaults to `1` (all targets).
+	Quorum float64
+}
+
+type discoveryCheck struct {
+	config *DiscoveryCheckConfig
+}
+
+// NewDiscoveryCheck creates a new check that resolves its targets on each execution, and checks each one of them.
+// The check fails when no targets are resolved, and reports each target outcome in its QuorumDetails.
+func NewDiscoveryCheck(config DiscoveryCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Resolver == nil {
+		return nil, errors.Errorf("Resolver must not be nil")
+	}
+	if config.NewCheck == nil {
+		return nil, errors.Errorf("NewCheck must not be nil")
+	}
+	if config.Quorum, err = validateQuorum(config.Quorum); err != nil {
+		return nil, err
+	}
+	if config.ResolveTimeout == 0 {
+		config.ResolveTimeout = time.Second
+	}
+
+	return &discoveryCheck{config: &config}, nil
+}
+
+func (check *discoveryCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *discoveryCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.ResolveTimeout)
+	defer cancel()
+
+	targets, err := check.config.Resolver(ctx)
+	if err != nil {
+		return nil, errors.Errorf("failed to resolve targets: %v", err)
+	}
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no targets were resolved")
+	}
+
+	probes := make(map[string]Check, len(targets))
+	for _, target := range targets {
+		probe, err := check.config.NewCheck(target)
+		if err != nil {
+			return nil, errors.Errorf("failed to create check for target [%s]: %v", target, err)
+		}
+		probes[target] = probe
+	}
+
+	return executeQuorum(probes, requiredForQuorum(check.config.Quorum, len(probes)))
+}
+
+// NewSRVResolver creates a TargetResolver that looks up the "host:port" targets of the given service using DNS SRV records.
+// The service, proto and name arguments are passed as is to `net.Resolver.LookupSRV()`.
+func NewSRVResolver(resolver *net.Resolver, service, proto, name string) TargetResolver {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return func(ctx context.Context) (targets []string, err error) {
+		_, records, err := resolver.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = make([]string, 0, len(records))
+		for _, r := range records {
+			targets = append(targets, net.JoinHostPort(strings.TrimSuffix(r.Target, "."), strconv.Itoa(int(r.Port))))
+		}
+		return targets, nil
+	}
+}
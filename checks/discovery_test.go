@@ -0,0 +1,95 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiscoveryCheckRequiredFields(t *testing.T) {
+	resolver := staticResolver("a")
+	factory := func(target string) (Check, error) { return &CustomCheck{CheckName: target}, nil }
+
+	check, err := NewDiscoveryCheck(DiscoveryCheckConfig{Resolver: resolver, NewCheck: factory})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewDiscoveryCheck(DiscoveryCheckConfig{CheckName: "meh", NewCheck: factory})
+	assert.Nil(t, check, "nil Resolver should yield nil check")
+	assert.Error(t, err, "nil Resolver should yield error")
+
+	check, err = NewDiscoveryCheck(DiscoveryCheckConfig{CheckName: "meh", Resolver: resolver})
+	assert.Nil(t, check, "nil NewCheck should yield nil check")
+	assert.Error(t, err, "nil NewCheck should yield error")
+}
+
+func TestNewDiscoveryCheck(t *testing.T) {
+	targets := []string{"a:80", "b:80"}
+	check, err := NewDiscoveryCheck(DiscoveryCheckConfig{
+		CheckName: "discovered.check",
+		Resolver: func(ctx context.Context) ([]string, error) {
+			return targets, nil
+		},
+		NewCheck: func(target string) (Check, error) {
+			return &CustomCheck{
+				CheckName: target,
+				CheckFunc: func() (details interface{}, err error) {
+					if target == "b:80" {
+						return nil, errors.New("unreachable")
+					}
+					return nil, nil
+				},
+			}, nil
+		},
+		Quorum: 0.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "discovered.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass with half of the targets up")
+	assert.Equal(t, QuorumDetails{
+		Passed:   1,
+		Required: 1,
+		Targets:  map[string]string{"a:80": "OK", "b:80": "unreachable"},
+	}, details)
+
+	// targets are resolved on each execution
+	targets = []string{"b:80"}
+	details, err = check.Execute()
+	assert.Error(t, err, "check should fail once the passing target is gone")
+	assert.Equal(t, QuorumDetails{
+		Passed:   0,
+		Required: 1,
+		Targets:  map[string]string{"b:80": "unreachable"},
+	}, details)
+
+	targets = nil
+	_, err = check.Execute()
+	assert.EqualError(t, err, "no targets were resolved")
+}
+
+func TestNewDiscoveryCheck_resolveError(t *testing.T) {
+	check, err := NewDiscoveryCheck(DiscoveryCheckConfig{
+		CheckName: "discovered.check",
+		Resolver: func(ctx context.Context) ([]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		NewCheck:       func(target string) (Check, error) { return &CustomCheck{}, nil },
+		ResolveTimeout: time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to resolve targets: context deadline exceeded")
+}
+
+func staticResolver(targets ...string) TargetResolver {
+	return func(ctx context.Context) ([]string, error) {
+		return targets, nil
+	}
+}
@@ -2,11 +2,8 @@ package checks
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
 // NewHostResolveCheck returns a Check that makes sure the provided host can resolve
@@ -15,12 +12,28 @@ func NewHostResolveCheck(host string, timeout time.Duration, minRequiredResults
 	return NewResolveCheck(NewHostLookup(nil), host, timeout, minRequiredResults)
 }
 
+// NewHostResolveCheckNetwork returns a Check that makes sure the provided host can resolve
+// to at least `minRequiredResults` IP address of the given network within the specified timeout,
+// e.g. NetworkTCP6 for validating the AAAA records of dual stack hosts.
+// The check is named after the IP version, e.g. `resolve.ipv6.example.com`, so a check may be registered per IP version.
+func NewHostResolveCheckNetwork(host string, network Network, timeout time.Duration, minRequiredResults int) (Check, error) {
+	if err := network.validate(); err != nil {
+		return nil, err
+	}
+	check := NewResolveCheck(NewHostLookupNetwork(nil, network), host, timeout, minRequiredResults).(*CustomCheck)
+	if version := network.ipVersion(); version != "" {
+		check.CheckName = "resolve." + version + "." + host
+	}
+	return check, nil
+}
+
 // LookupFunc is a function that is used for looking up something (in DNS) and return the resolved results count, and a possible error
 type LookupFunc func(ctx context.Context, lookFor string) (resolvedCount int, err error)
 
 // NewResolveCheck returns a Check that makes sure the `resolveThis` arg can be resolved using the `lookupFn`
 // to at least `minRequiredResults` result within the specified timeout.
 func NewResolveCheck(lookupFn LookupFunc, resolveThis string, timeout time.Duration, minRequiredResults int) Check {
+	messages := currentMessages()
 	return &CustomCheck{
 		CheckName: "resolve." + resolveThis,
 		CheckFunc: func() (details interface{}, err error) {
@@ -28,12 +41,13 @@ func NewResolveCheck(lookupFn LookupFunc, resolveThis string, timeout time.Durat
 			defer cancel()
 
 			resolvedCount, err := lookupFn(ctx, resolveThis)
-			details = fmt.Sprintf("[%d] results were resolved", resolvedCount)
+			data := messageData{"Host": resolveThis, "Count": resolvedCount, "MinRequired": minRequiredResults}
+			details = messages.format(MessageDNSResolved, data)
 			if err != nil {
 				return
 			}
 			if resolvedCount < minRequiredResults {
-				err = errors.Errorf("[%s] lookup returned %d results, but requires at least %d", resolveThis, resolvedCount, minRequiredResults)
+				err = messages.error(MessageDNSTooFewResults, data)
 			}
 
 			return
@@ -53,3 +67,20 @@ func NewHostLookup(resolver *net.Resolver) LookupFunc {
 		return
 	}
 }
+
+// NewHostLookupNetwork creates a LookupFunc that looks up the host addresses of the given network, e.g. IPv6 addresses only
+func NewHostLookupNetwork(resolver *net.Resolver, network Network) LookupFunc {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return func(ctx context.Context, host string) (resolvedCount int, err error) {
+		addrs, err := resolver.LookupIPAddr(ctx, host)
+		for _, addr := range addrs {
+			if network.matches(addr.IP) {
+				resolvedCount++
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEgressEndpoints are well known, highly available endpoints probed by the egress check by default.
+var DefaultEgressEndpoints = []string{
+	"https://www.google.com",
+	"https://www.cloudflare.com",
+	"https://www.microsoft.com",
+}
+
+// EgressCheckConfig configures a check for the outbound internet connectivity, which helps distinguishing between
+// "our dependency is down" and "we have no egress".
+type EgressCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Endpoints are the URLs to probe, defaults to DefaultEgressEndpoints, and must not repeat.
+	Endpoints []string
+	// Quorum is the fraction (0, 1] of endpoints that must be reachable for the check to pass, defaults to `0.5`,
+	// so the outage of a single endpoint doesn't fail the check.
+	Quorum float64
+	// Lookup is used for resolving the endpoints hosts, defaults to NewHostLookup(nil).
+	Lookup LookupFunc
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	// Redirects are never followed, as any response proves the endpoint is reachable.
+	Client *http.Client
+	// Timeout is the timeout used for probing each endpoint (resolve and HEAD request), defaults to "1s".
+	Timeout time.Duration
+}
+
+type egressCheck struct {
+	name     string
+	required int
+	probes   map[string]Check
+}
+
+// NewEgressCheck creates a new outbound connectivity check defined by the given config.
+// Each endpoint is resolved and then sent a HEAD request, where any HTTP response (regardless of its status) counts as reachable.
+// The check reports each endpoint outcome in its QuorumDetails.
+func NewEgressCheck(config EgressCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if len(config.Endpoints) == 0 {
+		config.Endpoints = DefaultEgressEndpoints
+	}
+	if config.Quorum == 0 {
+		config.Quorum = 0.5
+	}
+	quorum, err := validateQuorum(config.Quorum)
+	if err != nil {
+		return nil, err
+	}
+	if config.Lookup == nil {
+		config.Lookup = NewHostLookup(nil)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	client := &http.Client{Timeout: config.Timeout}
+	if config.Client != nil {
+		c := *config.Client
+		client = &c
+	}
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	probes := make(map[string]Check, len(config.Endpoints))
+	for _, endpoint := range config.Endpoints {
+		if _, ok := probes[endpoint]; ok {
+			return nil, errors.Errorf("duplicate endpoint [%s]", endpoint)
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid endpoint [%s]", endpoint)
+		}
+		if u.Hostname() == "" {
+			return nil, errors.Errorf("invalid endpoint [%s]: missing host", endpoint)
+		}
+		probes[endpoint] = newEgressProbe(config.CheckName, u, config.Lookup, client, config.Timeout)
+	}
+
+	return &egressCheck{
+		name:     config.CheckName,
+		required: requiredForQuorum(quorum, len(probes)),
+		probes:   probes,
+	}, nil
+}
+
+func newEgressProbe(name string, endpoint *url.URL, lookup LookupFunc, client *http.Client, timeout time.Duration) Check {
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func() (details interface{}, err error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if _, err = lookup(ctx, endpoint.Hostname()); err != nil {
+				return nil, errors.Errorf("failed to resolve: %v", err)
+			}
+			req, err := http.NewRequest(http.MethodHead, endpoint.String(), nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req.WithContext(ctx))
+			if err != nil {
+				return nil, errors.Errorf("failed to connect: %v", err)
+			}
+			_ = resp.Body.Close()
+			return resp.StatusCode, nil
+		},
+	}
+}
+
+func (check *egressCheck) Name() string {
+	return check.name
+}
+
+func (check *egressCheck) Execute() (details interface{}, err error) {
+	return executeQuorum(check.probes, check.required)
+}
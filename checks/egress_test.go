@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEgressCheckRequiredFields(t *testing.T) {
+	check, err := NewEgressCheck(EgressCheckConfig{})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewEgressCheck(EgressCheckConfig{CheckName: "meh", Quorum: 1.5})
+	assert.Nil(t, check, "invalid quorum should yield nil check")
+	assert.Error(t, err, "invalid quorum should yield error")
+
+	check, err = NewEgressCheck(EgressCheckConfig{CheckName: "meh", Endpoints: []string{"/no/host"}})
+	assert.Nil(t, check, "endpoint without a host should yield nil check")
+	assert.EqualError(t, err, "invalid endpoint [/no/host]: missing host")
+
+	check, err = NewEgressCheck(EgressCheckConfig{CheckName: "meh", Endpoints: []string{"https://a.example", "https://a.example"}})
+	assert.Nil(t, check, "duplicate endpoint should yield nil check")
+	assert.EqualError(t, err, "duplicate endpoint [https://a.example]")
+
+	check, err = NewEgressCheck(EgressCheckConfig{CheckName: "meh"})
+	assert.NoError(t, err)
+	assert.Len(t, check.(*egressCheck).probes, len(DefaultEgressEndpoints), "default endpoints")
+	assert.Equal(t, 2, check.(*egressCheck).required, "default quorum")
+}
+
+func TestEgressCheck(t *testing.T) {
+	var methods []string
+	redirecting := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		methods = append(methods, req.Method)
+		http.Redirect(rw, req, "http://unreachable.invalid", http.StatusMovedPermanently)
+	}))
+	defer redirecting.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	closed.Close()
+
+	unresolved := "http://egress.invalid"
+	lookup := func(ctx context.Context, host string) (int, error) {
+		if host == "egress.invalid" {
+			return 0, errors.New("no such host")
+		}
+		return 1, nil
+	}
+
+	check, err := NewEgressCheck(EgressCheckConfig{
+		CheckName: "egress",
+		Endpoints: []string{redirecting.URL, closed.URL, unresolved},
+		Quorum:    0.3,
+		Lookup:    lookup,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "egress", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass with a reachable endpoint")
+	assert.Equal(t, []string{http.MethodHead}, methods, "redirects should not be followed")
+	quorum := details.(QuorumDetails)
+	assert.Equal(t, 1, quorum.Passed)
+	assert.Equal(t, 1, quorum.Required)
+	assert.Equal(t, "OK", quorum.Targets[redirecting.URL])
+	assert.Contains(t, quorum.Targets[closed.URL], "failed to connect: ")
+	assert.Equal(t, "failed to resolve: no such host", quorum.Targets[unresolved])
+
+	check, err = NewEgressCheck(EgressCheckConfig{
+		CheckName: "egress",
+		Endpoints: []string{redirecting.URL, closed.URL, unresolved},
+		Lookup:    lookup,
+	})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "only 1 of 3 targets passed, but requires at least 2")
+}
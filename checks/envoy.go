@@ -0,0 +1,156 @@
+package checks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEnvoyAdminAddress is the conventional address of the local Envoy admin interface of a sidecar.
+const DefaultEnvoyAdminAddress = "http://127.0.0.1:15000"
+
+// EnvoyCheckConfig configures a check for the upstream clusters of the local Envoy proxy, e.g. a service mesh sidecar.
+type EnvoyCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// AdminAddress is the address of the Envoy admin interface; defaults to DefaultEnvoyAdminAddress.
+	AdminAddress string
+	// Clusters are required, and are the names of the upstream clusters that must have healthy hosts.
+	Clusters []string
+	// MinHealthyHosts is the minimal number of healthy hosts of each cluster; defaults to 1.
+	MinHealthyHosts int
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+}
+
+// EnvoyClusterDetails are the details of an upstream cluster, as reported by the Envoy check.
+type EnvoyClusterDetails struct {
+	Hosts        int `json:"hosts"`
+	HealthyHosts int `json:"healthyHosts"`
+}
+
+// envoyClusters is the part of the `/clusters?format=json` admin response used by the check
+type envoyClusters struct {
+	ClusterStatuses []struct {
+		Name         string `json:"name"`
+		HostStatuses []struct {
+			HealthStatus envoyHealthStatus `json:"health_status"`
+		} `json:"host_statuses"`
+	} `json:"cluster_statuses"`
+}
+
+type envoyHealthStatus struct {
+	EDSHealthStatus            string `json:"eds_health_status"`
+	FailedActiveHealthCheck    bool   `json:"failed_active_health_check"`
+	FailedOutlierCheck         bool   `json:"failed_outlier_check"`
+	FailedActiveDegradedCheck  bool   `json:"failed_active_degraded_check"`
+	ExcludedViaImmediateHCFail bool   `json:"excluded_via_immediate_hc_fail"`
+}
+
+// healthy returns true when Envoy routes requests to the host: it is healthy (or of unknown health) according to EDS,
+// and passes the active health checks and the outlier detection.
+func (s envoyHealthStatus) healthy() bool {
+	switch s.EDSHealthStatus {
+	case "", "HEALTHY", "UNKNOWN":
+	default:
+		return false
+	}
+	return !s.FailedActiveHealthCheck && !s.FailedOutlierCheck && !s.FailedActiveDegradedCheck && !s.ExcludedViaImmediateHCFail
+}
+
+type envoyCheck struct {
+	config      *EnvoyCheckConfig
+	clustersURL string
+}
+
+// NewEnvoyCheck creates a new check of the upstream clusters of the local Envoy proxy defined by the given config,
+// aligning the readiness of the application with the reality visible to the mesh.
+// The check queries the `/clusters` admin endpoint, and fails when any of the configured clusters is unknown,
+// or has less than MinHealthyHosts healthy hosts. The details hold the hosts counts of each configured cluster.
+func NewEnvoyCheck(config EnvoyCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if len(config.Clusters) == 0 {
+		return nil, errors.Errorf("Clusters must not be empty")
+	}
+	if config.AdminAddress == "" {
+		config.AdminAddress = DefaultEnvoyAdminAddress
+	}
+	if _, err = url.Parse(config.AdminAddress); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if config.MinHealthyHosts <= 0 {
+		config.MinHealthyHosts = 1
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	config.Client.Timeout = config.Timeout
+
+	return &envoyCheck{
+		config:      &config,
+		clustersURL: strings.TrimSuffix(config.AdminAddress, "/") + "/clusters?format=json",
+	}, nil
+}
+
+func (check *envoyCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *envoyCheck) Execute() (details interface{}, err error) {
+	resp, err := check.config.Client.Get(check.clustersURL)
+	if err != nil {
+		return nil, errors.Errorf("fail to execute envoy clusters request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code: '%v'", resp.StatusCode)
+	}
+
+	var clusters envoyClusters
+	if err = json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+		return nil, errors.Errorf("failed to parse envoy clusters response: %v", err)
+	}
+
+	all := make(map[string]EnvoyClusterDetails, len(clusters.ClusterStatuses))
+	for _, cluster := range clusters.ClusterStatuses {
+		clusterDetails := EnvoyClusterDetails{Hosts: len(cluster.HostStatuses)}
+		for _, host := range cluster.HostStatuses {
+			if host.HealthStatus.healthy() {
+				clusterDetails.HealthyHosts++
+			}
+		}
+		all[cluster.Name] = clusterDetails
+	}
+
+	result := make(map[string]EnvoyClusterDetails, len(check.config.Clusters))
+	var unhealthy []string
+	for _, name := range check.config.Clusters {
+		clusterDetails, ok := all[name]
+		if !ok {
+			unhealthy = append(unhealthy, name+" (unknown cluster)")
+			continue
+		}
+		result[name] = clusterDetails
+		if clusterDetails.HealthyHosts < check.config.MinHealthyHosts {
+			unhealthy = append(unhealthy, name)
+		}
+	}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return result, errors.Errorf("clusters without %d healthy hosts: %s", check.config.MinHealthyHosts, strings.Join(unhealthy, ", "))
+	}
+	return result, nil
+}
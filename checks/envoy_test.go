@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const envoyClustersResponse = `{
+	"cluster_statuses": [
+		{
+			"name": "orders",
+			"host_statuses": [
+				{"health_status": {"eds_health_status": "HEALTHY"}},
+				{"health_status": {"eds_health_status": "HEALTHY", "failed_outlier_check": true}},
+				{"health_status": {"eds_health_status": "UNHEALTHY"}}
+			]
+		},
+		{
+			"name": "payments",
+			"host_statuses": [
+				{"health_status": {"eds_health_status": "DRAINING"}},
+				{"health_status": {"failed_active_health_check": true}}
+			]
+		},
+		{"name": "static", "host_statuses": [{"health_status": {}}]}
+	]
+}`
+
+func TestNewEnvoyCheckRequiredFields(t *testing.T) {
+	check, err := NewEnvoyCheck(EnvoyCheckConfig{Clusters: []string{"orders"}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewEnvoyCheck(EnvoyCheckConfig{CheckName: "mesh"})
+	assert.Nil(t, check, "nil Clusters should yield nil check")
+	assert.Error(t, err, "nil Clusters should yield error")
+}
+
+func TestEnvoyCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/clusters", req.URL.Path)
+		assert.Equal(t, "json", req.URL.Query().Get("format"))
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(envoyClustersResponse))
+	}))
+	defer server.Close()
+
+	check, err := NewEnvoyCheck(EnvoyCheckConfig{CheckName: "mesh", AdminAddress: server.URL + "/", Clusters: []string{"orders", "static"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "mesh", check.Name())
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]EnvoyClusterDetails{"orders": {Hosts: 3, HealthyHosts: 1}, "static": {Hosts: 1, HealthyHosts: 1}}, details)
+
+	check, err = NewEnvoyCheck(EnvoyCheckConfig{CheckName: "mesh", AdminAddress: server.URL, Clusters: []string{"orders", "payments", "missing"}})
+	assert.NoError(t, err)
+	details, err = check.Execute()
+	assert.EqualError(t, err, "clusters without 1 healthy hosts: missing (unknown cluster), payments")
+	assert.Equal(t, map[string]EnvoyClusterDetails{"orders": {Hosts: 3, HealthyHosts: 1}, "payments": {Hosts: 2}}, details)
+
+	check, err = NewEnvoyCheck(EnvoyCheckConfig{CheckName: "mesh", AdminAddress: server.URL, Clusters: []string{"orders"}, MinHealthyHosts: 2})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "clusters without 2 healthy hosts: orders")
+
+	status = http.StatusServiceUnavailable
+	_, err = check.Execute()
+	assert.EqualError(t, err, "unexpected status code: '503'")
+}
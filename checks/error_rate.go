@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const errorRateBuckets = 10
+
+// ErrorRateRecorder is a small hook, which applications call on errors (and optionally on successes),
+// and which counts them over a sliding time window for the error rate check.
+// It is safe for concurrent use.
+type ErrorRateRecorder struct {
+	bucketSize time.Duration
+	now        func() time.Time
+
+	lock    sync.Mutex
+	buckets [errorRateBuckets]errorRateBucket
+}
+
+type errorRateBucket struct {
+	index  int64
+	errors int64
+	total  int64
+}
+
+// NewErrorRateRecorder creates a recorder counting the events over the given sliding window, e.g. "1m".
+// The window slides in steps of a tenth of its size.
+func NewErrorRateRecorder(window time.Duration) (*ErrorRateRecorder, error) {
+	if window < errorRateBuckets {
+		return nil, errors.Errorf("window is too small: %v", window)
+	}
+	return &ErrorRateRecorder{
+		bucketSize: window / errorRateBuckets,
+		now:        time.Now,
+	}, nil
+}
+
+// RecordError records an error.
+func (r *ErrorRateRecorder) RecordError() {
+	r.record(1)
+}
+
+// RecordSuccess records a successful event; successes are only required for checking the error ratio.
+func (r *ErrorRateRecorder) RecordSuccess() {
+	r.record(0)
+}
+
+func (r *ErrorRateRecorder) record(errs int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	index := r.now().UnixNano() / int64(r.bucketSize)
+	bucket := &r.buckets[index%errorRateBuckets]
+	if bucket.index != index {
+		*bucket = errorRateBucket{index: index}
+	}
+	bucket.errors += errs
+	bucket.total++
+}
+
+// Counts returns the number of errors, and the total number of events, recorded over the window.
+func (r *ErrorRateRecorder) Counts() (errs, total int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	index := r.now().UnixNano() / int64(r.bucketSize)
+	for _, bucket := range r.buckets {
+		if bucket.index > index-errorRateBuckets && bucket.index <= index {
+			errs += bucket.errors
+			total += bucket.total
+		}
+	}
+	return errs, total
+}
+
+// ErrorRateCheckConfig configures a check that fails when the rate of application errors crosses a threshold.
+type ErrorRateCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Recorder is required, and is the recorder the application reports its errors to.
+	Recorder *ErrorRateRecorder
+	// MaxErrors is the maximal number of errors allowed over the window; 0 disables the threshold.
+	MaxErrors int64
+	// MaxErrorRatio is the maximal ratio (0, 1] of errors out of all the recorded events allowed over the window;
+	// 0 disables the threshold. At least one of MaxErrors and MaxErrorRatio is required.
+	MaxErrorRatio float64
+	// MinEvents is the minimal number of events over the window required for checking MaxErrorRatio,
+	// so a single error in a quiet window doesn't fail the check; defaults to `1`.
+	MinEvents int64
+}
+
+// ErrorRateDetails are the details reported by the error rate check.
+type ErrorRateDetails struct {
+	Errors int64   `json:"errors"`
+	Total  int64   `json:"total"`
+	Ratio  float64 `json:"ratio"`
+}
+
+type errorRateCheck struct {
+	config *ErrorRateCheckConfig
+}
+
+// NewErrorRateCheck creates a new error rate check defined by the given config,
+// bridging application level error signals into the health.
+func NewErrorRateCheck(config ErrorRateCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Recorder == nil {
+		return nil, errors.Errorf("Recorder must not be nil")
+	}
+	if config.MaxErrors < 0 || config.MaxErrorRatio < 0 || config.MaxErrorRatio > 1 {
+		return nil, errors.Errorf("MaxErrors must not be negative, and MaxErrorRatio must be in the range (0, 1]")
+	}
+	if config.MaxErrors == 0 && config.MaxErrorRatio == 0 {
+		return nil, errors.Errorf("either MaxErrors or MaxErrorRatio must be defined")
+	}
+	if config.MinEvents <= 0 {
+		config.MinEvents = 1
+	}
+
+	return &errorRateCheck{config: &config}, nil
+}
+
+func (check *errorRateCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *errorRateCheck) Execute() (details interface{}, err error) {
+	errs, total := check.config.Recorder.Counts()
+	result := ErrorRateDetails{Errors: errs, Total: total}
+	if total > 0 {
+		result.Ratio = float64(errs) / float64(total)
+	}
+
+	if check.config.MaxErrors > 0 && errs > check.config.MaxErrors {
+		return result, errors.Errorf("%d errors exceed the limit of %d", errs, check.config.MaxErrors)
+	}
+	if check.config.MaxErrorRatio > 0 && total >= check.config.MinEvents && result.Ratio > check.config.MaxErrorRatio {
+		return result, errors.Errorf("error ratio %.2f exceeds %.2f", result.Ratio, check.config.MaxErrorRatio)
+	}
+	return result, nil
+}
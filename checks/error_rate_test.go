@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorRateCheckRequiredFields(t *testing.T) {
+	recorder, err := NewErrorRateRecorder(time.Minute)
+	assert.NoError(t, err)
+
+	check, err := NewErrorRateCheck(ErrorRateCheckConfig{Recorder: recorder, MaxErrors: 1})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewErrorRateCheck(ErrorRateCheckConfig{CheckName: "errors", MaxErrors: 1})
+	assert.Nil(t, check, "nil Recorder should yield nil check")
+	assert.Error(t, err, "nil Recorder should yield error")
+
+	check, err = NewErrorRateCheck(ErrorRateCheckConfig{CheckName: "errors", Recorder: recorder})
+	assert.Nil(t, check, "no thresholds should yield nil check")
+	assert.Error(t, err, "no thresholds should yield error")
+
+	check, err = NewErrorRateCheck(ErrorRateCheckConfig{CheckName: "errors", Recorder: recorder, MaxErrorRatio: 1.5})
+	assert.Nil(t, check, "invalid ratio should yield nil check")
+	assert.Error(t, err, "invalid ratio should yield error")
+
+	_, err = NewErrorRateRecorder(time.Nanosecond)
+	assert.Error(t, err, "too small window should yield error")
+}
+
+func TestErrorRateRecorder(t *testing.T) {
+	recorder, err := NewErrorRateRecorder(time.Minute)
+	assert.NoError(t, err)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+
+	recorder.RecordError()
+	recorder.RecordSuccess()
+	now = now.Add(30 * time.Second)
+	recorder.RecordError()
+	errs, total := recorder.Counts()
+	assert.Equal(t, int64(2), errs)
+	assert.Equal(t, int64(3), total)
+
+	now = now.Add(35 * time.Second)
+	errs, total = recorder.Counts()
+	assert.Equal(t, int64(1), errs, "events older than the window are dropped")
+	assert.Equal(t, int64(1), total)
+
+	now = now.Add(10 * time.Minute)
+	recorder.RecordSuccess()
+	errs, total = recorder.Counts()
+	assert.Equal(t, int64(0), errs, "reused buckets are reset")
+	assert.Equal(t, int64(1), total)
+}
+
+func TestErrorRateCheck(t *testing.T) {
+	recorder, _ := NewErrorRateRecorder(time.Minute)
+	check, err := NewErrorRateCheck(ErrorRateCheckConfig{
+		CheckName:     "app.errors",
+		Recorder:      recorder,
+		MaxErrors:     5,
+		MaxErrorRatio: 0.5,
+		MinEvents:     3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "app.errors", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrorRateDetails{}, details)
+
+	recorder.RecordError()
+	recorder.RecordError()
+	_, err = check.Execute()
+	assert.NoError(t, err, "error ratio isn't checked below MinEvents")
+
+	recorder.RecordSuccess()
+	details, err = check.Execute()
+	assert.EqualError(t, err, "error ratio 0.67 exceeds 0.50")
+	assert.Equal(t, int64(2), details.(ErrorRateDetails).Errors)
+
+	for i := 0; i < 10; i++ {
+		recorder.RecordSuccess()
+	}
+	_, err = check.Execute()
+	assert.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		recorder.RecordError()
+	}
+	_, err = check.Execute()
+	assert.EqualError(t, err, "6 errors exceed the limit of 5")
+}
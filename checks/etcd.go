@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EtcdClient is the minimal etcd client used by the etcd check.
+// See NewEtcdHTTPClient for a client using etcd's HTTP API, or adapt the official `clientv3` client.
+type EtcdClient interface {
+	// Health returns an error when the member reports itself as unhealthy (e.g. has no leader).
+	Health(ctx context.Context) error
+	// Get performs a linearizable read of the given key, going through the raft quorum.
+	// A missing key is not an error.
+	Get(ctx context.Context, key string) error
+}
+
+// EtcdCheckConfig configures a check for the health of an etcd cluster member.
+type EtcdCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Client is required, and is used for querying the etcd member.
+	Client EtcdClient
+	// Key is the key used for the linearizable read, defaults to "health".
+	Key string
+	// Timeout is the timeout used for the whole check execution (health and read), defaults to "1s".
+	Timeout time.Duration
+}
+
+// EtcdHealthDetails are the details reported by the etcd check.
+type EtcdHealthDetails struct {
+	// Healthy is true when the member reported itself as healthy.
+	Healthy bool `json:"healthy"`
+	// ReadLatency is the latency of the linearizable read, when it was performed.
+	ReadLatency string `json:"read_latency,omitempty"`
+}
+
+type etcdCheck struct {
+	config *EtcdCheckConfig
+}
+
+// NewEtcdCheck creates a new etcd health check defined by the given config.
+// The check fails when the member reports itself as unhealthy, or when a linearizable read fails,
+// which verifies the member is connected to a cluster with a quorum.
+func NewEtcdCheck(config EtcdCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Client == nil {
+		return nil, errors.Errorf("Client must not be nil")
+	}
+	if config.Key == "" {
+		config.Key = "health"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &etcdCheck{config: &config}, nil
+}
+
+func (check *etcdCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *etcdCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	var health EtcdHealthDetails
+	if err = check.config.Client.Health(ctx); err != nil {
+		return health, errors.Errorf("etcd member is unhealthy: %v", err)
+	}
+	health.Healthy = true
+
+	start := time.Now()
+	if err = check.config.Client.Get(ctx, check.config.Key); err != nil {
+		return health, errors.Errorf("etcd linearizable read failed: %v", err)
+	}
+	health.ReadLatency = time.Since(start).String()
+	return health, nil
+}
+
+type etcdHTTPClient struct {
+	healthURL string
+	rangeURL  string
+	client    *http.Client
+}
+
+// NewEtcdHTTPClient creates an EtcdClient for the etcd member at the given address (e.g. "http://127.0.0.1:2379"),
+// using the `/health` endpoint and the v3 JSON gateway for the linearizable read.
+// client is optional; if nil, http.DefaultClient is used.
+func NewEtcdHTTPClient(address string, client *http.Client) (EtcdClient, error) {
+	if address == "" {
+		return nil, errors.Errorf("address must not be empty")
+	}
+	if _, err := url.Parse(address); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	address = strings.TrimSuffix(address, "/")
+	return &etcdHTTPClient{
+		healthURL: address + "/health",
+		rangeURL:  address + "/v3/kv/range",
+		client:    client,
+	}, nil
+}
+
+func (c *etcdHTTPClient) Health(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, c.healthURL, nil)
+	if err != nil {
+		return err
+	}
+	// an unhealthy member responds with a non-OK status code, and the reason in the body
+	status, body, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	var health struct {
+		Health string `json:"health"`
+		Reason string `json:"reason"`
+	}
+	if err = json.Unmarshal(body, &health); err != nil {
+		return errors.Errorf("failed to parse health response (status code: '%v'): %v", status, err)
+	}
+	if health.Health != "true" {
+		if health.Reason != "" {
+			return errors.New(health.Reason)
+		}
+		return errors.Errorf("health is '%s'", health.Health)
+	}
+	return nil
+}
+
+func (c *etcdHTTPClient) Get(ctx context.Context, key string) error {
+	// ranges are linearizable unless "serializable" is set
+	payload, err := json.Marshal(map[string]interface{}{
+		"key":        base64.StdEncoding.EncodeToString([]byte(key)),
+		"keys_only":  true,
+		"count_only": true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.rangeURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	status, _, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.Errorf("unexpected status code: '%v'", status)
+	}
+	return nil
+}
+
+func (c *etcdHTTPClient) do(req *http.Request) (status int, body []byte, err error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err = ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
@@ -0,0 +1,105 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubEtcdClient struct {
+	healthErr error
+	getErr    error
+	key       string
+}
+
+func (c *stubEtcdClient) Health(ctx context.Context) error {
+	return c.healthErr
+}
+
+func (c *stubEtcdClient) Get(ctx context.Context, key string) error {
+	c.key = key
+	return c.getErr
+}
+
+func TestNewEtcdCheckRequiredFields(t *testing.T) {
+	check, err := NewEtcdCheck(EtcdCheckConfig{Client: &stubEtcdClient{}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewEtcdCheck(EtcdCheckConfig{CheckName: "etcd"})
+	assert.Nil(t, check, "nil Client should yield nil check")
+	assert.Error(t, err, "nil Client should yield error")
+}
+
+func TestEtcdCheck(t *testing.T) {
+	client := &stubEtcdClient{}
+	check, err := NewEtcdCheck(EtcdCheckConfig{CheckName: "etcd.check", Client: client})
+	assert.NoError(t, err)
+	assert.Equal(t, "etcd.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.True(t, details.(EtcdHealthDetails).Healthy)
+	assert.NotEmpty(t, details.(EtcdHealthDetails).ReadLatency)
+	assert.Equal(t, "health", client.key, "default key")
+
+	client.getErr = errors.New("context deadline exceeded")
+	details, err = check.Execute()
+	assert.EqualError(t, err, "etcd linearizable read failed: context deadline exceeded")
+	assert.Equal(t, EtcdHealthDetails{Healthy: true}, details)
+
+	client.healthErr = errors.New("NOSPACE")
+	details, err = check.Execute()
+	assert.EqualError(t, err, "etcd member is unhealthy: NOSPACE")
+	assert.Equal(t, EtcdHealthDetails{}, details)
+}
+
+func TestEtcdHTTPClient(t *testing.T) {
+	var health string
+	var rangeStatus int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/health":
+			if health != `{"health":"true","reason":""}` {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			}
+			_, _ = rw.Write([]byte(health))
+		case "/v3/kv/range":
+			assert.Equal(t, http.MethodPost, req.Method)
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			assert.Equal(t, "aGVhbHRo", body["key"], "base64 encoded key")
+			assert.Nil(t, body["serializable"], "read should be linearizable")
+			rw.WriteHeader(rangeStatus)
+			_, _ = rw.Write([]byte(`{"header":{},"count":"0"}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewEtcdHTTPClient("", nil)
+	assert.Error(t, err, "empty address should yield error")
+
+	client, err := NewEtcdHTTPClient(server.URL+"/", nil)
+	assert.NoError(t, err)
+	check, err := NewEtcdCheck(EtcdCheckConfig{CheckName: "etcd.check", Client: client})
+	assert.NoError(t, err)
+
+	health, rangeStatus = `{"health":"true","reason":""}`, http.StatusOK
+	_, err = check.Execute()
+	assert.NoError(t, err)
+
+	health = `{"health":"false","reason":"RAFT NO LEADER"}`
+	_, err = check.Execute()
+	assert.EqualError(t, err, "etcd member is unhealthy: RAFT NO LEADER")
+
+	health, rangeStatus = `{"health":"true","reason":""}`, http.StatusServiceUnavailable
+	_, err = check.Execute()
+	assert.EqualError(t, err, "etcd linearizable read failed: unexpected status code: '503'")
+}
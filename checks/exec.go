@@ -0,0 +1,275 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The plugin protocol is line delimited JSON over the plugin process stdin and stdout:
+// for each execution the check writes a PluginRequest line, and the plugin answers with a PluginResponse line carrying the same ID.
+// The plugin process is long-lived, and serves the executions one at a time.
+
+// PluginRequest is a probe request sent to a plugin.
+type PluginRequest struct {
+	ID    uint64 `json:"id"`
+	Check string `json:"check"`
+}
+
+// PluginResponse is the result of a probe, sent by a plugin.
+type PluginResponse struct {
+	ID uint64 `json:"id"`
+	// Status is one of "passing" (the default), "degraded" and "failing".
+	Status  string      `json:"status,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+	// Error fails the check when not empty.
+	Error string `json:"error,omitempty"`
+}
+
+const maxPluginLineSize = 1024 * 1024
+
+// ExecCheckConfig configures a check executed by a long-lived plugin process, e.g. a check written in another language.
+type ExecCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Command is required, and is the path (or the name, looked up in PATH) of the plugin executable.
+	Command string
+	// Args are the plugin arguments.
+	Args []string
+	// Env are additional environment variables of the plugin, in the form "key=value".
+	Env []string
+	// Stderr receives the plugin stderr, which is discarded by default.
+	Stderr io.Writer
+	// Timeout is the timeout of a probe, defaults to `1s`; a plugin that doesn't respond in time is restarted.
+	Timeout time.Duration
+}
+
+type execCheck struct {
+	config *ExecCheckConfig
+
+	lock   sync.Mutex
+	plugin *pluginProcess
+	nextID uint64
+}
+
+type pluginProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan PluginResponse
+	quit      chan struct{}
+	done      chan struct{}
+	err       error
+}
+
+// NewExecCheck creates a new check executed by a plugin process speaking the plugin protocol (see PluginRequest and PluginResponse).
+// The plugin is started on the first execution, and restarted when it exits, fails to respond in time, or violates the protocol.
+// The returned check implements io.Closer, which stops the plugin.
+func NewExecCheck(config ExecCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Command == "" {
+		return nil, errors.Errorf("Command must not be empty")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Second
+	}
+
+	return &execCheck{config: &config}, nil
+}
+
+func (check *execCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *execCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+func (check *execCheck) ExecuteWithContext(ctx context.Context) ExecutionResult {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, check.config.Timeout)
+	defer cancel()
+
+	if check.plugin == nil {
+		plugin, err := startPlugin(check.config)
+		if err != nil {
+			return ExecutionResult{Err: err}
+		}
+		check.plugin = plugin
+	}
+
+	check.nextID++
+	response, err := check.plugin.probe(ctx, PluginRequest{ID: check.nextID, Check: check.config.CheckName})
+	if err != nil {
+		check.stopPlugin()
+		return ExecutionResult{Err: err}
+	}
+
+	result := ExecutionResult{Details: response.Details}
+	switch response.Status {
+	case "", "passing":
+		result.Status = StatusPassing
+	case "degraded":
+		result.Status = StatusDegraded
+	case "failing":
+		result.Status = StatusFailing
+	default:
+		check.stopPlugin()
+		return ExecutionResult{Err: errors.Errorf("plugin responded with an unknown status: %s", response.Status)}
+	}
+	if response.Error != "" {
+		result.Err = errors.New(response.Error)
+	}
+	return result
+}
+
+// Close stops the plugin process.
+func (check *execCheck) Close() error {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	check.stopPlugin()
+	return nil
+}
+
+// stopPlugin kills the plugin process, if any, so the next execution restarts it. Callers must hold the lock.
+func (check *execCheck) stopPlugin() {
+	if check.plugin == nil {
+		return
+	}
+	close(check.plugin.quit)
+	_ = check.plugin.cmd.Process.Kill()
+	<-check.plugin.done
+	check.plugin = nil
+}
+
+func startPlugin(config *ExecCheckConfig) (*pluginProcess, error) {
+	cmd := exec.Command(config.Command, config.Args...) // nolint:gosec
+	cmd.Env = append(os.Environ(), config.Env...)
+	cmd.Stderr = config.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the plugin stdin")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the plugin stdout")
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start plugin %s", config.Command)
+	}
+
+	plugin := &pluginProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan PluginResponse),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go plugin.read(stdout)
+	return plugin, nil
+}
+
+// read decodes the plugin responses, until the plugin exits or violates the protocol
+func (p *pluginProcess) read(stdout io.Reader) {
+	defer close(p.done)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 4096), maxPluginLineSize)
+	for scanner.Scan() {
+		var response PluginResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			p.err = errors.Wrap(err, "plugin sent a malformed response")
+			_ = p.cmd.Process.Kill()
+			break
+		}
+		select {
+		case p.responses <- response:
+		case <-p.quit:
+		}
+	}
+	err := p.cmd.Wait()
+	switch {
+	case p.err != nil:
+	case err != nil:
+		p.err = errors.Wrap(err, "plugin exited")
+	default:
+		p.err = errors.New("plugin exited")
+	}
+}
+
+// probe sends the request to the plugin, and waits for its response
+func (p *pluginProcess) probe(ctx context.Context, request PluginRequest) (PluginResponse, error) {
+	line, err := json.Marshal(request)
+	if err != nil {
+		return PluginResponse{}, errors.Wrap(err, "failed to encode the plugin request")
+	}
+	if _, err = p.stdin.Write(append(line, '\n')); err != nil {
+		return PluginResponse{}, errors.Wrap(err, "failed to send the plugin request")
+	}
+
+	for {
+		select {
+		case response := <-p.responses:
+			if response.ID == request.ID {
+				return response, nil
+			}
+			// a late response of a previous request
+		case <-p.done:
+			return PluginResponse{}, p.err
+		case <-ctx.Done():
+			return PluginResponse{}, errors.Wrap(ctx.Err(), "plugin didn't respond in time")
+		}
+	}
+}
+
+// ServePlugin serves the plugin protocol over the given reader and writer (typically os.Stdin and os.Stdout),
+// executing the given check for each request, until the reader is exhausted.
+// It is the plugin side of NewExecCheck, for plugins written in Go.
+func ServePlugin(r io.Reader, w io.Writer, check Check) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxPluginLineSize)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		var request PluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			return errors.Wrap(err, "malformed plugin request")
+		}
+
+		response := PluginResponse{ID: request.ID}
+		var result ExecutionResult
+		if contextCheck, ok := check.(ContextCheck); ok {
+			result = contextCheck.ExecuteWithContext(context.Background())
+		} else {
+			result.Details, result.Err = check.Execute()
+		}
+		response.Details = result.Details
+		switch {
+		case result.Err != nil:
+			response.Status = "failing"
+			response.Error = result.Err.Error()
+		case result.Status == StatusDegraded:
+			response.Status = "degraded"
+		case result.Status == StatusFailing:
+			response.Status = "failing"
+		default:
+			response.Status = "passing"
+		}
+		if err := encoder.Encode(response); err != nil {
+			return errors.Wrap(err, "failed to send the plugin response")
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pluginModeEnv = "SUNDHEIT_TEST_PLUGIN"
+
+// TestExecPluginHelper isn't a real test, it's the plugin process executed by the exec check tests
+func TestExecPluginHelper(t *testing.T) {
+	mode := os.Getenv(pluginModeEnv)
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	executions := 0
+	check := &CustomCheck{
+		CheckName: "plugin",
+		CheckFunc: func() (details interface{}, err error) {
+			executions++
+			switch mode {
+			case "fail":
+				return "bad", errors.New("plugin failure")
+			case "slow":
+				time.Sleep(time.Hour)
+			case "exit":
+				if executions > 1 {
+					os.Exit(1)
+				}
+			}
+			return fmt.Sprintf("execution %d", executions), nil
+		},
+	}
+	switch mode {
+	case "garbage":
+		_, _ = os.Stdout.WriteString("not json\n")
+	case "degraded":
+		_ = ServePlugin(os.Stdin, os.Stdout, &degradedCheck{})
+	default:
+		_ = ServePlugin(os.Stdin, os.Stdout, check)
+	}
+}
+
+type degradedCheck struct{}
+
+func (degradedCheck) Name() string { return "degraded" }
+
+func (degradedCheck) Execute() (details interface{}, err error) { return nil, nil }
+
+func (degradedCheck) ExecuteWithContext(context.Context) ExecutionResult {
+	return ExecutionResult{Details: "slow", Status: StatusDegraded}
+}
+
+func newPluginCheck(t *testing.T, mode string, timeout time.Duration) ContextCheck {
+	check, err := NewExecCheck(ExecCheckConfig{
+		CheckName: "exec.check",
+		Command:   os.Args[0],
+		Args:      []string{"-test.run=^TestExecPluginHelper$"},
+		Env:       []string{pluginModeEnv + "=" + mode},
+		Timeout:   timeout,
+	})
+	if err != nil {
+		t.Fatal("Failed to create exec check: ", err)
+	}
+	return check.(ContextCheck)
+}
+
+func TestNewExecCheck(t *testing.T) {
+	_, err := NewExecCheck(ExecCheckConfig{Command: "plugin"})
+	assert.Error(t, err, "missing name")
+
+	_, err = NewExecCheck(ExecCheckConfig{CheckName: "exec.check"})
+	assert.Error(t, err, "missing command")
+
+	check, err := NewExecCheck(ExecCheckConfig{CheckName: "exec.check", Command: "/no/such/plugin"})
+	assert.NoError(t, err)
+	assert.Equal(t, "exec.check", check.Name())
+	_, err = check.Execute()
+	assert.Error(t, err, "missing plugin")
+}
+
+func TestExecCheck_pass(t *testing.T) {
+	check := newPluginCheck(t, "pass", 5*time.Second)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	for i := 1; i <= 3; i++ {
+		details, err := check.Execute()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("execution %d", i), details, "the plugin process is long-lived")
+	}
+}
+
+func TestExecCheck_fail(t *testing.T) {
+	check := newPluginCheck(t, "fail", 5*time.Second)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	result := check.ExecuteWithContext(context.Background())
+	assert.EqualError(t, result.Err, "plugin failure")
+	assert.Equal(t, StatusFailing, result.Status)
+	assert.Equal(t, "bad", result.Details)
+}
+
+func TestExecCheck_degraded(t *testing.T) {
+	check := newPluginCheck(t, "degraded", 5*time.Second)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	result := check.ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.Equal(t, "slow", result.Details)
+}
+
+func TestExecCheck_restart(t *testing.T) {
+	check := newPluginCheck(t, "exit", 5*time.Second)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "execution 1", details)
+
+	_, err = check.Execute()
+	assert.Error(t, err, "the plugin exited")
+	assert.True(t, strings.HasPrefix(err.Error(), "plugin exited"), err.Error())
+
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "execution 1", details, "the plugin is restarted")
+}
+
+func TestExecCheck_timeout(t *testing.T) {
+	check := newPluginCheck(t, "slow", 500*time.Millisecond)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	_, err := check.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin didn't respond in time")
+}
+
+func TestExecCheck_malformedResponse(t *testing.T) {
+	check := newPluginCheck(t, "garbage", 5*time.Second)
+	defer func() { _ = check.(io.Closer).Close() }()
+
+	_, err := check.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin sent a malformed response")
+}
+
+func TestServePlugin(t *testing.T) {
+	var out bytes.Buffer
+	requests := strings.NewReader(`{"id":1,"check":"a"}` + "\n" + `{"id":2,"check":"a"}` + "\n")
+	check := NewScriptedCheck("a", PassResult("ok"), FailResult(errors.New("down")))
+	assert.NoError(t, ServePlugin(requests, &out, check))
+	assert.Equal(t, `{"id":1,"status":"passing","details":"ok"}`+"\n"+
+		`{"id":2,"status":"failing","error":"down"}`+"\n", out.String())
+
+	assert.Error(t, ServePlugin(strings.NewReader("{\n"), &out, check), "malformed request")
+}
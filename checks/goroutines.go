@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GoroutineCheckConfig configures a check that detects goroutine leaks, by sampling the number of goroutines over its executions.
+type GoroutineCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// MaxGoroutines is the absolute bound on the number of goroutines; 0 disables the bound.
+	MaxGoroutines int
+	// MaxSlope is the maximal growth rate of the number of goroutines, in goroutines per minute, estimated over
+	// the last `Window` executions; 0 disables the bound. At least one of MaxGoroutines and MaxSlope is required.
+	MaxSlope float64
+	// Window is the number of executions used for estimating the growth rate, defaults to `10`.
+	Window int
+	// TopStacks is the number of most common goroutine stacks reported in the details on failure, defaults to `5`.
+	TopStacks int
+}
+
+// GoroutineDetails are the details reported by the goroutine leak check.
+type GoroutineDetails struct {
+	Count int `json:"count"`
+	// Slope is the estimated growth rate in goroutines per minute, once the window is full.
+	Slope float64 `json:"slope_per_minute"`
+	// TopStacks are the most common goroutine stacks, reported on failure.
+	TopStacks []GoroutineStack `json:"top_stacks,omitempty"`
+}
+
+// GoroutineStack is a goroutine stack, along with the number of goroutines sharing it.
+type GoroutineStack struct {
+	Count int `json:"count"`
+	// Frames are the function names of the stack frames, starting at the innermost frame.
+	Frames []string `json:"frames"`
+}
+
+type goroutineSample struct {
+	at    time.Time
+	count int
+}
+
+type goroutineCheck struct {
+	config *GoroutineCheckConfig
+	count  func() int
+	now    func() time.Time
+
+	lock    sync.Mutex
+	samples []goroutineSample
+}
+
+// NewGoroutineCheck creates a new goroutine leak check defined by the given config.
+// The check fails when the number of goroutines exceeds MaxGoroutines, or when its growth rate over the
+// last `Window` executions exceeds MaxSlope, and then reports the most common goroutine stacks in its details.
+func NewGoroutineCheck(config GoroutineCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.MaxGoroutines < 0 || config.MaxSlope < 0 {
+		return nil, errors.Errorf("MaxGoroutines and MaxSlope must not be negative")
+	}
+	if config.MaxGoroutines == 0 && config.MaxSlope == 0 {
+		return nil, errors.Errorf("either MaxGoroutines or MaxSlope must be defined")
+	}
+	if config.Window == 0 {
+		config.Window = 10
+	}
+	if config.Window < 2 {
+		return nil, errors.Errorf("Window must be at least 2, got: %d", config.Window)
+	}
+	if config.TopStacks <= 0 {
+		config.TopStacks = 5
+	}
+
+	return &goroutineCheck{
+		config: &config,
+		count:  runtime.NumGoroutine,
+		now:    time.Now,
+	}, nil
+}
+
+func (check *goroutineCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *goroutineCheck) Execute() (details interface{}, err error) {
+	result := GoroutineDetails{Count: check.count()}
+	result.Slope = check.sample(result.Count)
+
+	switch {
+	case check.config.MaxGoroutines > 0 && result.Count > check.config.MaxGoroutines:
+		err = errors.Errorf("%d goroutines exceed the limit of %d", result.Count, check.config.MaxGoroutines)
+	case check.config.MaxSlope > 0 && result.Slope > check.config.MaxSlope:
+		err = errors.Errorf("goroutines grow by %.2f per minute, exceeding %.2f", result.Slope, check.config.MaxSlope)
+	default:
+		return result, nil
+	}
+
+	result.TopStacks = topGoroutineStacks(check.config.TopStacks)
+	return result, err
+}
+
+// sample records the given count, and returns the estimated growth rate once the window is full
+func (check *goroutineCheck) sample(count int) float64 {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	check.samples = append(check.samples, goroutineSample{at: check.now(), count: count})
+	if len(check.samples) > check.config.Window {
+		check.samples = check.samples[len(check.samples)-check.config.Window:]
+	}
+	if len(check.samples) < check.config.Window {
+		return 0
+	}
+
+	// least squares slope, with x in minutes since the first sample
+	var meanX, meanY float64
+	for _, s := range check.samples {
+		meanX += s.at.Sub(check.samples[0].at).Minutes()
+		meanY += float64(s.count)
+	}
+	n := float64(len(check.samples))
+	meanX, meanY = meanX/n, meanY/n
+
+	var cov, variance float64
+	for _, s := range check.samples {
+		dx := s.at.Sub(check.samples[0].at).Minutes() - meanX
+		cov += dx * (float64(s.count) - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return 0
+	}
+	return cov / variance
+}
+
+// topGoroutineStacks returns the most common goroutine stacks, parsed from the (count sorted) debug=1 goroutine profile
+func topGoroutineStacks(limit int) []GoroutineStack {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	var stacks []GoroutineStack
+	var current *GoroutineStack
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.Contains(line, " @ "):
+			if len(stacks) == limit {
+				return stacks
+			}
+			count, err := strconv.Atoi(strings.SplitN(line, " ", 2)[0])
+			if err != nil {
+				current = nil
+				continue
+			}
+			stacks = append(stacks, GoroutineStack{Count: count})
+			current = &stacks[len(stacks)-1]
+		case current != nil && strings.HasPrefix(line, "#"):
+			// e.g. "#	0x4a1b2c	main.worker+0x3c	/app/main.go:12"
+			fields := strings.Fields(line)
+			if len(fields) >= 3 {
+				current.Frames = append(current.Frames, strings.SplitN(fields[2], "+", 2)[0])
+			}
+		}
+	}
+	return stacks
+}
@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGoroutineCheckRequiredFields(t *testing.T) {
+	check, err := NewGoroutineCheck(GoroutineCheckConfig{MaxGoroutines: 100})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewGoroutineCheck(GoroutineCheckConfig{CheckName: "goroutines"})
+	assert.Nil(t, check, "no bounds should yield nil check")
+	assert.Error(t, err, "no bounds should yield error")
+
+	check, err = NewGoroutineCheck(GoroutineCheckConfig{CheckName: "goroutines", MaxSlope: 1, Window: 1})
+	assert.Nil(t, check, "too small window should yield nil check")
+	assert.Error(t, err, "too small window should yield error")
+}
+
+func TestGoroutineCheck_absolute(t *testing.T) {
+	check, err := NewGoroutineCheck(GoroutineCheckConfig{CheckName: "goroutines.check", MaxGoroutines: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, "goroutines.check", check.Name(), "check name")
+
+	count := 50
+	check.(*goroutineCheck).count = func() int { return count }
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, GoroutineDetails{Count: 50}, details)
+
+	count = 101
+	details, err = check.Execute()
+	assert.EqualError(t, err, "101 goroutines exceed the limit of 100")
+	assert.NotEmpty(t, details.(GoroutineDetails).TopStacks, "top stacks should be reported on failure")
+}
+
+func TestGoroutineCheck_slope(t *testing.T) {
+	check, err := NewGoroutineCheck(GoroutineCheckConfig{CheckName: "goroutines.check", MaxSlope: 5, Window: 3, TopStacks: 1})
+	assert.NoError(t, err)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	count := 10
+	check.(*goroutineCheck).count = func() int { return count }
+	check.(*goroutineCheck).now = func() time.Time { return now }
+	execute := func(growth int) (GoroutineDetails, error) {
+		count += growth
+		now = now.Add(time.Minute)
+		details, err := check.Execute()
+		return details.(GoroutineDetails), err
+	}
+
+	for i := 0; i < 2; i++ {
+		details, err := execute(3)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(0), details.Slope, "slope is estimated once the window is full")
+	}
+	details, err := execute(3)
+	assert.NoError(t, err, "growth below the bound should pass")
+	assert.InDelta(t, float64(3), details.Slope, 0.01)
+	assert.Empty(t, details.TopStacks, "top stacks are reported only on failure")
+
+	details, err = execute(4)
+	assert.NoError(t, err)
+	assert.InDelta(t, 3.5, details.Slope, 0.01)
+
+	details, err = execute(10)
+	assert.EqualError(t, err, "goroutines grow by 7.00 per minute, exceeding 5.00")
+	assert.Len(t, details.TopStacks, 1)
+}
+
+func TestTopGoroutineStacks(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	var started sync.WaitGroup
+	started.Add(20)
+	for i := 0; i < 20; i++ {
+		go leakyWorker(&started, block)
+	}
+	started.Wait()
+	// let the workers block on the channel
+	time.Sleep(10 * time.Millisecond)
+
+	stacks := topGoroutineStacks(3)
+	assert.Len(t, stacks, 3)
+	assert.Equal(t, 20, stacks[0].Count, "most common stack first")
+	assert.Equal(t, "github.com/AppsFlyer/go-sundheit/checks.leakyWorker", stacks[0].Frames[0], "innermost frame")
+}
+
+func leakyWorker(started *sync.WaitGroup, block chan struct{}) {
+	started.Done()
+	<-block
+}
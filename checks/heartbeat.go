@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HeartbeatDetails are the details reported by the heartbeat check.
+type HeartbeatDetails struct {
+	// LastBeat is the time of the last beat; empty when no beat was received yet.
+	LastBeat string `json:"last_beat,omitempty"`
+	// Since is the time passed since the last beat, or since the check was created when no beat was received yet.
+	Since string `json:"since"`
+}
+
+// HeartbeatCheck is a check which is pinged by the monitored component, instead of probing it:
+// the component (e.g. an internal worker loop) calls Beat() on each iteration, and the check fails when no beat
+// was received within the max interval.
+type HeartbeatCheck struct {
+	name        string
+	maxInterval time.Duration
+	now         func() time.Time
+	created     time.Time
+	// lastBeat is the unix nano time of the last beat, zero when no beat was received yet
+	lastBeat int64
+}
+
+var _ Check = (*HeartbeatCheck)(nil)
+
+// NewHeartbeatCheck returns a heartbeat check, which fails when no beat was received within maxInterval.
+// The interval is first measured from the creation of the check, so the component has maxInterval to start beating.
+func NewHeartbeatCheck(name string, maxInterval time.Duration) *HeartbeatCheck {
+	return &HeartbeatCheck{
+		name:        name,
+		maxInterval: maxInterval,
+		now:         time.Now,
+		created:     time.Now(),
+	}
+}
+
+// Beat records a beat. Beat is cheap, and safe for concurrent use.
+func (check *HeartbeatCheck) Beat() {
+	atomic.StoreInt64(&check.lastBeat, check.now().UnixNano())
+}
+
+// Name is the name of the check.
+func (check *HeartbeatCheck) Name() string {
+	return check.name
+}
+
+// Execute fails when no beat was received within the max interval.
+func (check *HeartbeatCheck) Execute() (details interface{}, err error) {
+	var result HeartbeatDetails
+	last := check.created
+	if beat := atomic.LoadInt64(&check.lastBeat); beat != 0 {
+		last = time.Unix(0, beat)
+		result.LastBeat = last.UTC().Format(time.RFC3339Nano)
+	}
+	since := check.now().Sub(last)
+	result.Since = since.String()
+
+	if since > check.maxInterval {
+		if result.LastBeat == "" {
+			return result, errors.Errorf("no beat received since the check was created %v ago", since)
+		}
+		return result, errors.Errorf("no beat received for %v, exceeding %v", since, check.maxInterval)
+	}
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatCheck(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	check := NewHeartbeatCheck("worker.heartbeat", time.Minute)
+	check.now = func() time.Time { return now }
+	check.created = now
+	assert.Equal(t, "worker.heartbeat", check.Name())
+
+	now = now.Add(30 * time.Second)
+	details, err := check.Execute()
+	assert.NoError(t, err, "within the initial interval")
+	assert.Equal(t, HeartbeatDetails{Since: "30s"}, details)
+
+	now = now.Add(time.Minute)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "no beat received since the check was created 1m30s ago")
+
+	check.Beat()
+	now = now.Add(10 * time.Second)
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, HeartbeatDetails{LastBeat: "2020-01-01T00:01:30Z", Since: "10s"}, details)
+
+	now = now.Add(time.Minute)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "no beat received for 1m10s, exceeding 1m0s")
+}
@@ -1,9 +1,10 @@
 package checks
 
 import (
-	"fmt"
+	"context"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -29,12 +30,46 @@ type HTTPCheckConfig struct {
 	ExpectedStatus int
 	// ExpectedBody is optional; if defined, operates as a basic "body should contain <string>".
 	ExpectedBody string
-	// Client is optional; if undefined, a new client will be created using "Timeout".
+	// Client is optional; if undefined, a new client will be created using "Timeout" and "DialContext".
 	Client *http.Client
+	// DialContext is optional; if defined, the created client uses it to open connections,
+	// e.g. UnixSocketDialer for probing services over a unix domain socket.
+	// DialContext can't be used together with Client.
+	DialContext DialContextFunc
+	// Dialer is optional; if defined, the created client uses it to open connections, e.g. a SOCKS5 proxy dialer
+	// (see NewSOCKS5Dialer), so the probes traverse the same proxies, tunnels or meshes as the application traffic.
+	// Dialer can't be used together with Client or DialContext.
+	Dialer ContextDialer
+	// Doer is optional; if defined, it executes the requests instead of Client, e.g. a fasthttp adapter,
+	// a retryable client or an instrumented transport. "Timeout" is then enforced through the request context.
+	// Doer can't be used together with Client or DialContext.
+	Doer Doer
+	// Network optionally restricts the connections to a single IP version, e.g. NetworkTCP6 for catching IPv6 only breakage;
+	// defaults to NetworkAuto. Network can't be used together with Client or Doer.
+	Network Network
 	// Timeout is the timeout used for the HTTP request, defaults to "1s".
 	Timeout time.Duration
 	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
 	Options []RequestOption
+	// Messages optionally overrides the details and error messages of this check, see SetMessages.
+	Messages Messages
+}
+
+// Doer executes HTTP requests; satisfied by `*http.Client`.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DialContextFunc opens a connection to the given address, see `http.Transport.DialContext`.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// UnixSocketDialer returns a DialContextFunc that connects to the given unix domain socket,
+// regardless of the host in the check URL (e.g. `http://localhost/health`).
+func UnixSocketDialer(socket string) DialContextFunc {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socket)
+	}
 }
 
 // RequestOption configures the request with arbitrary settings, e.g. add request headers, etc.
@@ -42,6 +77,7 @@ type RequestOption func(r *http.Request)
 
 type httpCheck struct {
 	config         *HTTPCheckConfig
+	messages       messageTemplates
 	successDetails string
 }
 
@@ -73,14 +109,49 @@ func NewHTTPCheck(config HTTPCheckConfig) (check Check, err error) {
 	if config.Timeout == 0 {
 		config.Timeout = time.Second
 	}
-	if config.Client == nil {
-		config.Client = &http.Client{}
+	if config.Dialer != nil {
+		if config.DialContext != nil {
+			return nil, errors.Errorf("Dialer must not be used together with DialContext")
+		}
+		config.DialContext = config.Dialer.DialContext
+	}
+	if config.Client != nil && config.DialContext != nil {
+		return nil, errors.Errorf("DialContext must not be used together with Client")
+	}
+	if config.Doer != nil && (config.Client != nil || config.DialContext != nil) {
+		return nil, errors.Errorf("Doer must not be used together with Client or DialContext")
+	}
+	if err := config.Network.validate(); err != nil {
+		return nil, err
+	}
+	if !config.Network.auto() {
+		if config.Client != nil || config.Doer != nil {
+			return nil, errors.Errorf("Network must not be used together with Client or Doer")
+		}
+		config.DialContext = networkDialer(config.Network, config.DialContext)
+	}
+	if config.Doer == nil {
+		if config.Client == nil {
+			config.Client = &http.Client{}
+			if config.DialContext != nil {
+				// keep the default proxy, TLS handshake, keep-alive and idle connection settings
+				transport := http.DefaultTransport.(*http.Transport).Clone()
+				transport.DialContext = config.DialContext
+				config.Client.Transport = transport
+			}
+		}
+		config.Client.Timeout = config.Timeout
+	}
+
+	messages, err := checkMessages(config.Messages)
+	if err != nil {
+		return nil, err
 	}
-	config.Client.Timeout = config.Timeout
 
 	check = &httpCheck{
 		config:         &config,
-		successDetails: fmt.Sprintf("URL [%s] is accessible", config.URL),
+		messages:       messages,
+		successDetails: messages.format(MessageHTTPAccessible, messageData{"URL": config.URL}),
 	}
 	return check, nil
 }
@@ -90,51 +161,92 @@ func (check *httpCheck) Name() string {
 }
 
 func (check *httpCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+// ExecuteWithContext executes the check, reporting the timings of the request (DNS lookup, connect, TLS handshake and first byte)
+func (check *httpCheck) ExecuteWithContext(ctx context.Context) ExecutionResult {
+	ctx, timings := withHTTPTimings(ctx)
+	details, err := check.execute(ctx)
+	return ExecutionResult{Details: details, Err: err, Timings: timings.get()}
+}
+
+func (check *httpCheck) execute(ctx context.Context) (details interface{}, err error) {
 	details = check.config.URL
-	resp, err := check.fetchURL()
+	resp, err := check.fetchURL(ctx)
 	if err != nil {
 		return details, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != check.config.ExpectedStatus {
-		return details, errors.Errorf("unexpected status code: '%v' expected: '%v'",
-			resp.StatusCode, check.config.ExpectedStatus)
+		return details, check.messages.error(MessageHTTPUnexpectedStatus,
+			check.messageData(messageData{"Status": resp.StatusCode, "ExpectedStatus": check.config.ExpectedStatus}))
 	}
 
 	if check.config.ExpectedBody != "" {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return details, errors.Errorf("failed to read response body: %v", err)
+			return details, check.messages.error(MessageHTTPReadBodyFailed, check.messageData(messageData{"Error": err}))
 		}
 
 		if !strings.Contains(string(body), check.config.ExpectedBody) {
-			return details, errors.Errorf("body does not contain expected content '%v'", check.config.ExpectedBody)
+			return details, check.messages.error(MessageHTTPUnexpectedBody, check.messageData(messageData{"ExpectedBody": check.config.ExpectedBody}))
 		}
 	}
 
 	return check.successDetails, nil
+}
 
+// messageData adds the URL and Method to the data of a message
+func (check *httpCheck) messageData(data messageData) messageData {
+	data["URL"] = check.config.URL
+	data["Method"] = check.config.Method
+	return data
 }
 
-// fetchURL executes the HTTP request to the target URL, and returns a `http.Response`, error.
+// fetchURL executes the HTTP request to the target URL using the given context, and returns a `http.Response`, error.
 // It is the callers responsibility to close the response body
-func (check *httpCheck) fetchURL() (*http.Response, error) {
+func (check *httpCheck) fetchURL(ctx context.Context) (*http.Response, error) {
 	req, err := http.NewRequest(check.config.Method, check.config.URL, check.config.Body())
 	if err != nil {
-		return nil, errors.Errorf("unable to create check HTTP request: %v", err)
+		return nil, check.messages.error(MessageHTTPCreateRequestFailed, check.messageData(messageData{"Error": err}))
 	}
+	req = req.WithContext(ctx)
 
 	configureHTTPOptions(req, check.config.Options)
 
-	resp, err := check.config.Client.Do(req)
-	if err != nil {
-		return nil, errors.Errorf("fail to execute '%v' request: %v", check.config.Method, err)
+	if check.config.Client != nil {
+		resp, err := check.config.Client.Do(req)
+		if err != nil {
+			return nil, check.messages.error(MessageHTTPRequestFailed, check.messageData(messageData{"Error": err}))
+		}
+		return resp, nil
 	}
 
+	// a custom Doer isn't bound by the client timeout
+	ctx, cancel := context.WithTimeout(req.Context(), check.config.Timeout)
+	resp, err := check.config.Doer.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, check.messages.error(MessageHTTPRequestFailed, check.messageData(messageData{"Error": err}))
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return resp, nil
 }
 
+// cancelOnClose releases the request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelOnClose) Close() error {
+	defer body.cancel()
+	return body.ReadCloser.Close()
+}
+
 func configureHTTPOptions(req *http.Request, options []RequestOption) {
 	for _, opt := range options {
 		opt(req)
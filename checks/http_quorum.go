@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"github.com/pkg/errors"
+)
+
+// HTTPQuorumCheckConfig configures a check that probes several URLs (e.g. all the replicas of a dependency),
+// and passes when a large enough fraction of them respond successfully.
+type HTTPQuorumCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URLs are the endpoints to probe. At least one valid URL is required, and URLs must not repeat.
+	URLs []string
+	// Quorum is the fraction (0, 1] of endpoints that must pass for the check to pass, defaults to `1` (all endpoints).
+	Quorum float64
+	// Endpoint holds the HTTP settings used for probing each one of the URLs
+	// (method, body, expected status/body, client, timeout and request options).
+	// Its `CheckName` and `URL` fields are ignored.
+	Endpoint HTTPCheckConfig
+}
+
+type httpQuorumCheck struct {
+	name     string
+	required int
+	probes   map[string]Check
+}
+
+// NewHTTPQuorumCheck creates a new multi-endpoint http check defined by the given config.
+// The check reports each endpoint outcome in its QuorumDetails.
+func NewHTTPQuorumCheck(config HTTPQuorumCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if len(config.URLs) == 0 {
+		return nil, errors.Errorf("URLs must not be empty")
+	}
+	quorum, err := validateQuorum(config.Quorum)
+	if err != nil {
+		return nil, err
+	}
+
+	probes := make(map[string]Check, len(config.URLs))
+	for _, u := range config.URLs {
+		if _, ok := probes[u]; ok {
+			return nil, errors.Errorf("duplicate endpoint [%s]", u)
+		}
+		endpoint := config.Endpoint
+		endpoint.CheckName = config.CheckName
+		endpoint.URL = u
+		probe, err := NewHTTPCheck(endpoint)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid endpoint [%s]", u)
+		}
+		probes[u] = probe
+	}
+
+	return &httpQuorumCheck{
+		name:     config.CheckName,
+		required: requiredForQuorum(quorum, len(probes)),
+		probes:   probes,
+	}, nil
+}
+
+func (check *httpQuorumCheck) Name() string {
+	return check.name
+}
+
+func (check *httpQuorumCheck) Execute() (details interface{}, err error) {
+	return executeQuorum(check.probes, check.required)
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPQuorumCheckRequiredFields(t *testing.T) {
+	check, err := NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		URLs: []string{"http://example.org"},
+	})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "meh",
+	})
+	assert.Nil(t, check, "no URLs should yield nil check")
+	assert.Error(t, err, "no URLs should yield error")
+
+	check, err = NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "meh",
+		URLs:      []string{"http://example.org"},
+		Quorum:    1.5,
+	})
+	assert.Nil(t, check, "invalid quorum should yield nil check")
+	assert.Error(t, err, "invalid quorum should yield error")
+
+	check, err = NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "meh",
+		URLs:      []string{"http://example.org", ":/invalid.url"},
+	})
+	assert.Nil(t, check, "invalid url should yield nil check")
+	assert.Error(t, err, "invalid url should yield error")
+
+	check, err = NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "meh",
+		URLs:      []string{"http://example.org", "http://example.com", "http://example.org"},
+	})
+	assert.Nil(t, check, "duplicate url should yield nil check")
+	assert.EqualError(t, err, "duplicate endpoint [http://example.org]")
+}
+
+func TestNewHTTPQuorumCheck(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	check, err := NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "replicas.check",
+		URLs:      []string{up.URL, down.URL},
+		Quorum:    0.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass with half of the endpoints up")
+	assert.Equal(t, QuorumDetails{
+		Passed:   1,
+		Required: 1,
+		Targets: map[string]string{
+			up.URL:   "OK",
+			down.URL: "unexpected status code: '503' expected: '200'",
+		},
+	}, details)
+
+	check, err = NewHTTPQuorumCheck(HTTPQuorumCheckConfig{
+		CheckName: "replicas.check",
+		URLs:      []string{up.URL, down.URL},
+	})
+	assert.NoError(t, err)
+
+	details, err = check.Execute()
+	assert.Error(t, err, "check should fail when not all endpoints are up")
+	assert.Equal(t, "only 1 of 2 targets passed, but requires at least 2", err.Error())
+	assert.Equal(t, 1, details.(QuorumDetails).Passed)
+}
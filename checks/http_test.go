@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -68,6 +71,63 @@ func TestNewHttpCheckRequiredFields(t *testing.T) {
 	})
 	assert.Nil(t, check, "invalid url should yield nil check")
 	assert.Error(t, err, "invalid url should yield error")
+
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		URL:         "http://example.org",
+		CheckName:   "meh",
+		Client:      &http.Client{},
+		DialContext: UnixSocketDialer("/tmp/meh.sock"),
+	})
+	assert.Nil(t, check, "client with dialer should yield nil check")
+	assert.Error(t, err, "client with dialer should yield error")
+
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		URL:       "http://example.org",
+		CheckName: "meh",
+		Client:    &http.Client{},
+		Doer:      &http.Client{},
+	})
+	assert.Nil(t, check, "client with doer should yield nil check")
+	assert.Error(t, err, "client with doer should yield error")
+}
+
+func TestNewHttpCheckUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "service.sock")
+
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(expectedContent))
+	})}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName:    "socket.check",
+		URL:          "http://localhost/health",
+		ExpectedBody: expectedContent,
+		DialContext:  UnixSocketDialer(socket),
+	})
+	assert.NoError(t, err)
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass over the unix socket")
+	assert.Equal(t, "URL [http://localhost/health] is accessible", details)
+
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		CheckName:   "socket.check",
+		URL:         "http://localhost/health",
+		DialContext: UnixSocketDialer(filepath.Join(dir, "missing.sock")),
+	})
+	assert.NoError(t, err)
+
+	_, err = check.Execute()
+	assert.Error(t, err, "check should fail when the socket doesn't exist")
 }
 
 func TestNewHttpCheck(t *testing.T) {
@@ -114,6 +174,53 @@ func TestNewHttpCheck(t *testing.T) {
 	t.Run("HttpCheck fail on status code", testHTTPCheckFailStatusCode(server.URL, server.Client()))
 	t.Run("HttpCheck fail on URL", testHTTPCheckFailURL(server.URL, server.Client()))
 	t.Run("HttpCheck fail on timeout", testHTTPCheckFailTimeout(server.URL, server.Client()))
+	t.Run("HttpCheck success call with custom doer", testHTTPCheckSuccessWithDoer(server.URL, server.Client()))
+	t.Run("HttpCheck fail on timeout with custom doer", testHTTPCheckFailTimeoutWithDoer(server.URL, server.Client()))
+}
+
+type countingDoer struct {
+	client *http.Client
+	calls  int
+}
+
+func (doer *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	doer.calls++
+	return doer.client.Do(req)
+}
+
+func testHTTPCheckSuccessWithDoer(url string, client *http.Client) func(t *testing.T) {
+	return func(t *testing.T) {
+		doer := &countingDoer{client: client}
+		check, err := NewHTTPCheck(HTTPCheckConfig{
+			CheckName:    "url.check",
+			URL:          url,
+			ExpectedBody: expectedContent,
+			Doer:         doer,
+		})
+		assert.Nil(t, err)
+
+		details, err := check.Execute()
+		assert.Nil(t, err, "check should pass")
+		assert.Equal(t, fmt.Sprintf("URL [%s] is accessible", url), details, "check details when pass")
+		assert.Equal(t, 1, doer.calls, "requests should be executed by the doer")
+	}
+}
+
+func testHTTPCheckFailTimeoutWithDoer(url string, client *http.Client) func(t *testing.T) {
+	return func(t *testing.T) {
+		waitURL := fmt.Sprintf("%s/%s?wait=%s", url, longRequest, 100*time.Millisecond)
+		check, err := NewHTTPCheck(HTTPCheckConfig{
+			CheckName: "url.check",
+			URL:       waitURL,
+			Doer:      &countingDoer{client: client},
+			Timeout:   10 * time.Millisecond,
+		})
+		assert.Nil(t, err)
+
+		_, err = check.Execute()
+		assert.Error(t, err, "check should fail")
+		assert.Contains(t, err.Error(), "context deadline exceeded", "check error message")
+	}
 }
 
 func testHTTPCheckSuccess(url string, client *http.Client) func(t *testing.T) {
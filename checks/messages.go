@@ -0,0 +1,173 @@
+package checks
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// MessageKey identifies a message of the built-in HTTP, DNS and TCP checks.
+type MessageKey string
+
+// The message keys, along with the data available to their templates.
+const (
+	// MessageHTTPAccessible is the details of a passing HTTP check: URL
+	MessageHTTPAccessible MessageKey = "http.accessible"
+	// MessageHTTPUnexpectedStatus is the error of an unexpected response status: URL, Method, Status, ExpectedStatus
+	MessageHTTPUnexpectedStatus MessageKey = "http.unexpected_status"
+	// MessageHTTPUnexpectedBody is the error of a response body missing the expected content: URL, Method, ExpectedBody
+	MessageHTTPUnexpectedBody MessageKey = "http.unexpected_body"
+	// MessageHTTPReadBodyFailed is the error of a failure to read the response body: URL, Method, Error
+	MessageHTTPReadBodyFailed MessageKey = "http.read_body_failed"
+	// MessageHTTPCreateRequestFailed is the error of a failure to create the request: URL, Method, Error
+	MessageHTTPCreateRequestFailed MessageKey = "http.create_request_failed"
+	// MessageHTTPRequestFailed is the error of a failure to execute the request: URL, Method, Error
+	MessageHTTPRequestFailed MessageKey = "http.request_failed"
+	// MessageDNSResolved is the details of a DNS check: Host, Count
+	MessageDNSResolved MessageKey = "dns.resolved"
+	// MessageDNSTooFewResults is the error of a lookup returning too few results: Host, Count, MinRequired
+	MessageDNSTooFewResults MessageKey = "dns.too_few_results"
+	// MessagePingFailed is the error of a failed ping, e.g. a TCP dial: Name, Error
+	MessagePingFailed MessageKey = "ping.failed"
+)
+
+// Messages are the text/template sources of the messages of the built-in checks, by key.
+// The templates are executed with a map of the message data, e.g. `{{.URL}}`; `Error` is the underlying error.
+type Messages map[MessageKey]string
+
+// DefaultMessages are the default English messages.
+var DefaultMessages = Messages{
+	MessageHTTPAccessible:          "URL [{{.URL}}] is accessible",
+	MessageHTTPUnexpectedStatus:    "unexpected status code: '{{.Status}}' expected: '{{.ExpectedStatus}}'",
+	MessageHTTPUnexpectedBody:      "body does not contain expected content '{{.ExpectedBody}}'",
+	MessageHTTPReadBodyFailed:      "failed to read response body: {{.Error}}",
+	MessageHTTPCreateRequestFailed: "unable to create check HTTP request: {{.Error}}",
+	MessageHTTPRequestFailed:       "fail to execute '{{.Method}}' request: {{.Error}}",
+	MessageDNSResolved:             "[{{.Count}}] results were resolved",
+	MessageDNSTooFewResults:        "[{{.Host}}] lookup returned {{.Count}} results, but requires at least {{.MinRequired}}",
+	MessagePingFailed:              "{{.Error}}",
+}
+
+// KeyValueMessages are machine parsable messages, formatted as space separated key=value pairs with quoted strings,
+// e.g. `reason=unexpected_status url="http://localhost/health" status=503 expected=200`.
+var KeyValueMessages = Messages{
+	MessageHTTPAccessible:          `reason=accessible url={{printf "%q" .URL}}`,
+	MessageHTTPUnexpectedStatus:    `reason=unexpected_status url={{printf "%q" .URL}} status={{.Status}} expected={{.ExpectedStatus}}`,
+	MessageHTTPUnexpectedBody:      `reason=unexpected_body url={{printf "%q" .URL}} expected={{printf "%q" .ExpectedBody}}`,
+	MessageHTTPReadBodyFailed:      `reason=read_body_failed url={{printf "%q" .URL}} error={{printf "%q" .Error}}`,
+	MessageHTTPCreateRequestFailed: `reason=create_request_failed url={{printf "%q" .URL}} error={{printf "%q" .Error}}`,
+	MessageHTTPRequestFailed:       `reason=request_failed method={{.Method}} url={{printf "%q" .URL}} error={{printf "%q" .Error}}`,
+	MessageDNSResolved:             `reason=resolved host={{printf "%q" .Host}} count={{.Count}}`,
+	MessageDNSTooFewResults:        `reason=too_few_results host={{printf "%q" .Host}} count={{.Count}} required={{.MinRequired}}`,
+	MessagePingFailed:              `reason=ping_failed check={{printf "%q" .Name}} error={{printf "%q" .Error}}`,
+}
+
+type messageData map[string]interface{}
+
+// messageTemplates are the compiled messages, including all the keys
+type messageTemplates map[MessageKey]*template.Template
+
+var defaultTemplates = mustCompileMessages(DefaultMessages)
+
+var (
+	messagesLock     sync.RWMutex
+	currentTemplates = defaultTemplates
+)
+
+func mustCompileMessages(messages Messages) messageTemplates {
+	templates, err := compileMessages(messages)
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// compileMessages parses the messages, falling back to the default messages for missing keys.
+func compileMessages(messages Messages) (messageTemplates, error) {
+	templates := make(messageTemplates, len(DefaultMessages))
+	for key, text := range DefaultMessages {
+		if override, ok := messages[key]; ok {
+			text = override
+		}
+		tmpl, err := template.New(string(key)).Parse(text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid message %s", key)
+		}
+		templates[key] = tmpl
+	}
+	for key := range messages {
+		if _, ok := DefaultMessages[key]; !ok {
+			return nil, errors.Errorf("unknown message %s", key)
+		}
+	}
+	return templates, nil
+}
+
+// SetMessages sets the messages of the built-in HTTP, DNS and TCP checks created afterwards, e.g. localized messages,
+// or KeyValueMessages. Missing keys keep their default message. Returns an error when a template is invalid.
+func SetMessages(messages Messages) error {
+	templates, err := compileMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	messagesLock.Lock()
+	defer messagesLock.Unlock()
+	currentTemplates = templates
+	return nil
+}
+
+// checkMessages returns the messages of a new check: the given messages when set, or the messages set by SetMessages.
+func checkMessages(messages Messages) (messageTemplates, error) {
+	if messages != nil {
+		return compileMessages(messages)
+	}
+	return currentMessages(), nil
+}
+
+// currentMessages returns the messages set by SetMessages
+func currentMessages() messageTemplates {
+	messagesLock.RLock()
+	defer messagesLock.RUnlock()
+	return currentTemplates
+}
+
+// format renders the message, falling back to the default message when the template fails.
+func (t messageTemplates) format(key MessageKey, data messageData) string {
+	var buf bytes.Buffer
+	if err := t[key].Execute(&buf, data); err != nil {
+		buf.Reset()
+		_ = defaultTemplates[key].Execute(&buf, data)
+	}
+	return buf.String()
+}
+
+// error returns an error with the rendered message, which wraps the data Error when set.
+func (t messageTemplates) error(key MessageKey, data messageData) error {
+	msg := t.format(key, data)
+	if cause, ok := data["Error"].(error); ok {
+		return &messageError{msg: msg, cause: cause}
+	}
+	return errors.New(msg)
+}
+
+type messageError struct {
+	msg   string
+	cause error
+}
+
+func (e *messageError) Error() string {
+	return e.msg
+}
+
+// Cause returns the underlying error, see errors.Cause
+func (e *messageError) Cause() error {
+	return e.cause
+}
+
+// Unwrap returns the underlying error, see errors.Unwrap
+func (e *messageError) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCheckMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName: "api",
+		URL:       server.URL,
+		Messages:  KeyValueMessages,
+	})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, `reason=unexpected_status url="`+server.URL+`" status=503 expected=200`)
+
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		CheckName:      "api",
+		URL:            server.URL,
+		ExpectedStatus: http.StatusServiceUnavailable,
+		Messages: Messages{
+			MessageHTTPAccessible: "{{.URL}} ist erreichbar",
+		},
+	})
+	assert.NoError(t, err)
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+" ist erreichbar", details)
+
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Messages: Messages{MessageHTTPAccessible: "{{.URL"}})
+	assert.Error(t, err, "invalid template")
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Messages: Messages{"http.unknown": "meh"}})
+	assert.Error(t, err, "unknown key")
+}
+
+func TestHTTPCheckMessagesWrapTheError(t *testing.T) {
+	requestErr := errors.New("connection refused")
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName: "api",
+		URL:       "http://localhost/health",
+		Doer:      doerFunc(func(*http.Request) (*http.Response, error) { return nil, requestErr }),
+		Messages:  KeyValueMessages,
+	})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, `reason=request_failed method=GET url="http://localhost/health" error="connection refused"`)
+	assert.Equal(t, requestErr, errors.Cause(err))
+}
+
+func TestSetMessages(t *testing.T) {
+	assert.Error(t, SetMessages(Messages{MessageDNSResolved: "{{"}))
+
+	assert.NoError(t, SetMessages(KeyValueMessages))
+	defer func() { _ = SetMessages(nil) }()
+
+	lookup := func(context.Context, string) (int, error) { return 0, nil }
+	details, err := NewResolveCheck(lookup, "db.local", time.Second, 1).Execute()
+	assert.Equal(t, `reason=resolved host="db.local" count=0`, details)
+	assert.EqualError(t, err, `reason=too_few_results host="db.local" count=0 required=1`)
+
+	pingErr := errors.New("connection refused")
+	check, err := NewPingCheck("db", PingContextFunc(func(context.Context) error { return pingErr }), time.Second)
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, `reason=ping_failed check="db" error="connection refused"`)
+	assert.Equal(t, pingErr, errors.Cause(err))
+
+	// the messages are set on creation
+	check, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: "http://localhost/health"})
+	assert.NoError(t, err)
+	assert.NoError(t, SetMessages(nil))
+	assert.Equal(t, `reason=accessible url="http://localhost/health"`, check.(*httpCheck).successDetails)
+}
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaVersionProvider provides the schema version recorded by a migration tool.
+// See NewSQLSchemaVersionProvider for reading the version from a migrations table.
+type SchemaVersionProvider interface {
+	// SchemaVersion returns the current schema version, and whether the last migration failed
+	// and left the schema in a dirty state.
+	SchemaVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
+
+// SchemaVersionFunc type is an adapter to allow the use of ordinary functions as SchemaVersionProviders.
+type SchemaVersionFunc func(ctx context.Context) (version int64, dirty bool, err error)
+
+// SchemaVersion calls f(ctx).
+func (f SchemaVersionFunc) SchemaVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	return f(ctx)
+}
+
+// MigrationCheckConfig configures a check that compares the application's expected schema version
+// with the version recorded by the migration tool.
+type MigrationCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Provider is required, and provides the current schema version.
+	Provider SchemaVersionProvider
+	// ExpectedVersion is the schema version the application requires.
+	ExpectedVersion int64
+	// RequireExact indicates when true, that a newer schema version fails the check as well; defaults to false,
+	// which allows rolling back the application while keeping the schema.
+	RequireExact bool
+	// Timeout is the timeout used for reading the schema version, defaults to "1s".
+	Timeout time.Duration
+}
+
+// MigrationDetails are the details reported by the migration status check.
+type MigrationDetails struct {
+	Expected int64 `json:"expected"`
+	Current  int64 `json:"current"`
+	Dirty    bool  `json:"dirty,omitempty"`
+}
+
+type migrationCheck struct {
+	config *MigrationCheckConfig
+}
+
+// NewMigrationCheck creates a new migration status check defined by the given config.
+// The check fails until the migrations have completed, i.e. the schema version reached the expected version,
+// and when the schema is left dirty by a failed migration.
+func NewMigrationCheck(config MigrationCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Provider == nil {
+		return nil, errors.Errorf("Provider must not be nil")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &migrationCheck{config: &config}, nil
+}
+
+func (check *migrationCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *migrationCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	version, dirty, err := check.config.Provider.SchemaVersion(ctx)
+	if err != nil {
+		return nil, errors.Errorf("failed to read schema version: %v", err)
+	}
+
+	result := MigrationDetails{Expected: check.config.ExpectedVersion, Current: version, Dirty: dirty}
+	switch {
+	case dirty:
+		return result, errors.Errorf("schema version %d is dirty", version)
+	case version < check.config.ExpectedVersion:
+		return result, errors.Errorf("schema version %d is behind the expected version %d", version, check.config.ExpectedVersion)
+	case version > check.config.ExpectedVersion && check.config.RequireExact:
+		return result, errors.Errorf("schema version %d is ahead of the expected version %d", version, check.config.ExpectedVersion)
+	}
+	return result, nil
+}
+
+// NewSQLSchemaVersionProvider returns a SchemaVersionProvider that reads the schema version using the given query.
+// The query should return a single row, with the version in its first column, and an optional boolean dirty flag
+// in its second column; e.g. "SELECT version, dirty FROM schema_migrations" for golang-migrate,
+// or "SELECT max(version_id) FROM goose_db_version" for goose.
+func NewSQLSchemaVersionProvider(db SQLQuerier, query string) SchemaVersionFunc {
+	return func(ctx context.Context) (version int64, dirty bool, err error) {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return 0, false, err
+		}
+		defer func() { _ = rows.Close() }()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return 0, false, err
+		}
+		if !rows.Next() {
+			if err = rows.Err(); err != nil {
+				return 0, false, err
+			}
+			return 0, false, errors.New("no schema version recorded")
+		}
+		if len(columns) > 1 {
+			err = rows.Scan(&version, &dirty)
+		} else {
+			err = rows.Scan(&version)
+		}
+		return version, dirty, err
+	}
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigrationCheckRequiredFields(t *testing.T) {
+	check, err := NewMigrationCheck(MigrationCheckConfig{Provider: stubSchemaVersion(1, false)})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewMigrationCheck(MigrationCheckConfig{CheckName: "migrations"})
+	assert.Nil(t, check, "nil Provider should yield nil check")
+	assert.Error(t, err, "nil Provider should yield error")
+}
+
+func TestMigrationCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      int64
+		dirty        bool
+		requireExact bool
+		err          string
+	}{
+		{"up to date", 12, false, false, ""},
+		{"behind", 11, false, false, "schema version 11 is behind the expected version 12"},
+		{"dirty", 12, true, false, "schema version 12 is dirty"},
+		{"ahead", 13, false, false, ""},
+		{"ahead exact", 13, false, true, "schema version 13 is ahead of the expected version 12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check, err := NewMigrationCheck(MigrationCheckConfig{
+				CheckName:       "migrations",
+				Provider:        stubSchemaVersion(test.version, test.dirty),
+				ExpectedVersion: 12,
+				RequireExact:    test.requireExact,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "migrations", check.Name(), "check name")
+
+			details, err := check.Execute()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+			assert.Equal(t, MigrationDetails{Expected: 12, Current: test.version, Dirty: test.dirty}, details)
+		})
+	}
+
+	check, _ := NewMigrationCheck(MigrationCheckConfig{
+		CheckName: "migrations",
+		Provider: SchemaVersionFunc(func(ctx context.Context) (int64, bool, error) {
+			return 0, false, errors.New("relation \"schema_migrations\" does not exist")
+		}),
+	})
+	_, err := check.Execute()
+	assert.EqualError(t, err, "failed to read schema version: relation \"schema_migrations\" does not exist")
+}
+
+func TestSQLSchemaVersionProvider(t *testing.T) {
+	db := openStubDB([][]driver.Value{{int64(7), true}}, nil)
+	defer func() { _ = db.Close() }()
+	version, dirty, err := NewSQLSchemaVersionProvider(db, "SELECT version, dirty FROM schema_migrations").SchemaVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), version)
+	assert.True(t, dirty)
+
+	db = openStubDB([][]driver.Value{{int64(9)}}, nil)
+	defer func() { _ = db.Close() }()
+	version, dirty, err = NewSQLSchemaVersionProvider(db, "SELECT max(version_id) FROM goose_db_version").SchemaVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), version)
+	assert.False(t, dirty)
+
+	db = openStubDB(nil, nil)
+	defer func() { _ = db.Close() }()
+	_, _, err = NewSQLSchemaVersionProvider(db, "SELECT version FROM schema_migrations").SchemaVersion(context.Background())
+	assert.EqualError(t, err, "no schema version recorded")
+}
+
+func stubSchemaVersion(version int64, dirty bool) SchemaVersionFunc {
+	return func(ctx context.Context) (int64, bool, error) {
+		return version, dirty, nil
+	}
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Network selects the IP version used by the network checks, so dual stack environments can validate each family separately.
+type Network string
+
+const (
+	// NetworkAuto uses either IP version, as the system prefers; the empty Network is NetworkAuto as well
+	NetworkAuto Network = "auto"
+	// NetworkTCP4 uses IPv4 only
+	NetworkTCP4 Network = "tcp4"
+	// NetworkTCP6 uses IPv6 only
+	NetworkTCP6 Network = "tcp6"
+)
+
+func (n Network) validate() error {
+	switch n {
+	case "", NetworkAuto, NetworkTCP4, NetworkTCP6:
+		return nil
+	default:
+		return errors.Errorf("unsupported network %s", n)
+	}
+}
+
+func (n Network) auto() bool {
+	return n == "" || n == NetworkAuto
+}
+
+// dialNetwork returns the network to dial, e.g. "tcp6"
+func (n Network) dialNetwork() string {
+	if n.auto() {
+		return "tcp"
+	}
+	return string(n)
+}
+
+// ipVersion returns the IP version of the network, e.g. "ipv6", or an empty string for NetworkAuto
+func (n Network) ipVersion() string {
+	switch n {
+	case NetworkTCP4:
+		return "ipv4"
+	case NetworkTCP6:
+		return "ipv6"
+	default:
+		return ""
+	}
+}
+
+// matches returns true when the IP address belongs to the network
+func (n Network) matches(ip net.IP) bool {
+	switch n {
+	case NetworkTCP4:
+		return ip.To4() != nil
+	case NetworkTCP6:
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// networkDialer returns a DialContextFunc that dials the given network using dial, or a net.Dialer when dial is nil
+func networkDialer(network Network, dial DialContextFunc) DialContextFunc {
+	if dial == nil {
+		var dialer net.Dialer
+		dial = dialer.DialContext
+	}
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dial(ctx, network.dialNetwork(), addr)
+	}
+}
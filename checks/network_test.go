@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkMatches(t *testing.T) {
+	ipv4, ipv6 := net.ParseIP("10.0.0.1"), net.ParseIP("2001:db8::1")
+	assert.True(t, NetworkAuto.matches(ipv4))
+	assert.True(t, NetworkAuto.matches(ipv6))
+	assert.True(t, NetworkTCP4.matches(ipv4))
+	assert.False(t, NetworkTCP4.matches(ipv6))
+	assert.False(t, NetworkTCP6.matches(ipv4))
+	assert.True(t, NetworkTCP6.matches(ipv6))
+
+	assert.NoError(t, Network("").validate())
+	assert.Error(t, Network("udp").validate())
+}
+
+func TestHTTPCheckNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "api.ipv4", URL: server.URL, Network: NetworkTCP4})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.NoError(t, err)
+
+	// the server listens on an IPv4 address
+	check, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api.ipv6", URL: server.URL, Network: NetworkTCP6})
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.Error(t, err)
+
+	transport := check.(*httpCheck).config.Client.Transport.(*http.Transport)
+	defaults := http.DefaultTransport.(*http.Transport)
+	assert.NotNil(t, transport.Proxy, "the default proxy should be kept")
+	assert.Equal(t, defaults.TLSHandshakeTimeout, transport.TLSHandshakeTimeout, "the default TLS handshake timeout should be kept")
+	assert.Equal(t, defaults.IdleConnTimeout, transport.IdleConnTimeout, "the default idle connection timeout should be kept")
+	assert.Equal(t, defaults.MaxIdleConns, transport.MaxIdleConns, "the default idle connections limit should be kept")
+
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Network: NetworkTCP4, Client: server.Client()})
+	assert.Error(t, err, "Network can't be used together with Client")
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "api", URL: server.URL, Network: "udp"})
+	assert.Error(t, err, "unsupported network")
+}
+
+func TestHostLookupNetwork(t *testing.T) {
+	for network, expected := range map[Network]int{NetworkAuto: 1, NetworkTCP4: 1, NetworkTCP6: 0} {
+		count, err := NewHostLookupNetwork(nil, network)(context.Background(), "127.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, count, network)
+	}
+
+	check, err := NewHostResolveCheckNetwork("::1", NetworkTCP6, time.Second, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "resolve.ipv6.::1", check.Name())
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "[1] results were resolved", details)
+
+	check, err = NewHostResolveCheckNetwork("127.0.0.1", NetworkAuto, time.Second, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "resolve.127.0.0.1", check.Name())
+
+	_, err = NewHostResolveCheckNetwork("127.0.0.1", "udp", time.Second, 1)
+	assert.Error(t, err)
+}
+
+func TestDialPingerNetwork(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	assert.NoError(t, NewDialPinger(string(NetworkAuto), listener.Addr().String())(context.Background()))
+	assert.NoError(t, NewDialPinger(string(NetworkTCP4), listener.Addr().String())(context.Background()))
+	assert.Error(t, NewDialPinger(string(NetworkTCP6), listener.Addr().String())(context.Background()))
+}
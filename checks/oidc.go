@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OIDCCheckConfig configures a check for the availability of an OpenID Connect identity provider.
+type OIDCCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Issuer is required, and is the issuer URL, e.g. "https://accounts.example.com".
+	// The discovery document is fetched from "<Issuer>/.well-known/openid-configuration".
+	Issuer string
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the whole check execution (discovery and JWKS requests), defaults to "1s".
+	Timeout time.Duration
+}
+
+// OIDCDetails are the details reported by the OIDC check.
+type OIDCDetails struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri,omitempty"`
+	// SigningKeys is the number of signing keys published in the JWKS.
+	SigningKeys int `json:"signing_keys"`
+}
+
+type oidcCheck struct {
+	config       *OIDCCheckConfig
+	discoveryURL string
+}
+
+// NewOIDCCheck creates a new OIDC check defined by the given config.
+// The check fetches the discovery document and the JWKS it points to, and fails unless both parse,
+// the document's issuer matches the configured issuer, and the JWKS contains at least one signing key.
+func NewOIDCCheck(config OIDCCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Issuer == "" {
+		return nil, errors.Errorf("Issuer must not be empty")
+	}
+	if _, err = url.Parse(config.Issuer); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+
+	return &oidcCheck{
+		config:       &config,
+		discoveryURL: strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration",
+	}, nil
+}
+
+func (check *oidcCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *oidcCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	result := OIDCDetails{Issuer: check.config.Issuer}
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err = check.getJSON(ctx, check.discoveryURL, &discovery); err != nil {
+		return result, errors.Errorf("failed to fetch discovery document: %v", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(check.config.Issuer, "/") {
+		return result, errors.Errorf("discovery document issuer '%s' does not match '%s'", discovery.Issuer, check.config.Issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return result, errors.Errorf("discovery document has no jwks_uri")
+	}
+	result.JWKSURI = discovery.JWKSURI
+
+	var jwks struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			Use     string `json:"use"`
+		} `json:"keys"`
+	}
+	if err = check.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return result, errors.Errorf("failed to fetch JWKS: %v", err)
+	}
+	for _, key := range jwks.Keys {
+		// keys without a "use" may be used for signing
+		if key.KeyType != "" && (key.Use == "" || key.Use == "sig") {
+			result.SigningKeys++
+		}
+	}
+	if result.SigningKeys == 0 {
+		return result, errors.Errorf("JWKS contains no signing keys")
+	}
+	return result, nil
+}
+
+func (check *oidcCheck) getJSON(ctx context.Context, target string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := check.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code: '%v'", resp.StatusCode)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Errorf("failed to parse response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOIDCCheckRequiredFields(t *testing.T) {
+	check, err := NewOIDCCheck(OIDCCheckConfig{Issuer: "https://accounts.example.com"})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewOIDCCheck(OIDCCheckConfig{CheckName: "oidc"})
+	assert.Nil(t, check, "nil Issuer should yield nil check")
+	assert.Error(t, err, "nil Issuer should yield error")
+}
+
+func TestOIDCCheck(t *testing.T) {
+	var discovery, jwks string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/.well-known/openid-configuration":
+			_, _ = rw.Write([]byte(discovery))
+		case "/keys":
+			_, _ = rw.Write([]byte(jwks))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	validDiscovery := `{"issuer":"` + server.URL + `","jwks_uri":"` + server.URL + `/keys"}`
+	tests := []struct {
+		name      string
+		discovery string
+		jwks      string
+		keys      int
+		err       string
+	}{
+		{"valid", validDiscovery, `{"keys":[{"kty":"RSA","use":"sig"},{"kty":"EC"},{"kty":"RSA","use":"enc"}]}`, 2, ""},
+		{"no signing keys", validDiscovery, `{"keys":[{"kty":"RSA","use":"enc"}]}`, 0, "JWKS contains no signing keys"},
+		{"invalid jwks", validDiscovery, `<html>`, 0,
+			"failed to fetch JWKS: failed to parse response: invalid character '<' looking for beginning of value"},
+		{"issuer mismatch", `{"issuer":"https://evil.example.com","jwks_uri":"` + server.URL + `/keys"}`, "", 0,
+			"discovery document issuer 'https://evil.example.com' does not match '" + server.URL + "'"},
+		{"no jwks uri", `{"issuer":"` + server.URL + `"}`, "", 0, "discovery document has no jwks_uri"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			discovery, jwks = test.discovery, test.jwks
+			check, err := NewOIDCCheck(OIDCCheckConfig{CheckName: "oidc.check", Issuer: server.URL})
+			assert.NoError(t, err)
+			assert.Equal(t, "oidc.check", check.Name(), "check name")
+
+			details, err := check.Execute()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+			assert.Equal(t, test.keys, details.(OIDCDetails).SigningKeys)
+		})
+	}
+
+	check, _ := NewOIDCCheck(OIDCCheckConfig{CheckName: "oidc.check", Issuer: server.URL + "/missing"})
+	_, err := check.Execute()
+	assert.EqualError(t, err, "failed to fetch discovery document: unexpected status code: '404'")
+}
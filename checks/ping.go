@@ -28,21 +28,34 @@ func NewPingCheck(name string, pinger Pinger, timeout time.Duration) (Check, err
 		return nil, errors.New("Pinger must not be nil")
 	}
 
+	messages := currentMessages()
 	return &CustomCheck{
 		CheckName: name,
 		CheckFunc: func() (details interface{}, err error) {
 			pingCtx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
-			return nil, pinger.PingContext(pingCtx)
+			if err := pinger.PingContext(pingCtx); err != nil {
+				return nil, messages.error(MessagePingFailed, messageData{"Name": name, "Error": err})
+			}
+			return nil, nil
 		},
 	}, nil
 }
 
-// NewDialPinger returns a Pinger that pings the specified address
+// NewDialPinger returns a Pinger that pings the specified address.
+// The network is any network supported by net.Dial, e.g. "tcp", or a Network, e.g. "tcp6" for dialing over IPv6 only.
 func NewDialPinger(network, address string) PingContextFunc {
-	var d net.Dialer
+	return NewDialerPinger(&net.Dialer{}, network, address)
+}
+
+// NewDialerPinger returns a Pinger that pings the specified address using the given dialer,
+// e.g. through a SOCKS5 proxy (see NewSOCKS5Dialer), an SSH tunnel or a service mesh.
+func NewDialerPinger(dialer ContextDialer, network, address string) PingContextFunc {
+	if Network(network) == NetworkAuto {
+		network = NetworkAuto.dialNetwork()
+	}
 	return func(ctx context.Context) error {
-		conn, err := d.DialContext(ctx, network, address)
+		conn, err := dialer.DialContext(ctx, network, address)
 		if err == nil {
 			_ = conn.Close()
 		}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// QuorumDetails are the details reported by checks that probe several targets, and require a quorum of them to pass.
+type QuorumDetails struct {
+	// Passed is the number of targets that passed.
+	Passed int `json:"passed"`
+	// Required is the minimal number of passing targets required for the check to pass.
+	Required int `json:"required"`
+	// Targets maps each target to its outcome: "OK" or the failure message.
+	Targets map[string]string `json:"targets"`
+}
+
+func validateQuorum(quorum float64) (float64, error) {
+	if quorum == 0 {
+		return 1, nil
+	}
+	if quorum < 0 || quorum > 1 {
+		return 0, errors.Errorf("Quorum must be in the range (0, 1], got: %v", quorum)
+	}
+	return quorum, nil
+}
+
+func requiredForQuorum(quorum float64, total int) int {
+	return int(math.Ceil(quorum * float64(total)))
+}
+
+// executeQuorum concurrently executes the probes, and fails unless at least `required` of them pass.
+func executeQuorum(probes map[string]Check, required int) (QuorumDetails, error) {
+	details := QuorumDetails{
+		Required: required,
+		Targets:  make(map[string]string, len(probes)),
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	for target, probe := range probes {
+		wg.Add(1)
+		go func(target string, probe Check) {
+			defer wg.Done()
+			_, err := probe.Execute()
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				details.Targets[target] = err.Error()
+				return
+			}
+			details.Targets[target] = "OK"
+			details.Passed++
+		}(target, probe)
+	}
+	wg.Wait()
+
+	if details.Passed < details.Required {
+		return details, errors.Errorf("only %d of %d targets passed, but requires at least %d",
+			details.Passed, len(probes), details.Required)
+	}
+
+	return details, nil
+}
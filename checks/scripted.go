@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"sync"
+	"time"
+)
+
+// ResultSpec specifies a single outcome of a scripted check execution
+type ResultSpec struct {
+	// Details are the details returned by the execution
+	Details interface{}
+	// Err is the error returned by the execution; nil for passing executions
+	Err error
+	// Delay is the time the execution takes before returning (or panicking)
+	Delay time.Duration
+	// Panic, when not nil, is the value the execution panics with
+	Panic interface{}
+}
+
+// PassResult returns a ResultSpec of a passing execution with the given details
+func PassResult(details interface{}) ResultSpec {
+	return ResultSpec{Details: details}
+}
+
+// FailResult returns a ResultSpec of a failing execution with the given error
+func FailResult(err error) ResultSpec {
+	return ResultSpec{Err: err}
+}
+
+// SlowResult returns a copy of the given ResultSpec, which takes the given delay to execute
+func SlowResult(delay time.Duration, spec ResultSpec) ResultSpec {
+	spec.Delay = delay
+	return spec
+}
+
+// PanicResult returns a ResultSpec of an execution that panics with the given value
+func PanicResult(value interface{}) ResultSpec {
+	return ResultSpec{Panic: value}
+}
+
+type scriptedCheck struct {
+	name    string
+	results []ResultSpec
+
+	lock       sync.Mutex
+	executions int
+}
+
+// NewScriptedCheck returns a check that returns the given sequence of outcomes on successive executions.
+// Once the sequence is exhausted, the last outcome is repeated. With no results, the check always passes.
+// Scripted checks are meant for testing threshold, backoff and listener logic.
+func NewScriptedCheck(name string, results ...ResultSpec) Check {
+	return &scriptedCheck{
+		name:    name,
+		results: results,
+	}
+}
+
+func (check *scriptedCheck) Name() string {
+	return check.name
+}
+
+func (check *scriptedCheck) Execute() (details interface{}, err error) {
+	spec := check.next()
+	if spec.Delay > 0 {
+		time.Sleep(spec.Delay)
+	}
+	if spec.Panic != nil {
+		panic(spec.Panic)
+	}
+	return spec.Details, spec.Err
+}
+
+func (check *scriptedCheck) next() ResultSpec {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	if len(check.results) == 0 {
+		return ResultSpec{}
+	}
+	i := check.executions
+	if i >= len(check.results) {
+		i = len(check.results) - 1
+	}
+	check.executions++
+	return check.results[i]
+}
@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScriptedCheck(t *testing.T) {
+	check := NewScriptedCheck("scripted.check",
+		PassResult("first"),
+		FailResult(errors.New("second")),
+		SlowResult(20*time.Millisecond, PassResult("third")),
+		PanicResult("fourth"),
+		FailResult(errors.New("last")),
+	)
+	assert.Equal(t, "scripted.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", details)
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "second")
+
+	start := time.Now()
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "third", details)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond, "slow execution should be delayed")
+
+	assert.PanicsWithValue(t, "fourth", func() { _, _ = check.Execute() })
+
+	for i := 0; i < 2; i++ {
+		_, err = check.Execute()
+		assert.EqualError(t, err, "last", "last outcome should be repeated")
+	}
+}
+
+func TestNewScriptedCheck_empty(t *testing.T) {
+	details, err := NewScriptedCheck("scripted.check").Execute()
+	assert.NoError(t, err, "empty script should pass")
+	assert.Nil(t, details)
+}
@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SQLQuerier executes SQL queries; it is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type SQLQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLRowsValidator validates the rows returned by a query, and returns an error when they don't meet the expected condition.
+// The validator should not close the rows.
+type SQLRowsValidator func(rows *sql.Rows) error
+
+// NewSQLQueryCheck returns a Check that executes the given query, and validates its result rows using the given validator,
+// so the health may depend on data conditions (e.g. replication lag below a threshold) and not just on connectivity.
+// When validator is nil, the check only requires the query to succeed.
+// The check fails when the query is not completed within the given timeout.
+func NewSQLQueryCheck(name string, db SQLQuerier, query string, validator SQLRowsValidator, timeout time.Duration) (Check, error) {
+	if db == nil {
+		return nil, errors.New("db must not be nil")
+	}
+	if query == "" {
+		return nil, errors.New("query must not be empty")
+	}
+
+	return &sqlQueryCheck{name: name, db: db, query: query, validator: validator, timeout: timeout}, nil
+}
+
+// sqlConnector is implemented by *sql.DB, so the connection may be acquired (i.e. dialed and authenticated) separately
+type sqlConnector interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
+
+type sqlQueryCheck struct {
+	name      string
+	db        SQLQuerier
+	query     string
+	validator SQLRowsValidator
+	timeout   time.Duration
+}
+
+func (check *sqlQueryCheck) Name() string {
+	return check.name
+}
+
+func (check *sqlQueryCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+// ExecuteWithContext executes the check, reporting the timings of acquiring a connection (for *sql.DB) and of the query
+func (check *sqlQueryCheck) ExecuteWithContext(ctx context.Context) ExecutionResult {
+	ctx, cancel := context.WithTimeout(ctx, check.timeout)
+	defer cancel()
+
+	timings := make(map[string]time.Duration, 2)
+	querier := check.db
+	if connector, ok := check.db.(sqlConnector); ok {
+		start := time.Now()
+		conn, err := connector.Conn(ctx)
+		timings[TimingConnect] = time.Since(start)
+		if err != nil {
+			return ExecutionResult{Err: errors.Errorf("query failed: %v", err), Timings: timings}
+		}
+		defer func() { _ = conn.Close() }()
+		querier = conn
+	}
+
+	start := time.Now()
+	rows, err := querier.QueryContext(ctx, check.query)
+	timings[TimingQuery] = time.Since(start)
+	if err != nil {
+		return ExecutionResult{Err: errors.Errorf("query failed: %v", err), Timings: timings}
+	}
+	defer func() { _ = rows.Close() }()
+
+	if check.validator != nil {
+		if err = check.validator(rows); err != nil {
+			return ExecutionResult{Err: err, Timings: timings}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return ExecutionResult{Err: errors.Errorf("failed to read query results: %v", err), Timings: timings}
+	}
+	return ExecutionResult{Timings: timings}
+}
@@ -0,0 +1,164 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSQLQueryCheckRequiredFields(t *testing.T) {
+	check, err := NewSQLQueryCheck("sql", nil, "SELECT 1", nil, time.Second)
+	assert.Nil(t, check, "nil db should yield nil check")
+	assert.Error(t, err, "nil db should yield error")
+
+	check, err = NewSQLQueryCheck("sql", openStubDB(nil, nil), "", nil, time.Second)
+	assert.Nil(t, check, "empty query should yield nil check")
+	assert.Error(t, err, "empty query should yield error")
+}
+
+func TestSQLQueryCheck(t *testing.T) {
+	db := openStubDB([][]driver.Value{{int64(3)}}, nil)
+	defer func() { _ = db.Close() }()
+
+	maxLag := func(threshold int64) SQLRowsValidator {
+		return func(rows *sql.Rows) error {
+			if !rows.Next() {
+				return errors.New("no replication status")
+			}
+			var lag int64
+			if err := rows.Scan(&lag); err != nil {
+				return err
+			}
+			if lag > threshold {
+				return errors.Errorf("replication lag %d exceeds %d", lag, threshold)
+			}
+			return nil
+		}
+	}
+
+	check, err := NewSQLQueryCheck("replication.lag", db, "SELECT lag FROM replication_status", maxLag(5), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "replication.lag", check.Name(), "check name")
+	_, err = check.Execute()
+	assert.NoError(t, err, "lag is below the threshold")
+
+	check, _ = NewSQLQueryCheck("replication.lag", db, "SELECT lag FROM replication_status", maxLag(1), time.Second)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "replication lag 3 exceeds 1")
+
+	check, _ = NewSQLQueryCheck("replication.lag", db, "SELECT lag FROM replication_status", nil, time.Second)
+	_, err = check.Execute()
+	assert.NoError(t, err, "nil validator only requires the query to succeed")
+}
+
+func TestSQLQueryCheckTimings(t *testing.T) {
+	db := openStubDB([][]driver.Value{{int64(1)}}, nil)
+	defer func() { _ = db.Close() }()
+
+	check, err := NewSQLQueryCheck("sql", db, "SELECT 1", nil, time.Second)
+	assert.NoError(t, err)
+	result := check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Contains(t, result.Timings, TimingConnect, "connection acquisition should be timed")
+	assert.Contains(t, result.Timings, TimingQuery, "query should be timed")
+
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	check, err = NewSQLQueryCheck("sql", conn, "SELECT 1", nil, time.Second)
+	assert.NoError(t, err)
+	result = check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Equal(t, []string{TimingQuery}, timingPhases(result.Timings), "a connection is only timed by its query")
+}
+
+func TestSQLQueryCheck_queryError(t *testing.T) {
+	db := openStubDB(nil, errors.New("connection refused"))
+	defer func() { _ = db.Close() }()
+
+	check, err := NewSQLQueryCheck("sql", db, "SELECT 1", nil, time.Second)
+	assert.NoError(t, err)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "query failed: connection refused")
+}
+
+// a minimal database/sql driver returning canned rows for any query
+type stubSQLDriver struct {
+	rows [][]driver.Value
+	err  error
+}
+
+func openStubDB(rows [][]driver.Value, err error) *sql.DB {
+	return sql.OpenDB(&stubSQLConnector{driver: &stubSQLDriver{rows: rows, err: err}})
+}
+
+type stubSQLConnector struct {
+	driver *stubSQLDriver
+}
+
+func (c *stubSQLConnector) Connect(context.Context) (driver.Conn, error) {
+	return &stubSQLConn{driver: c.driver}, nil
+}
+
+func (c *stubSQLConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (d *stubSQLDriver) Open(string) (driver.Conn, error) {
+	return &stubSQLConn{driver: d}, nil
+}
+
+type stubSQLConn struct {
+	driver *stubSQLDriver
+}
+
+func (c *stubSQLConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if c.driver.err != nil {
+		return nil, c.driver.err
+	}
+	columns := []string{"value"}
+	if len(c.driver.rows) > 0 {
+		columns = make([]string, len(c.driver.rows[0]))
+	}
+	return &stubSQLRows{columns: columns, rows: c.driver.rows}, nil
+}
+
+func (c *stubSQLConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *stubSQLConn) Close() error {
+	return nil
+}
+
+func (c *stubSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type stubSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *stubSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *stubSQLRows) Close() error {
+	return nil
+}
+
+func (r *stubSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ThresholdProbe samples a numeric signal, e.g. a queue depth, a replication lag or a cache hit rate.
+type ThresholdProbe func() (float64, error)
+
+// ThresholdDetails are the details reported by the threshold check.
+type ThresholdDetails struct {
+	Value    float64 `json:"value"`
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+}
+
+type thresholdCheck struct {
+	name     string
+	probe    ThresholdProbe
+	warn     float64
+	crit     float64
+	inverted bool
+}
+
+// NewThresholdCheck returns a Check that maps a numeric signal to a status: the check is degraded once the value
+// reaches warn, and fails once it reaches crit. When warn is greater than crit, lower values are worse
+// (e.g. a cache hit rate), and the check is degraded once the value drops to warn, and fails once it drops to crit.
+// The raw value is reported in the details.
+func NewThresholdCheck(name string, probe ThresholdProbe, warn, crit float64) (Check, error) {
+	if probe == nil {
+		return nil, errors.New("probe must not be nil")
+	}
+	if math.IsNaN(warn) || math.IsNaN(crit) {
+		return nil, errors.New("thresholds must be numbers")
+	}
+
+	return &thresholdCheck{
+		name:     name,
+		probe:    probe,
+		warn:     warn,
+		crit:     crit,
+		inverted: warn > crit,
+	}, nil
+}
+
+func (check *thresholdCheck) Name() string {
+	return check.name
+}
+
+func (check *thresholdCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+func (check *thresholdCheck) ExecuteWithContext(context.Context) ExecutionResult {
+	value, err := check.probe()
+	if err != nil {
+		return ExecutionResult{Err: errors.Errorf("probe failed: %v", err)}
+	}
+
+	result := ExecutionResult{Details: ThresholdDetails{Value: value, Warning: check.warn, Critical: check.crit}}
+	switch {
+	case math.IsNaN(value):
+		result.Err = errors.New("probe returned NaN")
+	case check.reached(value, check.crit):
+		result.Err = errors.Errorf("value %v reached the critical threshold %v", value, check.crit)
+	case check.reached(value, check.warn):
+		result.Status = StatusDegraded
+	}
+	return result
+}
+
+func (check *thresholdCheck) reached(value, threshold float64) bool {
+	if check.inverted {
+		return value <= threshold
+	}
+	return value >= threshold
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewThresholdCheck(t *testing.T) {
+	_, err := NewThresholdCheck("queue.depth", nil, 10, 20)
+	assert.Error(t, err, "missing probe")
+
+	_, err = NewThresholdCheck("queue.depth", func() (float64, error) { return 0, nil }, math.NaN(), 20)
+	assert.Error(t, err, "NaN threshold")
+}
+
+func TestThresholdCheck(t *testing.T) {
+	var value float64
+	var probeErr error
+	probe := func() (float64, error) { return value, probeErr }
+
+	check, err := NewThresholdCheck("queue.depth", probe, 100, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "queue.depth", check.Name())
+	contextCheck := check.(ContextCheck)
+
+	for _, tc := range []struct {
+		value  float64
+		status Status
+		err    string
+	}{
+		{value: 10, status: StatusPassing},
+		{value: 100, status: StatusDegraded},
+		{value: 999, status: StatusDegraded},
+		{value: 1000, err: "value 1000 reached the critical threshold 1000"},
+		{value: math.NaN(), err: "probe returned NaN"},
+	} {
+		value = tc.value
+		result := contextCheck.ExecuteWithContext(context.Background())
+		if tc.err != "" {
+			assert.EqualError(t, result.Err, tc.err)
+			continue
+		}
+		assert.NoError(t, result.Err)
+		assert.Equal(t, tc.status, result.Status, "value %v", tc.value)
+		assert.Equal(t, ThresholdDetails{Value: tc.value, Warning: 100, Critical: 1000}, result.Details)
+	}
+
+	probeErr = errors.New("broker unavailable")
+	_, err = check.Execute()
+	assert.EqualError(t, err, "probe failed: broker unavailable")
+}
+
+func TestThresholdCheck_inverted(t *testing.T) {
+	var value float64
+	check, err := NewThresholdCheck("cache.hit_rate", func() (float64, error) { return value, nil }, 0.8, 0.5)
+	assert.NoError(t, err)
+	contextCheck := check.(ContextCheck)
+
+	value = 0.95
+	assert.Equal(t, ExecutionResult{Details: ThresholdDetails{Value: 0.95, Warning: 0.8, Critical: 0.5}},
+		contextCheck.ExecuteWithContext(context.Background()))
+
+	value = 0.7
+	assert.Equal(t, StatusDegraded, contextCheck.ExecuteWithContext(context.Background()).Status)
+
+	value = 0.5
+	assert.EqualError(t, contextCheck.ExecuteWithContext(context.Background()).Err, "value 0.5 reached the critical threshold 0.5")
+}
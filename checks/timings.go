@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// The phases reported by the built-in checks in the ExecutionResult timings.
+const (
+	// TimingDNS is the duration of the DNS lookup
+	TimingDNS = "dns"
+	// TimingConnect is the duration of opening a connection; for database checks, this includes the authentication
+	TimingConnect = "connect"
+	// TimingTLS is the duration of the TLS handshake
+	TimingTLS = "tls"
+	// TimingFirstByte is the duration from sending the request (once it was fully written) until the first response byte was received,
+	// i.e. excluding the DNS lookup, connection and TLS handshake
+	TimingFirstByte = "firstByte"
+	// TimingQuery is the duration of executing a query, until its first results are available
+	TimingQuery = "query"
+)
+
+// httpTimings records the timings of an HTTP request using httptrace.
+// Phases that didn't happen (e.g. when a connection was reused) are not recorded.
+type httpTimings struct {
+	lock           sync.Mutex
+	dnsStart       time.Time
+	connectStart   time.Time
+	tlsStart       time.Time
+	firstByteStart time.Time
+	timings        map[string]time.Duration
+}
+
+// withHTTPTimings returns a context recording the timings of the requests using it
+func withHTTPTimings(ctx context.Context) (context.Context, *httpTimings) {
+	t := &httpTimings{timings: make(map[string]time.Duration, 4)}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.begin(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.end(TimingDNS, &t.dnsStart) },
+		// with several addresses, connections may be attempted concurrently; the first successful one is recorded
+		ConnectStart: func(string, string) { t.begin(&t.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.end(TimingConnect, &t.connectStart)
+			}
+		},
+		TLSHandshakeStart:    func() { t.begin(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.end(TimingTLS, &t.tlsStart) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.begin(&t.firstByteStart) },
+		GotFirstResponseByte: func() { t.end(TimingFirstByte, &t.firstByteStart) },
+	}), t
+}
+
+func (t *httpTimings) begin(start *time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if start.IsZero() {
+		*start = time.Now()
+	}
+}
+
+func (t *httpTimings) end(phase string, start *time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.timings[phase]; !ok && !start.IsZero() {
+		t.timings[phase] = time.Since(*start)
+	}
+}
+
+// get returns the recorded timings, or nil when none were recorded
+func (t *httpTimings) get() map[string]time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.timings) == 0 {
+		return nil
+	}
+	timings := make(map[string]time.Duration, len(t.timings))
+	for phase, duration := range t.timings {
+		timings[phase] = duration
+	}
+	return timings
+}
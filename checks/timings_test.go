@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCheckTimings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "timed", URL: server.URL, Client: server.Client()})
+	assert.NoError(t, err)
+
+	result := check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Contains(t, result.Timings, TimingConnect, "new connection should be timed")
+	assert.Contains(t, result.Timings, TimingTLS, "TLS handshake should be timed")
+	assert.Contains(t, result.Timings, TimingFirstByte, "first byte should be timed")
+	assert.NotContains(t, result.Timings, TimingDNS, "IP addresses aren't resolved")
+
+	result = check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Equal(t, []string{TimingFirstByte}, timingPhases(result.Timings), "reused connection should only time the first byte")
+}
+
+func TestHTTPCheckTimings_firstByte(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			time.Sleep(100 * time.Millisecond)
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+	check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "timed", URL: server.URL, Client: client})
+	assert.NoError(t, err)
+
+	result := check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.True(t, result.Timings[TimingFirstByte] < 100*time.Millisecond,
+		"first byte should be timed from sending the request, but was %s", result.Timings[TimingFirstByte])
+}
+
+func TestHTTPCheckTimings_failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "timed", URL: server.URL, Timeout: time.Second})
+	assert.NoError(t, err)
+
+	result := check.(ContextCheck).ExecuteWithContext(context.Background())
+	assert.Error(t, result.Err)
+	assert.Nil(t, result.Timings, "failed connections aren't timed")
+}
+
+func timingPhases(timings map[string]time.Duration) []string {
+	phases := make([]string, 0, len(timings))
+	for phase := range timings {
+		phases = append(phases, phase)
+	}
+	return phases
+}
@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Vault node states, as reported in VaultHealthDetails.
+const (
+	VaultStateActive             = "active"
+	VaultStateStandby            = "standby"
+	VaultStatePerformanceStandby = "performance_standby"
+	VaultStateDRSecondary        = "dr_secondary"
+	VaultStateSealed             = "sealed"
+	VaultStateUninitialized      = "uninitialized"
+)
+
+// Vault uses nonstandard status codes for reporting its state, see https://www.vaultproject.io/api/system/health
+const (
+	vaultStatusStandby            = 429
+	vaultStatusDRSecondary        = 472
+	vaultStatusPerformanceStandby = 473
+)
+
+// VaultCheckConfig configures a check for the health of a HashiCorp Vault server.
+type VaultCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is required, and is the Vault server address, e.g. "https://vault.example.com:8200".
+	Address string
+	// RequireActive indicates when true, that standby nodes fail the check; defaults to false.
+	// Sealed and uninitialized nodes always fail the check.
+	RequireActive bool
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+}
+
+// VaultHealthDetails are the details reported by the Vault health check.
+type VaultHealthDetails struct {
+	// State is one of the VaultStateXXX constants.
+	State              string `json:"state"`
+	Initialized        bool   `json:"initialized"`
+	Sealed             bool   `json:"sealed"`
+	Standby            bool   `json:"standby"`
+	PerformanceStandby bool   `json:"performance_standby"`
+	Version            string `json:"version,omitempty"`
+	ClusterName        string `json:"cluster_name,omitempty"`
+}
+
+type vaultCheck struct {
+	config    *VaultCheckConfig
+	healthURL string
+}
+
+// NewVaultCheck creates a new Vault health check defined by the given config.
+// The check queries the `/v1/sys/health` endpoint, and reports the sealed/standby/active state of the node in its details.
+func NewVaultCheck(config VaultCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Address == "" {
+		return nil, errors.Errorf("Address must not be empty")
+	}
+	if _, err = url.Parse(config.Address); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	config.Client.Timeout = config.Timeout
+
+	return &vaultCheck{
+		config:    &config,
+		healthURL: strings.TrimSuffix(config.Address, "/") + "/v1/sys/health",
+	}, nil
+}
+
+func (check *vaultCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *vaultCheck) Execute() (details interface{}, err error) {
+	resp, err := check.config.Client.Get(check.healthURL)
+	if err != nil {
+		return check.config.Address, errors.Errorf("fail to execute vault health request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var health VaultHealthDetails
+	if err = json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return check.config.Address, errors.Errorf("failed to parse vault health response (status code: '%v'): %v",
+			resp.StatusCode, err)
+	}
+
+	switch {
+	case !health.Initialized:
+		health.State = VaultStateUninitialized
+	case health.Sealed:
+		health.State = VaultStateSealed
+	case resp.StatusCode == vaultStatusDRSecondary:
+		health.State = VaultStateDRSecondary
+	case health.PerformanceStandby || resp.StatusCode == vaultStatusPerformanceStandby:
+		health.State = VaultStatePerformanceStandby
+	case health.Standby || resp.StatusCode == vaultStatusStandby:
+		health.State = VaultStateStandby
+	default:
+		health.State = VaultStateActive
+	}
+
+	switch health.State {
+	case VaultStateUninitialized, VaultStateSealed:
+		return health, errors.Errorf("vault is %s", health.State)
+	case VaultStateActive:
+		return health, nil
+	}
+	if check.config.RequireActive {
+		return health, errors.Errorf("vault is %s, but an active node is required", health.State)
+	}
+	return health, nil
+}
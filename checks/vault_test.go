@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVaultCheckRequiredFields(t *testing.T) {
+	check, err := NewVaultCheck(VaultCheckConfig{Address: "http://vault:8200"})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewVaultCheck(VaultCheckConfig{CheckName: "vault"})
+	assert.Nil(t, check, "nil Address should yield nil check")
+	assert.Error(t, err, "nil Address should yield error")
+}
+
+func TestVaultCheck(t *testing.T) {
+	var status int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/sys/health", req.URL.Path)
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		requireActive bool
+		state         string
+		err           string
+	}{
+		{"active", 200, `{"initialized":true,"sealed":false,"standby":false,"version":"1.6.0"}`, false, VaultStateActive, ""},
+		{"standby", 429, `{"initialized":true,"sealed":false,"standby":true}`, false, VaultStateStandby, ""},
+		{"required active standby", 429, `{"initialized":true,"sealed":false,"standby":true}`, true,
+			VaultStateStandby, "vault is standby, but an active node is required"},
+		{"performance standby", 473, `{"initialized":true,"sealed":false,"standby":true,"performance_standby":true}`, false,
+			VaultStatePerformanceStandby, ""},
+		{"dr secondary", 472, `{"initialized":true,"sealed":false,"standby":false}`, false, VaultStateDRSecondary, ""},
+		{"sealed", 503, `{"initialized":true,"sealed":true,"standby":true}`, false, VaultStateSealed, "vault is sealed"},
+		{"uninitialized", 501, `{"initialized":false,"sealed":true}`, false, VaultStateUninitialized, "vault is uninitialized"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, body = test.status, test.body
+			check, err := NewVaultCheck(VaultCheckConfig{
+				CheckName:     "vault.check",
+				Address:       server.URL + "/",
+				RequireActive: test.requireActive,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "vault.check", check.Name(), "check name")
+
+			details, err := check.Execute()
+			assert.Equal(t, test.state, details.(VaultHealthDetails).State, "vault state")
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+
+	t.Run("bad response", func(t *testing.T) {
+		status, body = 500, "oops"
+		check, err := NewVaultCheck(VaultCheckConfig{CheckName: "vault.check", Address: server.URL})
+		assert.NoError(t, err)
+
+		details, err := check.Execute()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse vault health response (status code: '500')")
+		assert.Equal(t, server.URL, details)
+	})
+}
@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/internal/websocket"
+)
+
+// WebSocketCheckConfig configures a check for a WebSocket endpoint.
+// The only required fields are `CheckName` and `URL`, which must be a valid ws:// or wss:// URL.
+type WebSocketCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is required valid ws:// or wss:// URL, to connect to.
+	URL string
+	// Header is optional, and is added to the handshake request (e.g. authentication, origin, etc.).
+	Header http.Header
+	// Ping indicates when true, that after the handshake the check sends a ping frame, and waits for the pong reply.
+	Ping bool
+	// TLSConfig is optional, and is used for wss:// URLs.
+	TLSConfig *tls.Config
+	// Timeout is the timeout used for the whole check execution (connect, handshake and ping), defaults to "1s".
+	Timeout time.Duration
+}
+
+type webSocketCheck struct {
+	config         *WebSocketCheckConfig
+	dialer         *websocket.Dialer
+	successDetails string
+}
+
+// NewWebSocketCheck creates a new WebSocket check defined by the given config.
+// The check performs the WebSocket handshake, and optionally a ping/pong exchange, within the configured timeout.
+func NewWebSocketCheck(config WebSocketCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.URL == "" {
+		return nil, errors.Errorf("URL must not be empty")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, errors.Errorf("URL scheme must be 'ws' or 'wss', got: '%s'", u.Scheme)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &webSocketCheck{
+		config:         &config,
+		dialer:         &websocket.Dialer{TLSConfig: config.TLSConfig},
+		successDetails: fmt.Sprintf("WebSocket [%s] is accessible", config.URL),
+	}, nil
+}
+
+func (check *webSocketCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *webSocketCheck) Execute() (details interface{}, err error) {
+	details = check.config.URL
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	conn, _, err := check.dialer.Dial(ctx, check.config.URL, check.config.Header)
+	if err != nil {
+		return details, errors.Errorf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if check.config.Ping {
+		if err = ping(conn); err != nil {
+			return details, errors.Errorf("ping failed: %v", err)
+		}
+	}
+
+	return check.successDetails, nil
+}
+
+func ping(conn *websocket.Conn) error {
+	payload := []byte(fmt.Sprintf("%d", time.Now().UnixNano()))
+	if err := conn.WriteMessage(websocket.OpPing, payload); err != nil {
+		return err
+	}
+
+	for {
+		opcode, reply, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case websocket.OpPong:
+			if string(reply) == string(payload) {
+				return nil
+			}
+		case websocket.OpClose:
+			return errors.New("connection closed by peer")
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/internal/websocket"
+)
+
+func TestNewWebSocketCheckRequiredFields(t *testing.T) {
+	check, err := NewWebSocketCheck(WebSocketCheckConfig{
+		URL: "ws://example.org",
+	})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewWebSocketCheck(WebSocketCheckConfig{
+		CheckName: "meh",
+	})
+	assert.Nil(t, check, "nil URL should yield nil check")
+	assert.Error(t, err, "nil URL should yield error")
+
+	check, err = NewWebSocketCheck(WebSocketCheckConfig{
+		CheckName: "meh",
+		URL:       "http://example.org",
+	})
+	assert.Nil(t, check, "non ws URL should yield nil check")
+	assert.Error(t, err, "non ws URL should yield error")
+}
+
+func TestNewWebSocketCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/plain" {
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+
+		conn, err := websocket.Upgrade(rw, req)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for {
+			opcode, payload, err := conn.ReadMessage()
+			if err != nil || opcode == websocket.OpClose {
+				return
+			}
+			if opcode == websocket.OpPing && req.URL.Path != "/mute" {
+				_ = conn.WriteMessage(websocket.OpPong, payload)
+			}
+		}
+	}))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	t.Run("handshake", func(t *testing.T) {
+		check, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: "ws.check", URL: wsURL + "/ws"})
+		assert.NoError(t, err)
+		assert.Equal(t, "ws.check", check.Name(), "check name")
+
+		details, err := check.Execute()
+		assert.NoError(t, err, "check should pass")
+		assert.Equal(t, "WebSocket ["+wsURL+"/ws] is accessible", details)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		check, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: "ws.check", URL: wsURL + "/ws", Ping: true})
+		assert.NoError(t, err)
+
+		_, err = check.Execute()
+		assert.NoError(t, err, "check should pass")
+	})
+
+	t.Run("no pong", func(t *testing.T) {
+		check, err := NewWebSocketCheck(WebSocketCheckConfig{
+			CheckName: "ws.check",
+			URL:       wsURL + "/mute",
+			Ping:      true,
+			Timeout:   50 * time.Millisecond,
+		})
+		assert.NoError(t, err)
+
+		details, err := check.Execute()
+		assert.Error(t, err, "check should fail")
+		assert.Contains(t, err.Error(), "ping failed")
+		assert.Equal(t, wsURL+"/mute", details, "check details when fail are the URL")
+	})
+
+	t.Run("not a websocket", func(t *testing.T) {
+		check, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: "ws.check", URL: wsURL + "/plain"})
+		assert.NoError(t, err)
+
+		_, err = check.Execute()
+		assert.Error(t, err, "check should fail")
+		assert.Contains(t, err.Error(), "unexpected handshake status code: '200' expected: '101'")
+	})
+}
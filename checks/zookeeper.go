@@ -0,0 +1,106 @@
+package checks
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ZooKeeperSession is the minimal view of a ZooKeeper client session used by the ZooKeeper session check.
+// It is easily adapted from common clients (e.g. `github.com/go-zookeeper/zk`'s `*zk.Conn`).
+type ZooKeeperSession interface {
+	// Connected returns true when the client is connected and has an established session.
+	Connected() bool
+	// SessionID returns the current session ID, or 0 when no session was established.
+	SessionID() int64
+	// Exists performs a round trip to the ensemble, checking whether the given path exists.
+	Exists(path string) (bool, error)
+}
+
+// ZooKeeperCheckConfig configures a check for the liveness of a ZooKeeper client session.
+type ZooKeeperCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Session is required, and is the session to check.
+	Session ZooKeeperSession
+	// Path is the path used for the round trip to the ensemble, defaults to "/".
+	Path string
+	// Timeout is the timeout used for the round trip, defaults to "1s".
+	Timeout time.Duration
+}
+
+// ZooKeeperSessionDetails are the details reported by the ZooKeeper session check.
+type ZooKeeperSessionDetails struct {
+	// SessionID is the hexadecimal session ID, as displayed by ZooKeeper's tools.
+	SessionID string `json:"session_id,omitempty"`
+	// SessionChanged is true when the session was re-established since the previous execution,
+	// which means ephemeral nodes and watches of the previous session were lost.
+	SessionChanged bool `json:"session_changed,omitempty"`
+	// Latency is the latency of the round trip to the ensemble, when it was performed.
+	Latency string `json:"latency,omitempty"`
+}
+
+type zooKeeperCheck struct {
+	// lastSession is atomically accessed, so it comes first, for alignment on 32 bit platforms
+	lastSession int64
+	config      *ZooKeeperCheckConfig
+}
+
+// NewZooKeeperCheck creates a new ZooKeeper session check defined by the given config.
+// The check fails when the session is disconnected, or when a round trip to the ensemble fails or times out.
+func NewZooKeeperCheck(config ZooKeeperCheckConfig) (check Check, err error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Session == nil {
+		return nil, errors.Errorf("Session must not be nil")
+	}
+	if config.Path == "" {
+		config.Path = "/"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &zooKeeperCheck{config: &config}, nil
+}
+
+func (check *zooKeeperCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *zooKeeperCheck) Execute() (details interface{}, err error) {
+	session := check.config.Session
+	var result ZooKeeperSessionDetails
+	if !session.Connected() {
+		return result, errors.Errorf("zookeeper session is disconnected")
+	}
+	id := session.SessionID()
+	if id == 0 {
+		return result, errors.Errorf("zookeeper session is not established")
+	}
+	result.SessionID = "0x" + strconv.FormatUint(uint64(id), 16)
+	// hedged executions may run concurrently
+	last := atomic.SwapInt64(&check.lastSession, id)
+	result.SessionChanged = last != 0 && last != id
+
+	start := time.Now()
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := session.Exists(check.config.Path)
+		errChan <- err
+	}()
+	select {
+	case err = <-errChan:
+		if err != nil {
+			return result, errors.Errorf("zookeeper round trip failed: %v", err)
+		}
+	case <-time.After(check.config.Timeout):
+		return result, errors.Errorf("zookeeper round trip timed out after %v", check.config.Timeout)
+	}
+	result.Latency = time.Since(start).String()
+	return result, nil
+}
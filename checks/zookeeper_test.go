@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubZooKeeperSession struct {
+	connected bool
+	sessionID int64
+	existsErr error
+	delay     time.Duration
+	path      atomic.Value
+}
+
+func (s *stubZooKeeperSession) Connected() bool {
+	return s.connected
+}
+
+func (s *stubZooKeeperSession) SessionID() int64 {
+	return s.sessionID
+}
+
+func (s *stubZooKeeperSession) Exists(path string) (bool, error) {
+	s.path.Store(path)
+	time.Sleep(s.delay)
+	return s.existsErr == nil, s.existsErr
+}
+
+func TestNewZooKeeperCheckRequiredFields(t *testing.T) {
+	check, err := NewZooKeeperCheck(ZooKeeperCheckConfig{Session: &stubZooKeeperSession{}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewZooKeeperCheck(ZooKeeperCheckConfig{CheckName: "zk"})
+	assert.Nil(t, check, "nil Session should yield nil check")
+	assert.Error(t, err, "nil Session should yield error")
+}
+
+func TestZooKeeperCheck(t *testing.T) {
+	session := &stubZooKeeperSession{}
+	check, err := NewZooKeeperCheck(ZooKeeperCheckConfig{
+		CheckName: "zk.check",
+		Session:   session,
+		Timeout:   50 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "zk.check", check.Name(), "check name")
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "zookeeper session is disconnected")
+
+	session.connected = true
+	_, err = check.Execute()
+	assert.EqualError(t, err, "zookeeper session is not established")
+
+	session.sessionID = 0x1000a2b3c
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1000a2b3c", details.(ZooKeeperSessionDetails).SessionID)
+	assert.False(t, details.(ZooKeeperSessionDetails).SessionChanged)
+	assert.NotEmpty(t, details.(ZooKeeperSessionDetails).Latency)
+	assert.Equal(t, "/", session.path.Load(), "default path")
+
+	session.sessionID = 0x1000a2b3d
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.True(t, details.(ZooKeeperSessionDetails).SessionChanged, "session should be reported as re-established")
+
+	session.existsErr = errors.New("zk: connection closed")
+	_, err = check.Execute()
+	assert.EqualError(t, err, "zookeeper round trip failed: zk: connection closed")
+
+	session.existsErr, session.delay = nil, time.Second
+	_, err = check.Execute()
+	assert.EqualError(t, err, "zookeeper round trip timed out after 50ms")
+}
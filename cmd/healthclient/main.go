@@ -0,0 +1,15 @@
+// Command healthclient queries a go-sundheit health endpoint and exits with 0 when healthy, and with 1 otherwise.
+// It is meant to be used as a container health check, e.g.:
+//
+//	HEALTHCHECK CMD ["/healthclient", "-url", "http://localhost:8080/admin/health.json"]
+package main
+
+import (
+	"os"
+
+	"github.com/AppsFlyer/go-sundheit/healthclient"
+)
+
+func main() {
+	os.Exit(healthclient.Run(os.Args[1:], os.Stdout))
+}
@@ -0,0 +1,77 @@
+package gosundheit
+
+import (
+	"context"
+	"sync"
+)
+
+// concurrencyGroups serializes the executions of the checks sharing a concurrency group, see Config.ConcurrencyGroup.
+type concurrencyGroups struct {
+	lock   sync.Mutex
+	groups map[string]*concurrencyGroup
+}
+
+type concurrencyGroup struct {
+	slot chan struct{}
+	// waiting are notified once, on the next release, after failing to acquire the group (see tryAcquire)
+	waiting []func()
+}
+
+// group returns the named group. Callers must hold the lock.
+func (g *concurrencyGroups) group(name string) *concurrencyGroup {
+	if g.groups == nil {
+		g.groups = make(map[string]*concurrencyGroup)
+	}
+	group, ok := g.groups[name]
+	if !ok {
+		group = &concurrencyGroup{slot: make(chan struct{}, 1)}
+		g.groups[name] = group
+	}
+	return group
+}
+
+// acquire blocks until the named group is free, and returns false when stop is closed, or the context is done, first.
+func (g *concurrencyGroups) acquire(ctx context.Context, name string, stop <-chan struct{}) bool {
+	g.lock.Lock()
+	slot := g.group(name).slot
+	g.lock.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return true
+	case <-stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// tryAcquire acquires the named group when it is free, without blocking.
+// Otherwise, it returns false, and calls onRelease once the group is released.
+func (g *concurrencyGroups) tryAcquire(name string, onRelease func()) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	group := g.group(name)
+	select {
+	case group.slot <- struct{}{}:
+		return true
+	default:
+		group.waiting = append(group.waiting, onRelease)
+		return false
+	}
+}
+
+// release frees the named group, which must have been acquired, and notifies the callers waiting for it.
+func (g *concurrencyGroups) release(name string) {
+	g.lock.Lock()
+	group := g.group(name)
+	<-group.slot
+	waiting := group.waiting
+	group.waiting = nil
+	g.lock.Unlock()
+
+	for _, onRelease := range waiting {
+		onRelease()
+	}
+}
@@ -0,0 +1,203 @@
+package gosundheit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestConcurrencyGroups(t *testing.T) {
+	var groups concurrencyGroups
+	stop := make(chan struct{})
+	assert.True(t, groups.acquire(context.Background(), "admin", stop))
+	assert.True(t, groups.acquire(context.Background(), "other", stop), "other groups are independent")
+
+	acquired := make(chan bool)
+	go func() { acquired <- groups.acquire(context.Background(), "admin", stop) }()
+	select {
+	case <-acquired:
+		t.Fatal("group must not be acquired twice")
+	case <-time.After(10 * time.Millisecond):
+	}
+	groups.release("admin")
+	assert.True(t, <-acquired, "group is acquired once released")
+
+	released := make(chan struct{})
+	assert.False(t, groups.tryAcquire("admin", func() { close(released) }), "busy groups are not acquired")
+	groups.release("admin")
+	<-released
+	assert.True(t, groups.tryAcquire("admin", func() {}), "free groups are acquired")
+	groups.release("admin")
+	assert.True(t, groups.acquire(context.Background(), "admin", stop))
+
+	go func() { acquired <- groups.acquire(context.Background(), "admin", stop) }()
+	close(stop)
+	assert.False(t, <-acquired, "waiting is aborted once stopped")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { acquired <- groups.acquire(ctx, "admin", make(chan struct{})) }()
+	cancel()
+	assert.False(t, <-acquired, "waiting is aborted once the context is done")
+}
+
+func TestConcurrencyGroup(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"goroutine scheduler": nil,
+		"shared scheduler":    {WithSharedScheduler(4)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := New(opts...)
+			defer h.DeregisterAll()
+
+			var running, maxRunning int32
+			var executions sync.WaitGroup
+			executions.Add(6)
+			serialized := func(name string) *Config {
+				var executed int32
+				return &Config{
+					Check: &checks.CustomCheck{
+						CheckName: name,
+						CheckFunc: func() (details interface{}, err error) {
+							current := atomic.AddInt32(&running, 1)
+							defer atomic.AddInt32(&running, -1)
+							for {
+								max := atomic.LoadInt32(&maxRunning)
+								if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+									break
+								}
+							}
+							time.Sleep(10 * time.Millisecond)
+							if atomic.AddInt32(&executed, 1) <= 2 {
+								executions.Done()
+							}
+							return nil, nil
+						},
+					},
+					ExecutionPeriod:  time.Millisecond,
+					ConcurrencyGroup: "admin.pool",
+				}
+			}
+
+			var unrelated int32
+			assert.NoError(t, h.RegisterChecks(serialized("a"), serialized("b"), serialized("c"), &Config{
+				Check: &checks.CustomCheck{
+					CheckName: "unrelated",
+					CheckFunc: func() (details interface{}, err error) {
+						atomic.AddInt32(&unrelated, 1)
+						return nil, nil
+					},
+				},
+				ExecutionPeriod: time.Millisecond,
+			}))
+
+			executions.Wait()
+			assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning), "checks of a group never execute concurrently")
+			assert.True(t, atomic.LoadInt32(&unrelated) > 6, "unrelated checks execute in parallel")
+		})
+	}
+}
+
+func TestConcurrencyGroup_sharedSchedulerWorkers(t *testing.T) {
+	// more group members than workers must not starve the workers of unrelated checks
+	h := New(WithSharedScheduler(2))
+	defer h.DeregisterAll()
+
+	release := make(chan struct{})
+	defer close(release)
+	member := func(name string) *Config {
+		return &Config{
+			Check: &checks.CustomCheck{
+				CheckName: name,
+				CheckFunc: func() (details interface{}, err error) {
+					<-release
+					return nil, nil
+				},
+			},
+			ExecutionPeriod:  time.Millisecond,
+			ConcurrencyGroup: "admin.pool",
+		}
+	}
+	unrelated := make(chan struct{}, 8)
+	assert.NoError(t, h.RegisterChecks(member("a"), member("b"), member("c"), &Config{
+		Check: &checks.CustomCheck{
+			CheckName: "unrelated",
+			CheckFunc: func() (details interface{}, err error) {
+				select {
+				case unrelated <- struct{}{}:
+				default:
+				}
+				return nil, nil
+			},
+		},
+		ExecutionPeriod: time.Millisecond,
+		InitialDelay:    10 * time.Millisecond,
+	}))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-unrelated:
+		case <-time.After(time.Second):
+			t.Fatal("unrelated checks must execute while the group is busy")
+		}
+	}
+}
+
+func TestConcurrencyGroup_triggerDeadline(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var executed int32
+	assert.NoError(t, h.RegisterChecks(
+		&Config{
+			Check: &checks.CustomCheck{CheckName: "holder", CheckFunc: func() (details interface{}, err error) {
+				close(started)
+				<-release
+				return nil, nil
+			}},
+			ExecutionPeriod:  time.Hour,
+			InitialDelay:     time.Hour,
+			ConcurrencyGroup: "admin.pool",
+		},
+		&Config{
+			Check: &checks.CustomCheck{CheckName: "waiter", CheckFunc: func() (details interface{}, err error) {
+				atomic.AddInt32(&executed, 1)
+				return nil, nil
+			}},
+			ExecutionPeriod:  time.Hour,
+			InitialDelay:     time.Hour,
+			ConcurrencyGroup: "admin.pool",
+		},
+	))
+	go func() { _, _ = h.Trigger("holder") }()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	_, err := h.TriggerContext(ctx, "waiter")
+	assert.EqualError(t, err, "check waiter execution abandoned: context deadline exceeded")
+	assert.True(t, time.Since(begin) < time.Second, "waiting for the group should be bounded by the deadline")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&executed), "abandoned executions don't execute the check")
+}
+
+func TestConcurrencyGroupValidation(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	err := h.RegisterCheck(&Config{
+		Check:            &checks.CustomCheck{CheckName: "hedged"},
+		ExecutionPeriod:  time.Second,
+		HedgeDelay:       time.Millisecond,
+		ConcurrencyGroup: "admin.pool",
+	})
+	assert.EqualError(t, err, "misconfigured check hedged, hedged executions can't be serialized by concurrency group admin.pool")
+}
@@ -1,6 +1,7 @@
 package gosundheit
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/AppsFlyer/go-sundheit/checks"
@@ -10,10 +11,146 @@ import (
 type Config struct {
 	// Check is the health Check to be scheduled for execution.
 	Check checks.Check
-	// ExecutionPeriod is the period between successive executions.
+	// ExecutionPeriod is the period between successive executions, and must be positive.
 	ExecutionPeriod time.Duration
+	// SchedulingMode defines how ExecutionPeriod is measured; defaults to FixedRate.
+	SchedulingMode SchedulingMode
+	// MaxExecutionPeriod enables adaptive scheduling when greater than ExecutionPeriod: once the check passed
+	// StableExecutions times in a row, its period is doubled, up to MaxExecutionPeriod, and after a failure,
+	// its period is reset to ExecutionPeriod. It reduces the steady state load of the checks on their dependencies,
+	// while failing checks are still executed often; defaults to zero (disabled). Must not be negative.
+	MaxExecutionPeriod time.Duration
+	// StableExecutions is the number of passing executions in a row after which the period of an adaptively scheduled check
+	// is doubled; defaults to 5. Degraded executions restart the count. Must not be negative.
+	StableExecutions int
 	// InitialDelay is the time to delay first execution; defaults to zero.
 	InitialDelay time.Duration
 	// InitiallyPassing indicates when true, the check will be treated as passing before the first run; defaults to false
 	InitiallyPassing bool
+	// Classification is an optional classification of the check, e.g. "liveness", "readiness", "startup".
+	Classification string
+	// Description is an optional human readable description of what the check verifies.
+	Description string
+	// RunbookURL is an optional link to the remediation instructions for when the check fails.
+	RunbookURL string
+	// Owner is an optional owner of the check (e.g. a team name), which may be used for routing alerts.
+	Owner string
+	// Annotations are optional arbitrary key/value pairs (e.g. routing keys), which are passed on to the results.
+	// Annotations must not be modified once the check is registered.
+	Annotations map[string]string
+	// DeregisterOnPass indicates when true, the check is deregistered once it passes; defaults to false.
+	// It is useful for one-off checks, e.g. of the "setup" classification, which are not needed once they pass,
+	// and keeps the health responses small over time.
+	DeregisterOnPass bool
+	// TTL is the time after registration at which the check is deregistered automatically; defaults to zero (never).
+	TTL time.Duration
+	// Capabilities are the optional application capabilities (e.g. "writes", "search") the check gates,
+	// which are impacted when the check fails (see Health.ImpactedCapabilities()).
+	Capabilities []string
+	// Weight is the weight of the check in the health score (see Health.Score()); defaults to 1. Must not be negative.
+	Weight float64
+	// Retries is the number of times a failed execution is retried, before its failure is recorded; defaults to zero.
+	// Retries are independent of the recorded ContiguousFailures, which count the failed executions only. Must not be negative.
+	Retries int
+	// RetryDelay is the time to wait before the first retry; defaults to zero.
+	RetryDelay time.Duration
+	// RetryBackoff is the factor the retry delay is multiplied by after each retry, e.g. 2 for an exponential backoff;
+	// defaults to 1 (a constant delay). Must not be lower than 1.
+	RetryBackoff float64
+	// HedgeDelay enables hedged executions when positive: when an execution attempt hasn't completed within HedgeDelay,
+	// a second attempt is launched concurrently, and the outcome of the first attempt to complete is recorded.
+	// The other attempt is abandoned, and completes in the background, so hedged checks must be safe for concurrent execution.
+	// Hedging reduces the result latency variance of checks against flaky networks; defaults to zero (disabled).
+	HedgeDelay time.Duration
+	// ConcurrencyGroup is an optional name of a serialization group: checks sharing a group never execute concurrently
+	// (e.g. checks hitting the same constrained dependency, such as a tiny admin connection pool),
+	// while checks of other groups still execute in parallel. A check waiting for its group executes once the group is free.
+	// Timed out attempts are abandoned, and no longer hold the group. Can't be used together with HedgeDelay.
+	ConcurrencyGroup string
+	// Timeout is the time an execution attempt may take before it is recorded as failed; defaults to zero (no timeout).
+	// The timed out attempt is abandoned, and completes in the background.
+	Timeout time.Duration
+	// Severity defines whether a failure of the check fails the health; defaults to Critical.
+	Severity Severity
+	// Silences are optional daily windows (e.g. maintenance windows), during which the failures of the check are recorded,
+	// but are marked as silenced: they don't affect the health, and don't trigger transition listeners.
+	// Windows are evaluated on each execution, so a failing check is silenced (or unsilenced) at its first execution in (or out of) a window.
+	Silences []SilenceWindow
+	// GracePeriod is the time after registration during which the failures of the check are recorded, but are marked
+	// as within the grace period: they don't affect the health, and don't trigger transition listeners;
+	// defaults to zero (no grace period). It is useful for dependencies that legitimately take a while after deploy,
+	// e.g. cache warmup. The grace period is evaluated on each execution, like the silence windows. Must not be negative.
+	GracePeriod time.Duration
+	// LeaderPolicy defines how the check behaves when this instance is not the leader (see WithLeaderElection);
+	// defaults to AnyInstance.
+	LeaderPolicy LeaderPolicy
+}
+
+// SchedulingMode defines how the period between successive executions of a check is measured.
+type SchedulingMode int
+
+const (
+	// FixedRate checks are executed every ExecutionPeriod, measured from the scheduled time of the previous execution.
+	// When an execution takes longer than the period, the missed executions are skipped rather than executed in a burst.
+	FixedRate SchedulingMode = iota
+	// FixedDelay checks are executed ExecutionPeriod after the previous execution completes.
+	FixedDelay
+)
+
+func (m SchedulingMode) String() string {
+	switch m {
+	case FixedRate:
+		return "FixedRate"
+	case FixedDelay:
+		return "FixedDelay"
+	default:
+		return fmt.Sprintf("SchedulingMode(%d)", int(m))
+	}
+}
+
+// Severity defines whether a failing check fails the health.
+type Severity int
+
+const (
+	// Critical checks fail the health when failing.
+	Critical Severity = iota
+	// Warning checks are reported when failing (e.g. in the results, and the impacted capabilities), but don't fail the health.
+	Warning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Critical:
+		return "Critical"
+	case Warning:
+		return "Warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// LeaderPolicy defines how a check behaves when this instance is not the leader.
+type LeaderPolicy int
+
+const (
+	// AnyInstance checks are executed, and affect the health, regardless of leadership.
+	AnyInstance LeaderPolicy = iota
+	// LeaderOnlyHealth checks are executed on all instances, but affect the health only on the leader.
+	LeaderOnlyHealth
+	// LeaderOnlyExecution checks are executed, and affect the health, only on the leader.
+	// On other instances the executions are skipped.
+	LeaderOnlyExecution
+)
+
+func (p LeaderPolicy) String() string {
+	switch p {
+	case AnyInstance:
+		return "AnyInstance"
+	case LeaderOnlyHealth:
+		return "LeaderOnlyHealth"
+	case LeaderOnlyExecution:
+		return "LeaderOnlyExecution"
+	default:
+		return fmt.Sprintf("LeaderPolicy(%d)", int(p))
+	}
 }
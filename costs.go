@@ -0,0 +1,52 @@
+package gosundheit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Cost is the execution cost of a check, accumulated since its registration.
+type Cost struct {
+	// Executions is the number of executions of the check, including triggered executions
+	Executions int64 `json:"executions"`
+	// TotalDuration is the cumulative wall-clock time of the executions, including retries
+	TotalDuration time.Duration `json:"totalDuration"`
+}
+
+// AverageDuration returns the average wall-clock time of an execution, or 0 when the check wasn't executed yet.
+func (c Cost) AverageDuration() time.Duration {
+	if c.Executions == 0 {
+		return 0
+	}
+	return c.TotalDuration / time.Duration(c.Executions)
+}
+
+// costTracker accumulates the execution cost of a check
+type costTracker struct {
+	executions    int64
+	totalDuration int64
+}
+
+// record accounts for a single execution.
+func (c *costTracker) record(duration time.Duration) {
+	atomic.AddInt64(&c.totalDuration, int64(duration))
+	atomic.AddInt64(&c.executions, 1)
+}
+
+func (c *costTracker) load() Cost {
+	return Cost{
+		Executions:    atomic.LoadInt64(&c.executions),
+		TotalDuration: time.Duration(atomic.LoadInt64(&c.totalDuration)),
+	}
+}
+
+func (h *health) Costs() map[string]Cost {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	costs := make(map[string]Cost, len(h.checkTasks))
+	for name, task := range h.checkTasks {
+		costs[name] = task.cost.load()
+	}
+	return costs
+}
@@ -0,0 +1,60 @@
+package gosundheit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestCosts(t *testing.T) {
+	now := time.Now()
+	h := New(WithTimestampSource(func() time.Time {
+		// each reading of the clock advances it, so each execution takes a single tick
+		now = now.Add(10 * time.Millisecond)
+		return now
+	}))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("db", checks.PassResult("ok"), checks.FailResult(errors.New("down"))),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+	assert.Equal(t, map[string]Cost{"db": {}}, h.Costs())
+	assert.Equal(t, time.Duration(0), h.Costs()["db"].AverageDuration())
+
+	_, err := h.Trigger("db")
+	assert.NoError(t, err)
+	_, err = h.Trigger("db")
+	assert.NoError(t, err)
+
+	cost := h.Costs()["db"]
+	assert.Equal(t, Cost{Executions: 2, TotalDuration: 20 * time.Millisecond}, cost)
+	assert.Equal(t, 10*time.Millisecond, cost.AverageDuration())
+
+	h.Deregister("db")
+	assert.Empty(t, h.Costs())
+}
+
+func TestCosts_retries(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("db", checks.FailResult(errors.New("down")), checks.PassResult("ok")),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		Retries:         1,
+		RetryDelay:      20 * time.Millisecond,
+	}))
+	_, err := h.Trigger("db")
+	assert.NoError(t, err)
+
+	cost := h.Costs()["db"]
+	assert.Equal(t, int64(1), cost.Executions, "retries are accounted for as a single execution")
+	assert.True(t, cost.TotalDuration >= 20*time.Millisecond, "the duration includes the retries")
+}
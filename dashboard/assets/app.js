@@ -0,0 +1,106 @@
+(function () {
+  "use strict";
+
+  function statusOf(result) {
+    if (result.error) {
+      return result.silenced ? "silenced" : "failing";
+    }
+    return result.degraded ? "degraded" : "passing";
+  }
+
+  function text(value) {
+    if (value === undefined || value === null) {
+      return "";
+    }
+    return typeof value === "string" ? value : JSON.stringify(value);
+  }
+
+  function cell(row, content, className) {
+    var td = document.createElement("td");
+    if (className) {
+      var badge = document.createElement("span");
+      badge.className = "badge " + className;
+      badge.textContent = content;
+      td.appendChild(badge);
+    } else {
+      td.textContent = content;
+    }
+    row.appendChild(td);
+  }
+
+  function render(results, healthy) {
+    var body = document.getElementById("checks");
+    body.textContent = "";
+    Object.keys(results).sort().forEach(function (name) {
+      var result = results[name];
+      var status = statusOf(result);
+      var row = document.createElement("tr");
+      cell(row, name);
+      cell(row, status, status);
+      cell(row, result.error ? text(result.error.message) : text(result.message));
+      cell(row, String(result.contiguousFailures || 0));
+      cell(row, result.timestamp ? new Date(result.timestamp).toLocaleString() : "");
+      body.appendChild(row);
+    });
+
+    var overall = document.getElementById("overall");
+    overall.className = "badge " + (healthy ? "passing" : "failing");
+    overall.textContent = healthy ? "healthy" : "unhealthy";
+    document.getElementById("updated").textContent = new Date().toLocaleTimeString();
+    document.getElementById("error").hidden = true;
+  }
+
+  function showError(message) {
+    var error = document.getElementById("error");
+    error.textContent = message;
+    error.hidden = false;
+  }
+
+  function isHealthy(results) {
+    return Object.keys(results).every(function (name) {
+      var result = results[name];
+      return !result.error || result.silenced;
+    });
+  }
+
+  function poll(cfg) {
+    fetch(cfg.healthUrl, { cache: "no-store" })
+      .then(function (response) {
+        return response.json().then(function (results) {
+          render(results, response.ok);
+        });
+      })
+      .catch(function (err) {
+        showError("Failed to fetch the health: " + err);
+      })
+      .then(function () {
+        setTimeout(function () { poll(cfg); }, cfg.pollIntervalMs);
+      });
+  }
+
+  function stream(cfg) {
+    var source = new EventSource(cfg.streamUrl);
+    source.addEventListener("results", function (event) {
+      var results = JSON.parse(event.data);
+      render(results, isHealthy(results));
+    });
+    source.onerror = function () {
+      showError("Disconnected from the health stream, reconnecting...");
+    };
+  }
+
+  fetch("config.json")
+    .then(function (response) { return response.json(); })
+    .then(function (cfg) {
+      document.title = cfg.title;
+      document.getElementById("title").textContent = cfg.title;
+      if (cfg.streamUrl && window.EventSource) {
+        stream(cfg);
+      } else {
+        poll(cfg);
+      }
+    })
+    .catch(function (err) {
+      showError("Failed to load the dashboard configuration: " + err);
+    });
+})();
@@ -0,0 +1,86 @@
+// Package dashboard serves an embedded status page, which renders the live status of the health checks,
+// using the JSON endpoint (see healthhttp.HandleHealthJSON) and, optionally, the Server-Sent Events stream (see healthhttp.ResultsStream).
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+//go:embed assets
+var assets embed.FS
+
+// Option configures the dashboard
+type Option func(*config)
+
+type config struct {
+	Title          string `json:"title"`
+	HealthURL      string `json:"healthUrl"`
+	StreamURL      string `json:"streamUrl,omitempty"`
+	PollIntervalMs int64  `json:"pollIntervalMs"`
+}
+
+// WithTitle sets the page title; defaults to "Health".
+func WithTitle(title string) Option {
+	return func(cfg *config) {
+		cfg.Title = title
+	}
+}
+
+// WithHealthURL sets the URL of the health JSON endpoint, relative to the dashboard page; defaults to "health.json".
+func WithHealthURL(url string) Option {
+	return func(cfg *config) {
+		cfg.HealthURL = url
+	}
+}
+
+// WithStreamURL sets the URL of the results stream endpoint, relative to the dashboard page.
+// When set, the page is updated on each pushed results update, instead of polling the health endpoint.
+func WithStreamURL(url string) Option {
+	return func(cfg *config) {
+		cfg.StreamURL = url
+	}
+}
+
+// WithPollInterval sets the interval the health endpoint is polled at, when no stream is configured; defaults to 5 seconds.
+func WithPollInterval(interval time.Duration) Option {
+	return func(cfg *config) {
+		cfg.PollIntervalMs = interval.Milliseconds()
+	}
+}
+
+// Handler returns an http.Handler serving the dashboard page, and its assets, e.g.:
+//
+//	http.Handle("/admin/health.json", healthhttp.HandleHealthJSON(h))
+//	http.Handle("/admin/status/", http.StripPrefix("/admin/status", dashboard.Handler(dashboard.WithHealthURL("../health.json"))))
+func Handler(opts ...Option) http.Handler {
+	cfg := &config{
+		Title:          "Health",
+		HealthURL:      "health.json",
+		PollIntervalMs: defaultPollInterval.Milliseconds(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.PollIntervalMs <= 0 {
+		cfg.PollIntervalMs = defaultPollInterval.Milliseconds()
+	}
+	encodedConfig, _ := json.Marshal(cfg)
+
+	static, _ := fs.Sub(assets, "assets")
+	files := http.FileServer(http.FS(static))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(encodedConfig)
+	})
+	mux.Handle("/", files)
+	return mux
+}
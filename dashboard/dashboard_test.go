@@ -0,0 +1,56 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, handler http.Handler, path string) (*http.Response, string) {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestHandlerAssets(t *testing.T) {
+	handler := Handler()
+
+	resp, body := get(t, handler, "/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, body, `<script src="app.js"></script>`)
+
+	resp, _ = get(t, handler, "/app.js")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = get(t, handler, "/style.css")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandlerConfig(t *testing.T) {
+	resp, body := get(t, Handler(), "/config.json")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"title": "Health", "healthUrl": "health.json", "pollIntervalMs": 5000}`, body, "default config")
+
+	_, body = get(t, Handler(
+		WithTitle("Orders"),
+		WithHealthURL("../health.json"),
+		WithStreamURL("../health/stream"),
+		WithPollInterval(time.Second),
+	), "/config.json")
+	var cfg config
+	assert.NoError(t, json.Unmarshal([]byte(body), &cfg))
+	assert.Equal(t, config{
+		Title:          "Orders",
+		HealthURL:      "../health.json",
+		StreamURL:      "../health/stream",
+		PollIntervalMs: 1000,
+	}, cfg)
+}
@@ -0,0 +1,55 @@
+// Command example serves a health endpoint, its results stream, and the dashboard status page
+// for a couple of demo checks, on http://localhost:8080/admin/status/
+package main
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+	"github.com/AppsFlyer/go-sundheit/dashboard"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+func main() {
+	stream := healthhttp.NewResultsStream()
+	h := gosundheit.New(gosundheit.WithHealthListeners(stream))
+	defer h.DeregisterAll()
+
+	err := h.RegisterChecks(
+		&gosundheit.Config{
+			Check: &checks.CustomCheck{
+				CheckName: "lottery.check",
+				CheckFunc: func() (interface{}, error) {
+					if rand.Float64() < 0.3 {
+						return nil, errors.New("lost the lottery")
+					}
+					return "won the lottery", nil
+				},
+			},
+			ExecutionPeriod: 3 * time.Second,
+		},
+		&gosundheit.Config{
+			Check:           &checks.CustomCheck{CheckName: "always.passing.check"},
+			ExecutionPeriod: 10 * time.Second,
+		},
+	)
+	if err != nil {
+		log.Fatalf("failed to register the checks: %v", err)
+	}
+
+	http.Handle("/admin/health.json", healthhttp.HandleHealthJSON(h))
+	http.Handle("/admin/health/stream", stream.Handler(h))
+	http.Handle("/admin/status/", http.StripPrefix("/admin/status", dashboard.Handler(
+		dashboard.WithTitle("Example"),
+		dashboard.WithHealthURL("../health.json"),
+		dashboard.WithStreamURL("../health/stream"),
+	)))
+
+	log.Println("serving the dashboard on http://localhost:8080/admin/status/")
+	log.Fatal(http.ListenAndServe(":8080", http.DefaultServeMux))
+}
@@ -0,0 +1,10 @@
+module github.com/AppsFlyer/go-sundheit/dashboard
+
+go 1.16
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
@@ -0,0 +1,150 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// outcomeOf returns the encoding of the outcome of the result, i.e. its details, error and status,
+// ignoring the execution time, duration and counters. Returns false when the details can't be encoded.
+func outcomeOf(result Result) (string, bool) {
+	outcome := struct {
+		Details       interface{} `json:"details,omitempty"`
+		Error         string      `json:"error,omitempty"`
+		Degraded      bool        `json:"degraded,omitempty"`
+		Silenced      bool        `json:"silenced,omitempty"`
+		InGracePeriod bool        `json:"inGracePeriod,omitempty"`
+	}{
+		Details:       result.Details,
+		Degraded:      result.Degraded,
+		Silenced:      result.Silenced,
+		InGracePeriod: result.InGracePeriod,
+	}
+	if result.Error != nil {
+		outcome.Error = result.Error.Error()
+	}
+
+	encoded, err := json.Marshal(outcome)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// outcomeTracker tracks the last outcome of each check
+type outcomeTracker struct {
+	lock     sync.Mutex
+	outcomes map[string]string
+}
+
+// update records the outcome of the result, and returns true when it differs from the previous outcome of the check.
+// Results whose outcome can't be encoded always differ.
+func (t *outcomeTracker) update(name string, result Result) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	outcome, ok := outcomeOf(result)
+	if !ok {
+		delete(t.outcomes, name)
+		return true
+	}
+	if previous, existed := t.outcomes[name]; existed && previous == outcome {
+		return false
+	}
+	if t.outcomes == nil {
+		t.outcomes = make(map[string]string)
+	}
+	t.outcomes[name] = outcome
+	return true
+}
+
+func (t *outcomeTracker) remove(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.outcomes, name)
+}
+
+// UnchangedCheckListener is an optional interface, which a CheckListener wrapped by NewDeduplicatingCheckListener
+// may implement in order to be notified compactly of the executions whose outcome is identical to the previous one.
+type UnchangedCheckListener interface {
+	// OnCheckUnchanged is called instead of OnCheckCompleted, when the outcome of the check execution is identical to the previous one.
+	OnCheckUnchanged(name string)
+}
+
+// NewDeduplicatingCheckListener returns a CheckListener that notifies the given listener of the completed executions
+// only when their outcome (the details, error and status) differs from the previous outcome of the check,
+// cutting the metrics and log volume of stable systems. The executions with an identical outcome are dropped,
+// unless the listener implements UnchangedCheckListener. Registrations, starts, quarantines and deregistrations are always notified.
+func NewDeduplicatingCheckListener(listener CheckListener) CheckListener {
+	return &deduplicatingCheckListener{listener: listener}
+}
+
+type deduplicatingCheckListener struct {
+	listener CheckListener
+	outcomes outcomeTracker
+}
+
+func (l *deduplicatingCheckListener) OnCheckRegistered(name string, result Result) {
+	l.outcomes.update(name, result)
+	l.listener.OnCheckRegistered(name, result)
+}
+
+func (l *deduplicatingCheckListener) OnCheckStarted(name string) {
+	l.listener.OnCheckStarted(name)
+}
+
+func (l *deduplicatingCheckListener) OnCheckCompleted(name string, result Result) {
+	if l.outcomes.update(name, result) {
+		l.listener.OnCheckCompleted(name, result)
+	} else if unchanged, ok := l.listener.(UnchangedCheckListener); ok {
+		unchanged.OnCheckUnchanged(name)
+	}
+}
+
+func (l *deduplicatingCheckListener) OnCheckQuarantined(name string, result Result) {
+	l.outcomes.remove(name)
+	if quarantined, ok := l.listener.(CheckQuarantinedListener); ok {
+		quarantined.OnCheckQuarantined(name, result)
+	}
+}
+
+func (l *deduplicatingCheckListener) OnCheckDeregistered(name string) {
+	l.outcomes.remove(name)
+	if deregistered, ok := l.listener.(CheckDeregisteredListener); ok {
+		deregistered.OnCheckDeregistered(name)
+	}
+}
+
+// NewDeduplicatingHealthListener returns a HealthListener that notifies the given listener of the results updates
+// only when checks were added or removed, or when the outcome (the details, error and status) of any check changed,
+// e.g. for exporters that don't need a notification per execution of stable checks.
+func NewDeduplicatingHealthListener(listener HealthListener) HealthListener {
+	return &deduplicatingHealthListener{listener: listener}
+}
+
+type deduplicatingHealthListener struct {
+	listener HealthListener
+
+	lock     sync.Mutex
+	outcomes map[string]string
+}
+
+func (l *deduplicatingHealthListener) OnResultsUpdated(results map[string]Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	changed := len(results) != len(l.outcomes)
+	outcomes := make(map[string]string, len(results))
+	for name, result := range results {
+		outcome, ok := outcomeOf(result)
+		previous, existed := l.outcomes[name]
+		changed = changed || !ok || !existed || previous != outcome
+		outcomes[name] = outcome
+	}
+	if !changed {
+		return
+	}
+
+	l.outcomes = outcomes
+	l.listener.OnResultsUpdated(results)
+}
@@ -0,0 +1,120 @@
+package gosundheit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+type checkEventsRecorder struct {
+	events []string
+}
+
+func (r *checkEventsRecorder) OnCheckRegistered(name string, _ Result) {
+	r.events = append(r.events, "registered "+name)
+}
+
+func (r *checkEventsRecorder) OnCheckStarted(name string) {
+	r.events = append(r.events, "started "+name)
+}
+
+func (r *checkEventsRecorder) OnCheckCompleted(name string, result Result) {
+	r.events = append(r.events, "completed "+name)
+}
+
+func (r *checkEventsRecorder) OnCheckDeregistered(name string) {
+	r.events = append(r.events, "deregistered "+name)
+}
+
+func (r *checkEventsRecorder) OnCheckQuarantined(name string, _ Result) {
+	r.events = append(r.events, "quarantined "+name)
+}
+
+type unchangedRecorder struct {
+	checkEventsRecorder
+}
+
+func (r *unchangedRecorder) OnCheckUnchanged(name string) {
+	r.events = append(r.events, "unchanged "+name)
+}
+
+func TestDeduplicatingCheckListener(t *testing.T) {
+	recorder := &checkEventsRecorder{}
+	unchanged := &unchangedRecorder{}
+	for _, listener := range []CheckListener{NewDeduplicatingCheckListener(recorder), NewDeduplicatingCheckListener(unchanged)} {
+		listener.OnCheckRegistered("db", Result{Details: initialResultMsg, Error: errors.New(initialResultMsg)})
+		listener.OnCheckCompleted("db", Result{Details: "ok", ContiguousFailures: 0})
+		listener.OnCheckCompleted("db", Result{Details: "ok", Duration: 5})
+		listener.OnCheckCompleted("db", Result{Details: "ok", Degraded: true})
+		listener.OnCheckCompleted("db", Result{Error: errors.New("down"), ContiguousFailures: 1})
+		listener.OnCheckCompleted("db", Result{Error: errors.New("down"), ContiguousFailures: 2})
+		listener.OnCheckCompleted("db", Result{Details: func() {}})
+		listener.OnCheckCompleted("db", Result{Details: func() {}})
+		listener.(CheckDeregisteredListener).OnCheckDeregistered("db")
+		listener.OnCheckStarted("db")
+		listener.OnCheckCompleted("db", Result{Error: errors.New("down")})
+	}
+
+	assert.Equal(t, []string{
+		"registered db",
+		"completed db",
+		"completed db",
+		"completed db",
+		"completed db",
+		"completed db",
+		"deregistered db",
+		"started db",
+		"completed db",
+	}, recorder.events)
+	assert.Equal(t, []string{
+		"registered db",
+		"completed db",
+		"unchanged db",
+		"completed db",
+		"completed db",
+		"unchanged db",
+		"completed db",
+		"completed db",
+		"deregistered db",
+		"started db",
+		"completed db",
+	}, unchanged.events, "results that can't be encoded are never deduplicated")
+}
+
+func TestDeduplicatingCheckListener_quarantine(t *testing.T) {
+	recorder := &checkEventsRecorder{}
+	h := New(WithPanicQuarantine(1), WithCheckListeners(NewDeduplicatingCheckListener(recorder)))
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{CheckName: "panicky", CheckFunc: func() (interface{}, error) {
+			panic("boom")
+		}},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+	_, _ = h.Trigger("panicky")
+
+	assert.Equal(t, []string{"registered panicky", "started panicky", "completed panicky", "quarantined panicky"}, recorder.events,
+		"quarantines are notified through the deduplicating listener")
+}
+
+func TestDeduplicatingHealthListener(t *testing.T) {
+	var updates []map[string]Result
+	listener := NewDeduplicatingHealthListener(healthListenerFunc(func(results map[string]Result) {
+		updates = append(updates, results)
+	}))
+
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok", Generation: 2}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}, "cache": {Details: "ok"}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}, "cache": {Details: "ok"}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}, "cache": {Error: errors.New("down")}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}, "queue": {Error: errors.New("down")}})
+	listener.OnResultsUpdated(map[string]Result{"db": {Details: "ok"}})
+
+	assert.Len(t, updates, 5)
+}
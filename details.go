@@ -0,0 +1,35 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// TruncatedDetails replaces the details of a result whose JSON encoding exceeds the configured size (see WithMaxDetailsSize).
+type TruncatedDetails struct {
+	// Truncated is always true, and indicates the details were truncated
+	Truncated bool `json:"truncated"`
+	// Size is the size of the JSON encoding of the original details, in bytes
+	Size int `json:"size"`
+	// Preview is the prefix of the JSON encoding of the original details, up to the configured size
+	Preview string `json:"preview"`
+}
+
+// limitDetails returns the details, or TruncatedDetails when their JSON encoding is longer than maxSize bytes.
+// Details that can't be encoded are returned as is, so encoding the results reports the error.
+func limitDetails(details interface{}, maxSize int) interface{} {
+	if maxSize <= 0 || details == nil || details == initialResultMsg {
+		return details
+	}
+	encoded, err := json.Marshal(details)
+	if err != nil || len(encoded) <= maxSize {
+		return details
+	}
+
+	preview := encoded[:maxSize]
+	// don't cut a multi-byte character
+	for len(preview) > 0 && !utf8.Valid(preview) {
+		preview = preview[:len(preview)-1]
+	}
+	return TruncatedDetails{Truncated: true, Size: len(encoded), Preview: string(preview)}
+}
@@ -0,0 +1,50 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestLimitDetails(t *testing.T) {
+	assert.Equal(t, "short", limitDetails("short", 10))
+	assert.Equal(t, strings.Repeat("a", 100), limitDetails(strings.Repeat("a", 100), 0), "no cap")
+	assert.Nil(t, limitDetails(nil, 10))
+	assert.Equal(t, initialResultMsg, limitDetails(initialResultMsg, 1), "the initial result is never truncated")
+
+	assert.Equal(t, TruncatedDetails{Truncated: true, Size: 17, Preview: `{"items":[`},
+		limitDetails(map[string][]int{"items": {1, 2, 3}}, 10))
+	// "אב" is 4 bytes long, so the preview isn't cut in the middle of the second character
+	assert.Equal(t, TruncatedDetails{Truncated: true, Size: 8, Preview: `"א`}, limitDetails("אבג", 4))
+}
+
+func TestMaxDetailsSize(t *testing.T) {
+	h := New(WithMaxDetailsSize(16))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("db", checks.PassResult(strings.Repeat("row,", 1000)), checks.PassResult("ok")),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+	results, _ := h.Results()
+	assert.False(t, results["db"].Executed())
+
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.Equal(t, TruncatedDetails{Truncated: true, Size: 4002, Preview: `"row,row,row,row`}, result.Details)
+	var encoded map[string]struct {
+		Message TruncatedDetails `json:"message"`
+	}
+	assert.NoError(t, json.Unmarshal(h.ResultsJSON(), &encoded))
+	assert.Equal(t, result.Details, encoded["db"].Message)
+
+	result, err = h.Trigger("db")
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Details)
+}
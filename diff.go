@@ -0,0 +1,110 @@
+package gosundheit
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ChangeType is the type of a change between two results snapshots
+type ChangeType int
+
+const (
+	// ChangeAdded means the check has a result only in the current snapshot
+	ChangeAdded ChangeType = iota
+	// ChangeRemoved means the check has a result only in the previous snapshot
+	ChangeRemoved
+	// ChangeFailed means the check was passing (or silenced, or within its grace period) in the previous snapshot, and is failing in the current one
+	ChangeFailed
+	// ChangeRecovered means the check was failing in the previous snapshot, and is passing (or silenced, or within its grace period) in the current one
+	ChangeRecovered
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "Added"
+	case ChangeRemoved:
+		return "Removed"
+	case ChangeFailed:
+		return "Failed"
+	case ChangeRecovered:
+		return "Recovered"
+	default:
+		return fmt.Sprintf("ChangeType(%d)", int(t))
+	}
+}
+
+// Change describes the change of a single check between two results snapshots
+type Change struct {
+	// Check is the name of the changed check
+	Check string
+	// Type is the type of the change
+	Type ChangeType
+	// Previous is the result in the previous snapshot; the zero Result when the check was added
+	Previous Result
+	// Current is the result in the current snapshot; the zero Result when the check was removed
+	Current Result
+}
+
+// Diff returns the changes between two results snapshots, sorted by check name:
+// added and removed checks, and checks whose health has changed. Results that changed without a health transition
+// (e.g. a passing check that executed again) are not reported. Silenced failures, and failures within the grace period,
+// count as passing, so a check failing within a silence window is reported as failed only once it's still failing after the window.
+func Diff(prev, curr map[string]Result) []Change {
+	var changes []Change
+	for name, current := range curr {
+		previous, existed := prev[name]
+		switch {
+		case !existed:
+			changes = append(changes, Change{Check: name, Type: ChangeAdded, Current: current})
+		case !previous.affectsHealth() && current.affectsHealth():
+			changes = append(changes, Change{Check: name, Type: ChangeFailed, Previous: previous, Current: current})
+		case previous.affectsHealth() && !current.affectsHealth():
+			changes = append(changes, Change{Check: name, Type: ChangeRecovered, Previous: previous, Current: current})
+		}
+	}
+	for name, previous := range prev {
+		if _, exists := curr[name]; !exists {
+			changes = append(changes, Change{Check: name, Type: ChangeRemoved, Previous: previous})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Check < changes[j].Check
+	})
+	return changes
+}
+
+// TransitionListener is notified of the changes between successive results snapshots.
+// Implementations must not block, as a HealthListener.
+type TransitionListener interface {
+	// OnTransitions is called with the non empty changes of the results, as returned by Diff
+	OnTransitions(changes []Change)
+}
+
+// NewTransitionHealthListener returns a HealthListener that notifies the given listener of the changes between
+// successive results snapshots, e.g. for custom notification logic:
+//
+//	h := gosundheit.New(gosundheit.WithHealthListeners(gosundheit.NewTransitionHealthListener(notifier)))
+func NewTransitionHealthListener(listener TransitionListener) HealthListener {
+	return &transitionHealthListener{listener: listener}
+}
+
+type transitionHealthListener struct {
+	listener TransitionListener
+
+	lock sync.Mutex
+	prev map[string]Result
+}
+
+func (l *transitionHealthListener) OnResultsUpdated(results map[string]Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	changes := Diff(l.prev, results)
+	l.prev = results
+	if len(changes) > 0 {
+		l.listener.OnTransitions(changes)
+	}
+}
@@ -0,0 +1,89 @@
+package gosundheit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestDiff(t *testing.T) {
+	passing := Result{Details: "ok"}
+	failing := Result{Error: errors.New("down")}
+
+	prev := map[string]Result{
+		"stable":    passing,
+		"failing":   passing,
+		"recovered": failing,
+		"removed":   passing,
+	}
+	curr := map[string]Result{
+		"stable":    {Details: "still ok"},
+		"failing":   failing,
+		"recovered": passing,
+		"added":     failing,
+	}
+
+	assert.Equal(t, []Change{
+		{Check: "added", Type: ChangeAdded, Current: failing},
+		{Check: "failing", Type: ChangeFailed, Previous: passing, Current: failing},
+		{Check: "recovered", Type: ChangeRecovered, Previous: failing, Current: passing},
+		{Check: "removed", Type: ChangeRemoved, Previous: passing},
+	}, Diff(prev, curr))
+	assert.Empty(t, Diff(curr, curr), "no changes")
+	assert.Len(t, Diff(nil, curr), len(curr), "all checks are added")
+}
+
+func TestChangeTypeString(t *testing.T) {
+	assert.Equal(t, "Added", ChangeAdded.String())
+	assert.Equal(t, "Removed", ChangeRemoved.String())
+	assert.Equal(t, "Failed", ChangeFailed.String())
+	assert.Equal(t, "Recovered", ChangeRecovered.String())
+	assert.Equal(t, "ChangeType(9)", ChangeType(9).String())
+}
+
+type transitionsRecorder struct {
+	lock    sync.Mutex
+	changes []string
+}
+
+func (r *transitionsRecorder) OnTransitions(changes []Change) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, change := range changes {
+		r.changes = append(r.changes, change.Check+" "+change.Type.String())
+	}
+}
+
+func (r *transitionsRecorder) recorded() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.changes...)
+}
+
+func TestTransitionHealthListener(t *testing.T) {
+	recorder := &transitionsRecorder{}
+	h := New(WithHealthListeners(NewTransitionHealthListener(recorder)))
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("flaky.check",
+			checks.PassResult("ok"),
+			checks.PassResult("ok"),
+			checks.FailResult(errors.New("down")),
+			checks.PassResult("ok"),
+		),
+		ExecutionPeriod: 10 * time.Millisecond,
+	})
+	time.Sleep(45 * time.Millisecond)
+
+	assert.Equal(t, []string{
+		"flaky.check Added",
+		"flaky.check Failed",
+		"flaky.check Recovered",
+	}, recorder.recorded())
+}
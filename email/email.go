@@ -0,0 +1,285 @@
+// Package email notifies on sustained check failures by email, for small teams without a paging infrastructure.
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultThreshold   = 3
+	defaultQuietPeriod = 30 * time.Minute
+	emailsBufferSize   = 16
+)
+
+// DefaultSubjectTemplate is the default template of the email subject.
+var DefaultSubjectTemplate = template.Must(template.New("subject").Parse(
+	`{{if .Source}}[{{.Source}}] {{end}}{{len .Failing}} health check(s) failing`))
+
+// DefaultBodyTemplate is the default template of the email body, listing the failing checks and the results table.
+var DefaultBodyTemplate = template.Must(template.New("body").Parse(`The following checks failed at least {{.Threshold}} times in a row:
+{{range .Failing}}
+  - {{.Name}}: {{.ErrorMessage}}{{if .TimeOfFirstFailure}} (failing since {{.TimeOfFirstFailure.Format "2006-01-02T15:04:05Z07:00"}}){{end}}{{if .RunbookURL}}
+    runbook: {{.RunbookURL}}{{end}}{{end}}
+
+Results:
+{{range .Results}}
+  {{printf "%-8s" .Status}} {{.Name}}{{if .Error}}: {{.ErrorMessage}}{{end}}{{end}}
+`))
+
+// CheckResult is the result of a named check, as exposed to the templates.
+type CheckResult struct {
+	Name string
+	gosundheit.Result
+}
+
+// Status returns the status of the check: PASS, DEGRADED, SILENCED or FAIL.
+func (r CheckResult) Status() string {
+	switch {
+	case r.IsHealthy() && r.Degraded:
+		return "DEGRADED"
+	case r.IsHealthy():
+		return "PASS"
+	case r.Silenced || r.InGracePeriod:
+		return "SILENCED"
+	default:
+		return "FAIL"
+	}
+}
+
+// ErrorMessage returns the error message of the check, or an empty string when the check passed.
+func (r CheckResult) ErrorMessage() string {
+	if r.Error == nil {
+		return ""
+	}
+	return r.Error.Error()
+}
+
+// Notification is the data of the subject and body templates.
+type Notification struct {
+	// Source identifies the notifying service, see WithSource
+	Source string
+	// Threshold is the number of contiguous failures of a sustained failure
+	Threshold int64
+	// Timestamp is the time of the notification
+	Timestamp time.Time
+	// Failing are the checks failing in a sustained manner, sorted by name
+	Failing []CheckResult
+	// Results are the results of all the checks, sorted by name
+	Results []CheckResult
+}
+
+// Sender sends an email message.
+type Sender interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// SMTPSender sends the messages using the SMTP server at Addr (host:port), authenticating with Auth when not nil.
+type SMTPSender struct {
+	Addr string
+	Auth smtp.Auth
+}
+
+// Send sends the message using smtp.SendMail
+func (s SMTPSender) Send(from string, to []string, msg []byte) error {
+	return smtp.SendMail(s.Addr, s.Auth, from, to, msg)
+}
+
+// Option configures the Listener
+type Option func(*Listener)
+
+// WithSource sets the source of the notifications, e.g. the service and instance names, which is included in the default subject.
+func WithSource(source string) Option {
+	return func(l *Listener) {
+		l.source = source
+	}
+}
+
+// WithThreshold sets the number of contiguous failures after which a failure is sustained; defaults to 3.
+func WithThreshold(threshold int64) Option {
+	return func(l *Listener) {
+		l.threshold = threshold
+	}
+}
+
+// WithQuietPeriod sets the minimal duration between emails; defaults to 30m.
+// Checks that start failing within the quiet period are notified on the first results update after it ends.
+func WithQuietPeriod(quietPeriod time.Duration) Option {
+	return func(l *Listener) {
+		l.quietPeriod = quietPeriod
+	}
+}
+
+// WithTemplates sets the subject and body templates, which are executed with a Notification.
+func WithTemplates(subject, body *template.Template) Option {
+	return func(l *Listener) {
+		l.subject = subject
+		l.body = body
+	}
+}
+
+// WithErrorHandler sets a handler of the rendering and sending errors, which are discarded by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Listener) {
+		l.onError = handler
+	}
+}
+
+// Listener is a gosundheit.HealthListener that emails the recipients once checks fail in a sustained manner,
+// i.e. once they fail a threshold number of times in a row. Silenced failures, and failures within the grace period, are ignored.
+// Each failing check is notified once, until it recovers, and no more than one email is sent per quiet period.
+// Emails are sent in the background, so the checks are never blocked by the SMTP server.
+type Listener struct {
+	sender      Sender
+	from        string
+	to          []string
+	source      string
+	threshold   int64
+	quietPeriod time.Duration
+	subject     *template.Template
+	body        *template.Template
+	onError     func(error)
+	now         func() time.Time
+
+	lock     sync.Mutex
+	notified map[string]bool
+	lastSent time.Time
+	emails   chan []byte
+	closed   bool
+	done     chan struct{}
+}
+
+// NewListener returns a Listener emailing from the given address to the given recipients using sender,
+// which should be closed once the health is no longer used.
+func NewListener(sender Sender, from string, to []string, opts ...Option) *Listener {
+	l := &Listener{
+		sender:      sender,
+		from:        from,
+		to:          to,
+		threshold:   defaultThreshold,
+		quietPeriod: defaultQuietPeriod,
+		subject:     DefaultSubjectTemplate,
+		body:        DefaultBodyTemplate,
+		onError:     func(error) {},
+		now:         time.Now,
+		notified:    make(map[string]bool),
+		emails:      make(chan []byte, emailsBufferSize),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	go l.send()
+	return l
+}
+
+// OnResultsUpdated enqueues an email when checks started failing in a sustained manner, and the quiet period has passed.
+func (l *Listener) OnResultsUpdated(results map[string]gosundheit.Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+
+	notification := Notification{Source: l.source, Threshold: l.threshold, Timestamp: l.now()}
+	pending := false
+	for name, result := range results {
+		checkResult := CheckResult{Name: name, Result: result}
+		notification.Results = append(notification.Results, checkResult)
+		if result.IsHealthy() || result.Silenced || result.InGracePeriod || result.ContiguousFailures < l.threshold {
+			delete(l.notified, name)
+			continue
+		}
+		notification.Failing = append(notification.Failing, checkResult)
+		pending = pending || !l.notified[name]
+	}
+	for name := range l.notified {
+		if _, ok := results[name]; !ok {
+			delete(l.notified, name)
+		}
+	}
+	if !pending || (!l.lastSent.IsZero() && notification.Timestamp.Sub(l.lastSent) < l.quietPeriod) {
+		return
+	}
+
+	msg, err := l.render(notification)
+	if err != nil {
+		l.onError(err)
+		return
+	}
+	for _, failing := range notification.Failing {
+		l.notified[failing.Name] = true
+	}
+	l.lastSent = notification.Timestamp
+	l.enqueue(msg)
+}
+
+func (l *Listener) render(notification Notification) ([]byte, error) {
+	byName := func(checks []CheckResult) func(i, j int) bool {
+		return func(i, j int) bool { return checks[i].Name < checks[j].Name }
+	}
+	sort.Slice(notification.Failing, byName(notification.Failing))
+	sort.Slice(notification.Results, byName(notification.Results))
+
+	var subject, body bytes.Buffer
+	if err := l.subject.Execute(&subject, notification); err != nil {
+		return nil, err
+	}
+	if err := l.body.Execute(&body, notification); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", l.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(l.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.Join(strings.Fields(subject.String()), " "))
+	fmt.Fprintf(&msg, "Date: %s\r\n", notification.Timestamp.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.Replace(body.String(), "\n", "\r\n", -1))
+	return msg.Bytes(), nil
+}
+
+// enqueue adds the email to the queue, dropping the oldest email when the queue is full. Callers must hold the lock.
+func (l *Listener) enqueue(msg []byte) {
+	for {
+		select {
+		case l.emails <- msg:
+			return
+		default:
+			select {
+			case <-l.emails:
+			default:
+			}
+		}
+	}
+}
+
+func (l *Listener) send() {
+	defer close(l.done)
+	for msg := range l.emails {
+		if err := l.sender.Send(l.from, l.to, msg); err != nil {
+			l.onError(err)
+		}
+	}
+}
+
+// Close stops accepting results updates, and returns once the pending emails are sent.
+func (l *Listener) Close() {
+	l.lock.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.emails)
+	}
+	l.lock.Unlock()
+	<-l.done
+}
@@ -0,0 +1,125 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+type recordingSender struct {
+	lock sync.Mutex
+	msgs []string
+	err  error
+}
+
+func (s *recordingSender) Send(from string, to []string, msg []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.msgs = append(s.msgs, from+" -> "+strings.Join(to, ",")+"\n"+string(msg))
+	return s.err
+}
+
+func TestListener(t *testing.T) {
+	sender := &recordingSender{}
+	listener := NewListener(sender, "health@example.org", []string{"team@example.org"},
+		WithSource("orders-1"), WithThreshold(2), WithQuietPeriod(time.Hour))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	listener.now = func() time.Time { return now }
+
+	down := errors.New("down")
+	since := now.Add(-time.Minute)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 1},
+		"cache": {Error: down, ContiguousFailures: 5, Silenced: true},
+		"queue": {Degraded: true},
+	})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 2, TimeOfFirstFailure: &since, RunbookURL: "https://runbooks/db"},
+		"cache": {Error: down, ContiguousFailures: 6, Silenced: true},
+		"queue": {Degraded: true},
+	})
+	// db was already notified, and the quiet period didn't pass yet
+	now = now.Add(time.Minute)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 3},
+		"queue": {Error: down, ContiguousFailures: 2},
+	})
+	now = now.Add(time.Hour)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 4},
+		"queue": {Error: down, ContiguousFailures: 3},
+	})
+	// nothing new to notify
+	now = now.Add(time.Hour)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 5},
+		"queue": {Error: down, ContiguousFailures: 4},
+	})
+	listener.Close()
+
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"cache": {Error: down, ContiguousFailures: 10}})
+
+	if assert.Len(t, sender.msgs, 2) {
+		assert.Equal(t, "health@example.org -> team@example.org\n"+
+			"From: health@example.org\r\n"+
+			"To: team@example.org\r\n"+
+			"Subject: [orders-1] 1 health check(s) failing\r\n"+
+			"Date: Wed, 01 Jan 2020 00:00:00 +0000\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=UTF-8\r\n"+
+			"\r\n"+
+			"The following checks failed at least 2 times in a row:\r\n"+
+			"\r\n"+
+			"  - db: down (failing since 2019-12-31T23:59:00Z)\r\n"+
+			"    runbook: https://runbooks/db\r\n"+
+			"\r\n"+
+			"Results:\r\n"+
+			"\r\n"+
+			"  SILENCED cache: down\r\n"+
+			"  FAIL     db: down\r\n"+
+			"  DEGRADED queue\r\n", sender.msgs[0])
+		assert.Contains(t, sender.msgs[1], "Subject: [orders-1] 2 health check(s) failing\r\n")
+		assert.Contains(t, sender.msgs[1], "  - queue: down\r\n")
+	}
+}
+
+func TestListener_recovery(t *testing.T) {
+	sender := &recordingSender{}
+	listener := NewListener(sender, "health@example.org", []string{"team@example.org"}, WithQuietPeriod(0))
+
+	failing := map[string]gosundheit.Result{"db": {Error: errors.New("down"), ContiguousFailures: 3}}
+	listener.OnResultsUpdated(failing)
+	listener.OnResultsUpdated(failing)
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {}})
+	listener.OnResultsUpdated(failing)
+	listener.Close()
+
+	assert.Len(t, sender.msgs, 2, "a recovered check is notified once it fails again")
+}
+
+func TestListener_errors(t *testing.T) {
+	sender := &recordingSender{err: errors.New("connection refused")}
+	errs := make(chan error, 2)
+	failing := map[string]gosundheit.Result{"db": {Error: errors.New("down"), ContiguousFailures: 3}}
+
+	listener := NewListener(sender, "health@example.org", []string{"team@example.org"},
+		WithErrorHandler(func(err error) { errs <- err }))
+	listener.OnResultsUpdated(failing)
+	listener.Close()
+	assert.EqualError(t, <-errs, "connection refused")
+
+	broken := template.Must(template.New("subject").Parse("{{.Missing}}"))
+	listener = NewListener(sender, "health@example.org", []string{"team@example.org"},
+		WithTemplates(broken, DefaultBodyTemplate), WithErrorHandler(func(err error) { errs <- err }))
+	listener.OnResultsUpdated(failing)
+	listener.Close()
+	assert.Error(t, <-errs, "template error")
+	assert.Len(t, sender.msgs, 1)
+}
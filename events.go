@@ -0,0 +1,109 @@
+package gosundheit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const eventsBufferSize = 256
+
+// EventType is the type of a check Event
+type EventType string
+
+// Check event types
+const (
+	// EventRegistered is emitted when a check is registered
+	EventRegistered EventType = "registered"
+	// EventStarted is emitted when a check execution starts
+	EventStarted EventType = "started"
+	// EventCompleted is emitted when a check execution passes
+	EventCompleted EventType = "completed"
+	// EventFailed is emitted when a check execution fails
+	EventFailed EventType = "failed"
+	// EventDeregistered is emitted when a check is deregistered
+	EventDeregistered EventType = "deregistered"
+	// EventQuarantined is emitted when a check is quarantined after panicking repeatedly (see WithPanicQuarantine)
+	EventQuarantined EventType = "quarantined"
+)
+
+// Event is a check lifecycle event, as delivered by Health.Events()
+type Event struct {
+	// Type is the event type
+	Type EventType
+	// Check is the check name
+	Check string
+	// Result is the check result; it is set for registered, completed, failed and quarantined events only
+	Result Result
+	// Time is the time the event was emitted at
+	Time time.Time
+}
+
+// eventsEmitter is a CheckListener that emits the check events to the events channel.
+// Events are emitted only once the channel was requested, and the oldest events are dropped when the channel is full.
+type eventsEmitter struct {
+	subscribed int32
+	once       sync.Once
+	lock       sync.Mutex
+	events     chan Event
+	now        func() time.Time
+}
+
+func (e *eventsEmitter) channel() <-chan Event {
+	e.once.Do(func() {
+		e.events = make(chan Event, eventsBufferSize)
+		atomic.StoreInt32(&e.subscribed, 1)
+	})
+	return e.events
+}
+
+func (e *eventsEmitter) emit(eventType EventType, name string, result Result) {
+	if atomic.LoadInt32(&e.subscribed) == 0 {
+		return
+	}
+
+	event := Event{Type: eventType, Check: name, Result: result, Time: e.now()}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for {
+		select {
+		case e.events <- event:
+			return
+		default:
+			// the channel is full - drop the oldest event to make room
+			select {
+			case <-e.events:
+			default:
+			}
+		}
+	}
+}
+
+func (e *eventsEmitter) OnCheckRegistered(name string, result Result) {
+	e.emit(EventRegistered, name, result)
+}
+
+func (e *eventsEmitter) OnCheckStarted(name string) {
+	e.emit(EventStarted, name, Result{})
+}
+
+func (e *eventsEmitter) OnCheckCompleted(name string, result Result) {
+	if result.IsHealthy() {
+		e.emit(EventCompleted, name, result)
+	} else {
+		e.emit(EventFailed, name, result)
+	}
+}
+
+func (e *eventsEmitter) OnCheckDeregistered(name string) {
+	e.emit(EventDeregistered, name, Result{})
+}
+
+func (e *eventsEmitter) OnCheckQuarantined(name string, result Result) {
+	e.emit(EventQuarantined, name, result)
+}
+
+func (h *health) Events() <-chan Event {
+	return h.events.channel()
+}
@@ -0,0 +1,55 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	h := New()
+	events := h.Events()
+	assert.True(t, events == h.Events(), "the same channel should be returned on each call")
+
+	registerCheck(h, failingCheckName, false, false)
+	registered := awaitEvent(t, events, true)
+	assert.Equal(t, EventRegistered, registered.Type)
+	assert.Equal(t, initialResultMsg, registered.Result.Details)
+	assert.Equal(t, Event{Type: EventStarted, Check: failingCheckName}, awaitEvent(t, events, false))
+	failed := awaitEvent(t, events, true)
+	assert.Equal(t, EventFailed, failed.Type)
+	assert.Equal(t, "failed; i=1", failed.Result.Details)
+
+	h.DeregisterAll()
+	assert.Equal(t, Event{Type: EventDeregistered, Check: failingCheckName}, awaitEvent(t, events, false))
+}
+
+func TestEventsEmitter_dropOldest(t *testing.T) {
+	emitter := &eventsEmitter{now: time.Now}
+	emitter.OnCheckStarted("dropped")
+	assert.Len(t, emitter.events, 0, "events should not be emitted before subscribing")
+
+	events := emitter.channel()
+	for i := 0; i < eventsBufferSize+1; i++ {
+		emitter.OnCheckCompleted(passingCheckName, Result{Details: i})
+	}
+	assert.Len(t, events, eventsBufferSize, "buffered events")
+	assert.Equal(t, 1, (<-events).Result.Details, "the oldest event should be dropped")
+}
+
+// awaitEvent returns the next event, without its time, and optionally without its result
+func awaitEvent(t *testing.T, events <-chan Event, keepResult bool) Event {
+	select {
+	case event := <-events:
+		assert.False(t, event.Time.IsZero(), "event time")
+		event.Time = time.Time{}
+		if !keepResult {
+			event.Result = Result{}
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
@@ -0,0 +1,308 @@
+// Package gossip lets instances exchange health summaries with their peers over HTTP,
+// so each node exposes a cluster-wide health view without a central aggregator.
+//
+// Each node periodically pushes its view of the cluster (the latest known summary of each node) to a few random peers,
+// which merge it into their own view, and reply with theirs (push-pull), so the views converge within a few rounds.
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	defaultFanout   = 2
+	maxViewSize     = 4 << 20
+)
+
+// CheckSummary is the summary of the result of a single check
+type CheckSummary struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NodeHealth is the health summary of a single node
+type NodeHealth struct {
+	// Node is the name of the node
+	Node string `json:"node"`
+	// Healthy is the health of the node
+	Healthy bool `json:"healthy"`
+	// Checks are the summaries of the node check results
+	Checks map[string]CheckSummary `json:"checks"`
+	// Version orders the summaries of the node; it's set by the node from its own clock
+	Version int64 `json:"version"`
+}
+
+// Option configures the Gossiper
+type Option func(*Gossiper)
+
+// WithPeers sets the base URLs of the peers, where their gossip handlers are served, e.g. "http://10.0.0.2:8080/admin/gossip".
+func WithPeers(peers ...string) Option {
+	return func(g *Gossiper) {
+		g.peers = peers
+	}
+}
+
+// WithInterval sets the interval between gossip rounds; defaults to 5 seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(g *Gossiper) {
+		g.interval = interval
+	}
+}
+
+// WithFanout sets the number of random peers gossiped with on each round; defaults to 2.
+func WithFanout(fanout int) Option {
+	return func(g *Gossiper) {
+		g.fanout = fanout
+	}
+}
+
+// WithExpiry sets the time after which a node that wasn't heard of is removed from the view; defaults to 10 intervals.
+// Once removed, the versions of the node that are not newer than the removed one are ignored for another expiry,
+// so peers that didn't remove the node yet don't gossip it back into the view.
+func WithExpiry(expiry time.Duration) Option {
+	return func(g *Gossiper) {
+		g.expiry = expiry
+	}
+}
+
+// WithClient sets the HTTP client used to gossip with the peers; defaults to a client with a timeout of one interval.
+func WithClient(client *http.Client) Option {
+	return func(g *Gossiper) {
+		g.client = client
+	}
+}
+
+// Gossiper exchanges the health summaries of the local Health instance, and of the rest of the cluster, with its peers.
+type Gossiper struct {
+	health   gosundheit.Health
+	node     string
+	peers    []string
+	interval time.Duration
+	fanout   int
+	expiry   time.Duration
+	client   *http.Client
+
+	lock       sync.Mutex
+	view       map[string]*entry
+	tombstones map[string]tombstone
+}
+
+type entry struct {
+	health NodeHealth
+	// updated is the local time the latest version was received at
+	updated time.Time
+}
+
+// tombstone is the latest version of an expired node
+type tombstone struct {
+	version int64
+	// expired is the local time the node expired at
+	expired time.Time
+}
+
+// New returns a Gossiper of the given Health instance, named node in the cluster view.
+// The Gossiper handler must be served (see Handler()), and its gossip rounds must be started (see Run()).
+func New(h gosundheit.Health, node string, opts ...Option) *Gossiper {
+	g := &Gossiper{
+		health:     h,
+		node:       node,
+		interval:   defaultInterval,
+		fanout:     defaultFanout,
+		view:       make(map[string]*entry),
+		tombstones: make(map[string]tombstone),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.interval <= 0 {
+		g.interval = defaultInterval
+	}
+	if g.fanout <= 0 {
+		g.fanout = defaultFanout
+	}
+	if g.expiry <= 0 {
+		g.expiry = 10 * g.interval
+	}
+	if g.client == nil {
+		g.client = &http.Client{Timeout: g.interval}
+	}
+	return g
+}
+
+// ClusterResults returns the latest known health summary of each node in the cluster, including this one, by node name.
+func (g *Gossiper) ClusterResults() map[string]NodeHealth {
+	view := g.currentView()
+	results := make(map[string]NodeHealth, len(view))
+	for _, health := range view {
+		results[health.Node] = health
+	}
+	return results
+}
+
+// Handler returns the handler peers gossip with: it merges the POSTed view, and responds with the merged view.
+func (g *Gossiper) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var view []NodeHealth
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxViewSize)).Decode(&view); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		g.merge(view)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g.currentView())
+	})
+}
+
+// Run gossips with the peers on each interval, until the context is done, and returns the context error.
+// Failing to gossip with a peer doesn't stop the gossip; the peer will be gossiped with on another round.
+func (g *Gossiper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.round(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// round gossips with random peers
+func (g *Gossiper) round(ctx context.Context) {
+	view := g.currentView()
+	var wg sync.WaitGroup
+	for _, peer := range g.randomPeers() {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			if received, err := g.exchange(ctx, peer, view); err == nil {
+				g.merge(received)
+			}
+		}(peer)
+	}
+	wg.Wait()
+}
+
+// exchange pushes the view to the peer, and returns the view it responds with
+func (g *Gossiper) exchange(ctx context.Context, peer string, view []NodeHealth) ([]NodeHealth, error) {
+	body, err := json.Marshal(view)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode the view")
+	}
+	req, err := http.NewRequest(http.MethodPost, peer, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid peer %s", peer)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to gossip with %s", peer)
+	}
+	defer resp.Body.Close()
+	defer func() { _, _ = io.Copy(ioutil.Discard, resp.Body) }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to gossip with %s, status %d", peer, resp.StatusCode)
+	}
+	var received []NodeHealth
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxViewSize)).Decode(&received); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the view of %s", peer)
+	}
+	return received, nil
+}
+
+func (g *Gossiper) randomPeers() []string {
+	peers := append([]string(nil), g.peers...)
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > g.fanout {
+		peers = peers[:g.fanout]
+	}
+	return peers
+}
+
+// merge keeps the latest version of each node health, ignoring the summaries of this node,
+// and the versions of expired nodes that are not newer than the expired one
+func (g *Gossiper) merge(view []NodeHealth) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	now := time.Now()
+	for _, health := range view {
+		if health.Node == "" || health.Node == g.node {
+			continue
+		}
+		if current, ok := g.view[health.Node]; ok && current.health.Version >= health.Version {
+			continue
+		}
+		if tombstone, ok := g.tombstones[health.Node]; ok {
+			if tombstone.version >= health.Version {
+				continue
+			}
+			delete(g.tombstones, health.Node)
+		}
+		g.view[health.Node] = &entry{health: health, updated: now}
+	}
+}
+
+// currentView refreshes the summary of this node, drops the expired nodes, and returns the view
+func (g *Gossiper) currentView() []NodeHealth {
+	local := g.localHealth()
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	now := time.Now()
+	view := []NodeHealth{local}
+	for node, entry := range g.view {
+		if now.Sub(entry.updated) > g.expiry {
+			delete(g.view, node)
+			g.tombstones[node] = tombstone{version: entry.health.Version, expired: now}
+			continue
+		}
+		view = append(view, entry.health)
+	}
+	for node, tombstone := range g.tombstones {
+		if now.Sub(tombstone.expired) > g.expiry {
+			delete(g.tombstones, node)
+		}
+	}
+	return view
+}
+
+func (g *Gossiper) localHealth() NodeHealth {
+	results, healthy := g.health.Results()
+	checks := make(map[string]CheckSummary, len(results))
+	for name, result := range results {
+		summary := CheckSummary{Healthy: result.IsHealthy()}
+		if result.Error != nil {
+			summary.Error = result.Error.Error()
+		}
+		checks[name] = summary
+	}
+	return NodeHealth{
+		Node:    g.node,
+		Healthy: healthy,
+		Checks:  checks,
+		Version: time.Now().UnixNano(),
+	}
+}
@@ -0,0 +1,131 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+type node struct {
+	health   *gosundheittest.FakeHealth
+	gossiper *Gossiper
+	server   *httptest.Server
+}
+
+func newNode(name string, opts ...Option) *node {
+	n := &node{health: gosundheittest.NewFakeHealth()}
+	n.health.SetPassing(name + ".check")
+	n.gossiper = New(n.health, name, opts...)
+	n.server = httptest.NewServer(n.gossiper.Handler())
+	return n
+}
+
+func TestGossip(t *testing.T) {
+	c := newNode("c")
+	defer c.server.Close()
+	b := newNode("b", WithPeers(c.server.URL))
+	defer b.server.Close()
+	a := newNode("a", WithPeers(b.server.URL))
+	defer a.server.Close()
+	c.health.SetFailing("c.check", errors.New("down"))
+
+	ctx := context.Background()
+	a.gossiper.round(ctx)
+	b.gossiper.round(ctx)
+	a.gossiper.round(ctx)
+
+	for _, n := range []*node{a, b, c} {
+		cluster := n.gossiper.ClusterResults()
+		assert.Len(t, cluster, 3, "all nodes should be known")
+		assert.True(t, cluster["a"].Healthy)
+		assert.True(t, cluster["b"].Healthy)
+		assert.False(t, cluster["c"].Healthy)
+		assert.Equal(t, CheckSummary{Healthy: false, Error: "down"}, cluster["c"].Checks["c.check"])
+	}
+
+	c.health.SetPassing("c.check")
+	b.gossiper.round(ctx)
+	a.gossiper.round(ctx)
+	assert.True(t, a.gossiper.ClusterResults()["c"].Healthy, "newer summaries should replace older ones")
+}
+
+func TestRun(t *testing.T) {
+	b := newNode("b")
+	defer b.server.Close()
+	a := newNode("a", WithPeers(b.server.URL, "http://127.0.0.1:1/unreachable"), WithInterval(10*time.Millisecond))
+	defer a.server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, a.gossiper.Run(ctx))
+	assert.Len(t, b.gossiper.ClusterResults(), 2, "unreachable peers should not stop the gossip")
+}
+
+func TestExpiry(t *testing.T) {
+	b := newNode("b")
+	defer b.server.Close()
+	a := newNode("a", WithPeers(b.server.URL), WithExpiry(20*time.Millisecond))
+	defer a.server.Close()
+
+	a.gossiper.round(context.Background())
+	assert.Len(t, a.gossiper.ClusterResults(), 2)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(t, a.gossiper.ClusterResults(), 1, "nodes that weren't heard of should expire")
+}
+
+func TestExpiry_stoppedNode(t *testing.T) {
+	ctx := context.Background()
+	b := newNode("b", WithExpiry(100*time.Millisecond))
+	defer b.server.Close()
+	a := newNode("a", WithPeers(b.server.URL), WithExpiry(100*time.Millisecond))
+	defer a.server.Close()
+	b.gossiper.peers = []string{a.server.URL}
+	c := newNode("c", WithPeers(a.server.URL))
+
+	c.gossiper.round(ctx)
+	time.Sleep(60 * time.Millisecond)
+	a.gossiper.round(ctx)
+	assert.Len(t, b.gossiper.ClusterResults(), 3, "b hears of c later than a")
+	c.server.Close()
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Len(t, a.gossiper.ClusterResults(), 2, "a should expire c")
+	a.gossiper.round(ctx)
+	b.gossiper.round(ctx)
+	assert.NotContains(t, a.gossiper.ClusterResults(), "c", "b should not gossip the expired version of c back to a")
+
+	time.Sleep(60 * time.Millisecond)
+	a.gossiper.round(ctx)
+	b.gossiper.round(ctx)
+	assert.NotContains(t, a.gossiper.ClusterResults(), "c", "c should leave the cluster view")
+	assert.NotContains(t, b.gossiper.ClusterResults(), "c", "c should leave the cluster view")
+
+	restarted := newNode("c", WithPeers(a.server.URL))
+	defer restarted.server.Close()
+	restarted.gossiper.round(ctx)
+	assert.Contains(t, a.gossiper.ClusterResults(), "c", "newer versions of expired nodes should be accepted")
+}
+
+func TestHandlerInvalidRequests(t *testing.T) {
+	a := newNode("a")
+	defer a.server.Close()
+
+	resp, err := http.Get(a.server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Post(a.server.URL, "application/json", strings.NewReader("{"))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,69 @@
+package gosundheittest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const pollInterval = 5 * time.Millisecond
+
+// EventuallyHealthy fails the test when h doesn't become healthy within the timeout.
+func EventuallyHealthy(t testing.TB, h gosundheit.Health, timeout time.Duration) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := h.WaitForHealthy(ctx); err != nil {
+		results, _ := h.Results()
+		t.Fatalf("health did not become healthy within %s; results: %v", timeout, results)
+	}
+}
+
+// EventuallyUnhealthy fails the test when h doesn't become unhealthy within the timeout.
+func EventuallyUnhealthy(t testing.TB, h gosundheit.Health, timeout time.Duration) {
+	t.Helper()
+
+	if !eventually(timeout, func() bool { return !h.IsHealthy() }) {
+		t.Fatalf("health did not become unhealthy within %s", timeout)
+	}
+}
+
+// EventuallyPassing fails the test when the check with the given name doesn't pass within the timeout.
+func EventuallyPassing(t testing.TB, h gosundheit.Health, name string, timeout time.Duration) {
+	t.Helper()
+
+	if !eventually(timeout, func() bool { return checkHealthy(h, name, true) }) {
+		t.Fatalf("check %s did not pass within %s", name, timeout)
+	}
+}
+
+// EventuallyFailing fails the test when the check with the given name doesn't fail within the timeout.
+func EventuallyFailing(t testing.TB, h gosundheit.Health, name string, timeout time.Duration) {
+	t.Helper()
+
+	if !eventually(timeout, func() bool { return checkHealthy(h, name, false) }) {
+		t.Fatalf("check %s did not fail within %s", name, timeout)
+	}
+}
+
+func checkHealthy(h gosundheit.Health, name string, healthy bool) bool {
+	results, _ := h.Results()
+	result, ok := results[name]
+	return ok && result.IsHealthy() == healthy
+}
+
+func eventually(timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if condition() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,3 @@
+// Package gosundheittest provides utilities for testing code that uses go-sundheit:
+// a fake Health, a controllable ManualCheck, listener recorders, and assertion helpers.
+package gosundheittest
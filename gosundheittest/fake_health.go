@@ -0,0 +1,393 @@
+package gosundheittest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// FakeHealth is a gosundheit.Health that never executes checks.
+// Instead, tests control the results using SetResult, which makes the health of the code under test deterministic.
+// The zero value is ready to use.
+type FakeHealth struct {
+	lock       sync.Mutex
+	checks     map[string]gosundheit.Config
+	results    map[string]gosundheit.Result
+	generation uint64
+	changed    chan struct{}
+	events     chan gosundheit.Event
+}
+
+const eventsBufferSize = 256
+
+// NewFakeHealth returns a new FakeHealth
+func NewFakeHealth() *FakeHealth {
+	return &FakeHealth{}
+}
+
+// SetResult sets the result of the check with the given name, whether it is registered or not.
+func (f *FakeHealth) SetResult(name string, result gosundheit.Result) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.setResult(name, result)
+	if result.IsHealthy() {
+		f.emit(gosundheit.EventCompleted, name, f.results[name])
+	} else {
+		f.emit(gosundheit.EventFailed, name, f.results[name])
+	}
+}
+
+// SetPassing sets a passing result for the check with the given name.
+func (f *FakeHealth) SetPassing(name string) {
+	f.SetResult(name, gosundheit.Result{})
+}
+
+// SetFailing sets a failing result, with the given error, for the check with the given name;
+// a default error is used when err is nil.
+func (f *FakeHealth) SetFailing(name string, err error) {
+	if err == nil {
+		err = errors.New("fake check failure")
+	}
+	f.SetResult(name, gosundheit.Result{Error: err, ContiguousFailures: 1})
+}
+
+// setResult updates the result, and notifies the waiters. Callers must hold the lock.
+func (f *FakeHealth) setResult(name string, result gosundheit.Result) {
+	if f.results == nil {
+		f.results = make(map[string]gosundheit.Result)
+	}
+	f.generation++
+	result.Generation = f.generation
+	f.results[name] = result
+	f.notify()
+}
+
+func (f *FakeHealth) notify() {
+	if f.changed != nil {
+		close(f.changed)
+		f.changed = nil
+	}
+}
+
+// RegisterCheck records the check configuration, and sets its initial result. The check is never executed.
+func (f *FakeHealth) RegisterCheck(cfg *gosundheit.Config) error {
+	if cfg.Check == nil || cfg.Check.Name() == "" {
+		return errors.Errorf("misconfigured check %v", cfg.Check)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.checks == nil {
+		f.checks = make(map[string]gosundheit.Config)
+	}
+	f.checks[cfg.Check.Name()] = *cfg
+
+	result := gosundheit.Result{
+		Details:        "didn't run yet",
+		Classification: cfg.Classification,
+		Description:    cfg.Description,
+		RunbookURL:     cfg.RunbookURL,
+		Owner:          cfg.Owner,
+		Annotations:    cfg.Annotations,
+		Weight:         cfg.Weight,
+		Capabilities:   cfg.Capabilities,
+	}
+	if !cfg.InitiallyPassing {
+		result.Error = errors.New("didn't run yet")
+		result.ContiguousFailures = 1
+	}
+	f.setResult(cfg.Check.Name(), result)
+	f.emit(gosundheit.EventRegistered, cfg.Check.Name(), f.results[cfg.Check.Name()])
+	return nil
+}
+
+// RegisterChecks validates all the check configurations, and registers them when they are all valid, as RegisterCheck does.
+func (f *FakeHealth) RegisterChecks(cfgs ...*gosundheit.Config) error {
+	var errs gosundheit.MultiError
+	names := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		switch {
+		case cfg == nil || cfg.Check == nil || cfg.Check.Name() == "":
+			errs = append(errs, errors.Errorf("misconfigured check %v", cfg))
+		case names[cfg.Check.Name()]:
+			errs = append(errs, errors.Errorf("duplicate check %s", cfg.Check.Name()))
+		default:
+			names[cfg.Check.Name()] = true
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, cfg := range cfgs {
+		_ = f.RegisterCheck(cfg)
+	}
+	return nil
+}
+
+// Deregister removes the check configuration and result.
+func (f *FakeHealth) Deregister(name string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.checks[name]; ok {
+		delete(f.checks, name)
+		f.emit(gosundheit.EventDeregistered, name, gosundheit.Result{})
+	}
+	if _, ok := f.results[name]; ok {
+		delete(f.results, name)
+		f.generation++
+		f.notify()
+	}
+}
+
+// DeregisterAll removes all the checks configurations and results.
+func (f *FakeHealth) DeregisterAll() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for name := range f.checks {
+		f.emit(gosundheit.EventDeregistered, name, gosundheit.Result{})
+	}
+	f.checks = nil
+	if len(f.results) > 0 {
+		f.results = nil
+		f.generation++
+		f.notify()
+	}
+}
+
+// Results returns a copy of the current results, and the current health.
+func (f *FakeHealth) Results() (results map[string]gosundheit.Result, healthy bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	results = make(map[string]gosundheit.Result, len(f.results))
+	for name, result := range f.results {
+		results[name] = result
+	}
+	return results, f.healthy()
+}
+
+// ResultsJSON returns the indented JSON encoding of the current results, as served by the HTTP handler.
+func (f *FakeHealth) ResultsJSON() []byte {
+	results, _ := f.Results()
+
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(results); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// IsHealthy returns true iff all the results are passing.
+func (f *FakeHealth) IsHealthy() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.healthy()
+}
+
+// healthy returns true iff no critical result is failing, unless silenced or within its grace period
+func (f *FakeHealth) healthy() bool {
+	for name, result := range f.results {
+		if !result.IsHealthy() && !result.Silenced && !result.InGracePeriod && f.checks[name].Severity != gosundheit.Warning {
+			return false
+		}
+	}
+	return true
+}
+
+// Score returns the weighted health score of the current results.
+func (f *FakeHealth) Score() float64 {
+	results, _ := f.Results()
+	return gosundheit.ScoreOf(results)
+}
+
+// ImpactedCapabilities returns the sorted capabilities of the failing results.
+func (f *FakeHealth) ImpactedCapabilities() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	impacted := make(map[string]bool)
+	for _, result := range f.results {
+		if result.IsHealthy() {
+			continue
+		}
+		for _, capability := range result.Capabilities {
+			impacted[capability] = true
+		}
+	}
+	capabilities := make([]string, 0, len(impacted))
+	for capability := range impacted {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// WaitForHealthy blocks until all the results are passing, or until the context is done.
+func (f *FakeHealth) WaitForHealthy(ctx context.Context) error {
+	for {
+		f.lock.Lock()
+		healthy := f.healthy()
+		if f.changed == nil {
+			f.changed = make(chan struct{})
+		}
+		changed := f.changed
+		f.lock.Unlock()
+
+		if healthy {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Trigger returns the current result of the check with the given name, as the checks are never executed.
+func (f *FakeHealth) Trigger(name string) (gosundheit.Result, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	result, ok := f.results[name]
+	if !ok {
+		return gosundheit.Result{}, errors.Errorf("check %s is not registered", name)
+	}
+	return result, nil
+}
+
+// TriggerContext returns the current result of the check with the given name, as the checks are never executed,
+// or the context error once the context is done.
+func (f *FakeHealth) TriggerContext(ctx context.Context, name string) (gosundheit.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return gosundheit.Result{}, err
+	}
+	return f.Trigger(name)
+}
+
+// Costs returns a zero cost for each registered check, as the checks are never executed.
+func (f *FakeHealth) Costs() map[string]gosundheit.Cost {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	costs := make(map[string]gosundheit.Cost, len(f.checks))
+	for name := range f.checks {
+		costs[name] = gosundheit.Cost{}
+	}
+	return costs
+}
+
+// Checks returns the configurations of the registered checks.
+func (f *FakeHealth) Checks() map[string]gosundheit.Config {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	configs := make(map[string]gosundheit.Config, len(f.checks))
+	for name, cfg := range f.checks {
+		configs[name] = cfg
+	}
+	return configs
+}
+
+// Generation returns the number of results changes so far.
+func (f *FakeHealth) Generation() uint64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.generation
+}
+
+// Events returns the channel of the fake events: registered and deregistered events are emitted by RegisterCheck
+// and Deregister, and completed or failed events are emitted whenever a result is set.
+// Events are emitted only once Events() was first called, and the oldest events are dropped when the channel is full.
+func (f *FakeHealth) Events() <-chan gosundheit.Event {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.events == nil {
+		f.events = make(chan gosundheit.Event, eventsBufferSize)
+	}
+	return f.events
+}
+
+// emit sends the event, dropping the oldest event when the channel is full. Callers must hold the lock.
+func (f *FakeHealth) emit(eventType gosundheit.EventType, name string, result gosundheit.Result) {
+	if f.events == nil {
+		return
+	}
+
+	event := gosundheit.Event{Type: eventType, Check: name, Result: result, Time: time.Now()}
+	for {
+		select {
+		case f.events <- event:
+			return
+		default:
+			select {
+			case <-f.events:
+			default:
+			}
+		}
+	}
+}
+
+// Export returns the states of the results that were set, excluding the initial results of registered checks.
+func (f *FakeHealth) Export() gosundheit.Snapshot {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	snapshot := gosundheit.Snapshot{
+		Version: gosundheit.SnapshotVersion,
+		Time:    time.Now(),
+		Checks:  make(map[string]gosundheit.CheckState, len(f.results)),
+	}
+	for name, result := range f.results {
+		if result.Executed() {
+			snapshot.Checks[name] = gosundheit.CheckStateOf(result)
+		}
+	}
+	return snapshot
+}
+
+// Import sets the results of the registered checks that still have their initial results, from the states of the given Snapshot.
+func (f *FakeHealth) Import(snapshot gosundheit.Snapshot) error {
+	if snapshot.Version != gosundheit.SnapshotVersion {
+		return errors.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, gosundheit.SnapshotVersion)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for name, state := range snapshot.Checks {
+		cfg, ok := f.checks[name]
+		if !ok || f.results[name].Executed() {
+			continue
+		}
+		result := state.Result()
+		result.Classification = cfg.Classification
+		result.Description = cfg.Description
+		result.RunbookURL = cfg.RunbookURL
+		result.Owner = cfg.Owner
+		result.Annotations = cfg.Annotations
+		result.Weight = cfg.Weight
+		result.Capabilities = cfg.Capabilities
+		f.setResult(name, result)
+	}
+	return nil
+}
+
+var _ gosundheit.Health = (*FakeHealth)(nil)
@@ -0,0 +1,142 @@
+package gosundheittest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestFakeHealth(t *testing.T) {
+	h := NewFakeHealth()
+	assert.True(t, h.IsHealthy(), "health with no results")
+	assert.Equal(t, "{}\n", string(h.ResultsJSON()), "JSON of empty results")
+
+	err := h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("db"), Owner: "storage-team"})
+	assert.NoError(t, err)
+	assert.Equal(t, "storage-team", h.Checks()["db"].Owner, "registered check config")
+	assert.False(t, h.IsHealthy(), "health after registration")
+	assert.Equal(t, uint64(1), h.Generation(), "generation after registration")
+
+	h.SetPassing("db")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "health after setting a passing result")
+	assert.True(t, results["db"].IsHealthy(), "passing result")
+	assert.Equal(t, uint64(2), results["db"].Generation, "result generation")
+
+	h.SetFailing("db", errors.New("connection refused"))
+	assert.False(t, h.IsHealthy(), "health after setting a failing result")
+
+	h.Deregister("db")
+	assert.True(t, h.IsHealthy(), "health after deregistration")
+	assert.Empty(t, h.Checks(), "checks after deregistration")
+	assert.Equal(t, uint64(4), h.Generation(), "generation after deregistration")
+
+	err = h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("")})
+	assert.Error(t, err, "bogus check should fail the registration")
+}
+
+func TestFakeHealth_Severity(t *testing.T) {
+	h := NewFakeHealth()
+	_ = h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("search"), Severity: gosundheit.Warning})
+
+	h.SetFailing("search", errors.New("degraded"))
+	assert.True(t, h.IsHealthy(), "failing warning checks should not fail the health")
+}
+
+func TestFakeHealth_RegisterChecks(t *testing.T) {
+	h := NewFakeHealth()
+
+	err := h.RegisterChecks(&gosundheit.Config{Check: NewManualCheck("db")}, &gosundheit.Config{Check: NewManualCheck("db")})
+	assert.Error(t, err, "duplicate checks should fail the registration")
+	assert.Empty(t, h.Checks(), "no check should be registered")
+
+	err = h.RegisterChecks(&gosundheit.Config{Check: NewManualCheck("db")}, &gosundheit.Config{Check: NewManualCheck("cache")})
+	assert.NoError(t, err)
+	assert.Len(t, h.Checks(), 2, "all the checks should be registered")
+}
+
+func TestFakeHealth_WaitForHealthy(t *testing.T) {
+	h := NewFakeHealth()
+	h.SetFailing("db", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, h.WaitForHealthy(ctx), "wait for a failing result")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		h.SetPassing("db")
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.WaitForHealthy(ctx), "wait for a passing result")
+}
+
+func TestFakeHealth_Events(t *testing.T) {
+	h := NewFakeHealth()
+	events := h.Events()
+
+	_ = h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("db")})
+	h.SetPassing("db")
+	h.SetFailing("db", nil)
+	h.Deregister("db")
+
+	var types []gosundheit.EventType
+	for len(events) > 0 {
+		types = append(types, (<-events).Type)
+	}
+	assert.Equal(t, []gosundheit.EventType{
+		gosundheit.EventRegistered, gosundheit.EventCompleted, gosundheit.EventFailed, gosundheit.EventDeregistered,
+	}, types)
+}
+
+func TestFakeHealth_ImpactedCapabilities(t *testing.T) {
+	h := NewFakeHealth()
+	h.SetResult("db", gosundheit.Result{Error: errors.New("down"), Capabilities: []string{"writes", "checkout"}})
+	h.SetResult("search", gosundheit.Result{Capabilities: []string{"search"}})
+	assert.Equal(t, []string{"checkout", "writes"}, h.ImpactedCapabilities())
+}
+
+func TestFakeHealth_Trigger(t *testing.T) {
+	h := NewFakeHealth()
+	_, err := h.Trigger("db")
+	assert.Error(t, err, "unregistered check")
+
+	h.SetFailing("db", errors.New("connection refused"))
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.EqualError(t, result.Error, "connection refused")
+}
+
+func TestFakeHealth_Costs(t *testing.T) {
+	h := NewFakeHealth()
+	assert.Empty(t, h.Costs())
+
+	assert.NoError(t, h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("db")}))
+	assert.Equal(t, map[string]gosundheit.Cost{"db": {}}, h.Costs())
+}
+
+func TestFakeHealth_ExportImport(t *testing.T) {
+	old := NewFakeHealth()
+	assert.NoError(t, old.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("db")}))
+	assert.NoError(t, old.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("cache")}))
+	old.SetFailing("db", errors.New("connection refused"))
+
+	snapshot := old.Export()
+	assert.Len(t, snapshot.Checks, 1, "initial results are not exported")
+
+	h := NewFakeHealth()
+	assert.NoError(t, h.RegisterCheck(&gosundheit.Config{Check: NewManualCheck("db"), Owner: "storage-team"}))
+	assert.NoError(t, h.Import(snapshot))
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.EqualError(t, result.Error, "connection refused")
+	assert.Equal(t, "storage-team", result.Owner)
+
+	assert.Error(t, h.Import(gosundheit.Snapshot{}), "unsupported snapshot version")
+}
@@ -0,0 +1,91 @@
+package gosundheittest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestManualCheck(t *testing.T) {
+	check := NewManualCheck("manual")
+	assert.Equal(t, "manual", check.Name())
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "new manual check should pass")
+	assert.Nil(t, details)
+
+	check.Fail(nil)
+	_, err = check.Execute()
+	assert.EqualError(t, err, "manual check failure")
+
+	check.Pass("ok")
+	details, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", details)
+	assert.Equal(t, 3, check.Executions())
+}
+
+func TestWithHealth(t *testing.T) {
+	checkRecorder := &CheckListenerRecorder{}
+	healthRecorder := &HealthListenerRecorder{}
+	h := gosundheit.New(gosundheit.WithCheckListeners(checkRecorder), gosundheit.WithHealthListeners(healthRecorder))
+	defer h.DeregisterAll()
+
+	check := NewManualCheck("manual")
+	err := h.RegisterCheck(&gosundheit.Config{Check: check, ExecutionPeriod: 5 * time.Millisecond})
+	assert.NoError(t, err)
+
+	EventuallyHealthy(t, h, time.Second)
+	EventuallyPassing(t, h, "manual", time.Second)
+
+	check.Fail(errors.New("boom"))
+	EventuallyUnhealthy(t, h, time.Second)
+	EventuallyFailing(t, h, "manual", time.Second)
+
+	h.Deregister("manual")
+	assert.True(t, eventually(time.Second, func() bool { return len(checkRecorder.Events(EventDeregistered)) == 1 }),
+		"deregistration should be recorded")
+
+	events := checkRecorder.Events()
+	assert.Equal(t, EventRegistered, events[0].Type, "first event")
+	assert.Equal(t, EventDeregistered, events[len(events)-1].Type, "last event")
+	assert.NotEmpty(t, checkRecorder.Events(EventStarted, EventCompleted), "execution events")
+	assert.NotEmpty(t, healthRecorder.Updates(), "results updates")
+	assert.NotNil(t, healthRecorder.Last())
+
+	checkRecorder.Reset()
+	assert.Empty(t, checkRecorder.Events(), "events after reset")
+}
+
+func TestAssertionFailures(t *testing.T) {
+	h := NewFakeHealth()
+	h.SetFailing("db", nil)
+
+	tb := &fatalRecorder{}
+	EventuallyHealthy(tb, h, time.Millisecond)
+	EventuallyPassing(tb, h, "db", time.Millisecond)
+	EventuallyPassing(tb, h, "missing", time.Millisecond)
+	h.SetPassing("db")
+	EventuallyUnhealthy(tb, h, time.Millisecond)
+	EventuallyFailing(tb, h, "db", time.Millisecond)
+
+	assert.Len(t, tb.failures, 5, "all assertions should fail")
+	assert.Contains(t, tb.failures[0], "health did not become healthy within 1ms")
+}
+
+// fatalRecorder records failures without stopping the test
+type fatalRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,71 @@
+package gosundheittest
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// ManualCheck is a check whose outcome is controlled by the test, using Pass and Fail.
+// A new ManualCheck is passing.
+type ManualCheck struct {
+	name string
+
+	lock       sync.Mutex
+	details    interface{}
+	err        error
+	executions int
+}
+
+// NewManualCheck returns a new passing ManualCheck with the given name
+func NewManualCheck(name string) *ManualCheck {
+	return &ManualCheck{name: name}
+}
+
+// Pass makes the next executions pass with the given details
+func (c *ManualCheck) Pass(details interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.details = details
+	c.err = nil
+}
+
+// Fail makes the next executions fail with the given error; a default error is used when err is nil
+func (c *ManualCheck) Fail(err error) {
+	if err == nil {
+		err = errors.New("manual check failure")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.details = nil
+	c.err = err
+}
+
+// Executions returns the number of times the check has executed
+func (c *ManualCheck) Executions() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.executions
+}
+
+// Name returns the check name
+func (c *ManualCheck) Name() string {
+	return c.name
+}
+
+// Execute returns the outcome set by the last call to Pass or Fail
+func (c *ManualCheck) Execute() (details interface{}, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.executions++
+	return c.details, c.err
+}
+
+var _ checks.Check = (*ManualCheck)(nil)
@@ -0,0 +1,132 @@
+package gosundheittest
+
+import (
+	"sync"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// Check event types recorded by CheckListenerRecorder
+const (
+	EventRegistered   = "registered"
+	EventStarted      = "started"
+	EventCompleted    = "completed"
+	EventDeregistered = "deregistered"
+)
+
+// CheckEvent is a check listener event, as recorded by CheckListenerRecorder
+type CheckEvent struct {
+	// Type is one of EventRegistered, EventStarted, EventCompleted or EventDeregistered
+	Type string
+	// Check is the check name
+	Check string
+	// Result is the reported result; it is set for EventRegistered and EventCompleted events only
+	Result gosundheit.Result
+}
+
+// CheckListenerRecorder is a gosundheit.CheckListener that records all the events it is notified of.
+// The zero value is ready to use.
+type CheckListenerRecorder struct {
+	lock   sync.Mutex
+	events []CheckEvent
+}
+
+// OnCheckRegistered records an EventRegistered event
+func (r *CheckListenerRecorder) OnCheckRegistered(name string, result gosundheit.Result) {
+	r.record(CheckEvent{Type: EventRegistered, Check: name, Result: result})
+}
+
+// OnCheckStarted records an EventStarted event
+func (r *CheckListenerRecorder) OnCheckStarted(name string) {
+	r.record(CheckEvent{Type: EventStarted, Check: name})
+}
+
+// OnCheckCompleted records an EventCompleted event
+func (r *CheckListenerRecorder) OnCheckCompleted(name string, result gosundheit.Result) {
+	r.record(CheckEvent{Type: EventCompleted, Check: name, Result: result})
+}
+
+// OnCheckDeregistered records an EventDeregistered event
+func (r *CheckListenerRecorder) OnCheckDeregistered(name string) {
+	r.record(CheckEvent{Type: EventDeregistered, Check: name})
+}
+
+func (r *CheckListenerRecorder) record(event CheckEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+// Events returns a copy of the recorded events, in the order they were recorded.
+// When types are given, only events of these types are returned.
+func (r *CheckListenerRecorder) Events(types ...string) []CheckEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	events := make([]CheckEvent, 0, len(r.events))
+	for _, event := range r.events {
+		if len(types) == 0 || contains(types, event.Type) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Reset clears the recorded events
+func (r *CheckListenerRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = nil
+}
+
+// HealthListenerRecorder is a gosundheit.HealthListener that records all the results updates it is notified of.
+// The zero value is ready to use.
+type HealthListenerRecorder struct {
+	lock    sync.Mutex
+	updates []map[string]gosundheit.Result
+}
+
+// OnResultsUpdated records the updated results
+func (r *HealthListenerRecorder) OnResultsUpdated(results map[string]gosundheit.Result) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.updates = append(r.updates, results)
+}
+
+// Updates returns a copy of the recorded results updates, in the order they were recorded.
+// The results maps are shared, and must not be modified.
+func (r *HealthListenerRecorder) Updates() []map[string]gosundheit.Result {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]map[string]gosundheit.Result(nil), r.updates...)
+}
+
+// Last returns the last recorded results update, or nil when no update was recorded.
+func (r *HealthListenerRecorder) Last() map[string]gosundheit.Result {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.updates) == 0 {
+		return nil
+	}
+	return r.updates[len(r.updates)-1]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ gosundheit.CheckListener             = (*CheckListenerRecorder)(nil)
+	_ gosundheit.CheckDeregisteredListener = (*CheckListenerRecorder)(nil)
+	_ gosundheit.HealthListener            = (*HealthListenerRecorder)(nil)
+)
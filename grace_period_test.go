@@ -0,0 +1,59 @@
+package gosundheit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestGracePeriod(t *testing.T) {
+	now := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	recorder := &transitionsRecorder{}
+	h := New(
+		WithTimestampSource(func() time.Time { return now }),
+		WithHealthListeners(NewTransitionHealthListener(recorder)),
+	).(*health)
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "cache.warmup"},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		GracePeriod:     time.Minute,
+	}))
+	results, healthy := h.Results()
+	assert.True(t, results["cache.warmup"].InGracePeriod, "the initial failure is within the grace period")
+	assert.True(t, healthy, "failures within the grace period don't affect the health")
+
+	task := h.checkTasks["cache.warmup"]
+	fail := func() Result {
+		result, ok := h.updateResult(task, checks.ExecutionResult{Err: errors.New("cold cache")}, 0, now)
+		assert.True(t, ok)
+		h.reportResults()
+		return result
+	}
+
+	now = now.Add(30 * time.Second)
+	result := fail()
+	assert.False(t, result.IsHealthy(), "the failure is recorded within the grace period")
+	assert.True(t, result.InGracePeriod)
+	assert.True(t, h.IsHealthy(), "failures within the grace period don't affect the health")
+	assert.Equal(t, []string{"cache.warmup Added"}, recorder.recorded(), "failures within the grace period don't trigger transitions")
+
+	now = now.Add(time.Minute)
+	result = fail()
+	assert.False(t, result.InGracePeriod)
+	assert.False(t, h.IsHealthy(), "failures affect the health after the grace period")
+	assert.Equal(t, []string{"cache.warmup Added", "cache.warmup Failed"}, recorder.recorded())
+
+	err := h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "invalid.grace"},
+		ExecutionPeriod: time.Second,
+		GracePeriod:     -time.Second,
+	})
+	assert.EqualError(t, err, "misconfigured check invalid.grace grace period -1s, must not be negative")
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// ConnStateSource reports the connectivity state of a client connection.
+// It is implemented by `*grpc.ClientConn`.
+type ConnStateSource interface {
+	// GetState returns the current connectivity state.
+	GetState() connectivity.State
+	// WaitForStateChange waits until the state changes from sourceState or ctx expires.
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+	// Connect causes an idle connection to begin connecting.
+	Connect()
+}
+
+// ConnStateCheckConfig configures a check for the connectivity state of a long-lived gRPC client connection.
+type ConnStateCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Conn is required, and is usually a `*grpc.ClientConn`.
+	Conn ConnStateSource
+	// WaitForReady is optional; when positive, a connection that isn't READY triggers a connection attempt,
+	// and the check waits up to WaitForReady for it to become READY before failing.
+	// When undefined, only the TRANSIENT_FAILURE and SHUTDOWN states fail the check.
+	WaitForReady time.Duration
+}
+
+type connStateCheck struct {
+	config *ConnStateCheckConfig
+}
+
+// NewConnStateCheck creates a new check that reports the connectivity state of the given client connection.
+// The check details are the connectivity state at the end of the execution.
+func NewConnStateCheck(config ConnStateCheckConfig) (checks.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Conn == nil {
+		return nil, errors.Errorf("Conn must not be nil")
+	}
+
+	return &connStateCheck{config: &config}, nil
+}
+
+func (check *connStateCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *connStateCheck) Execute() (details interface{}, err error) {
+	conn := check.config.Conn
+	state := conn.GetState()
+
+	if check.config.WaitForReady > 0 && state != connectivity.Ready && state != connectivity.Shutdown {
+		ctx, cancel := context.WithTimeout(context.Background(), check.config.WaitForReady)
+		defer cancel()
+
+		conn.Connect()
+		for state = conn.GetState(); state != connectivity.Ready && state != connectivity.Shutdown; state = conn.GetState() {
+			if !conn.WaitForStateChange(ctx, state) {
+				return state.String(), errors.Errorf("connection is not ready after %v, state: %s", check.config.WaitForReady, state)
+			}
+		}
+	}
+
+	switch state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return state.String(), errors.Errorf("unhealthy connection state: %s", state)
+	}
+
+	return state.String(), nil
+}
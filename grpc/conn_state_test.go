@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestNewConnStateCheckRequiredFields(t *testing.T) {
+	check, err := NewConnStateCheck(ConnStateCheckConfig{Conn: &fakeConn{}})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewConnStateCheck(ConnStateCheckConfig{CheckName: "meh"})
+	assert.Nil(t, check, "nil Conn should yield nil check")
+	assert.Error(t, err, "nil Conn should yield error")
+}
+
+func TestConnStateCheck_states(t *testing.T) {
+	for state, healthy := range map[connectivity.State]bool{
+		connectivity.Idle:             true,
+		connectivity.Connecting:       true,
+		connectivity.Ready:            true,
+		connectivity.TransientFailure: false,
+		connectivity.Shutdown:         false,
+	} {
+		check, err := NewConnStateCheck(ConnStateCheckConfig{CheckName: "grpc.conn", Conn: &fakeConn{state: state}})
+		assert.NoError(t, err)
+		assert.Equal(t, "grpc.conn", check.Name(), "check name")
+
+		details, err := check.Execute()
+		assert.Equal(t, state.String(), details, "check details are the connection state")
+		assert.Equal(t, healthy, err == nil, "health of state %s", state)
+	}
+}
+
+func TestConnStateCheck_waitForReady(t *testing.T) {
+	check, err := NewConnStateCheck(ConnStateCheckConfig{
+		CheckName:    "grpc.conn",
+		Conn:         &fakeConn{state: connectivity.Connecting},
+		WaitForReady: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	details, err := check.Execute()
+	assert.Error(t, err, "connection that does not become ready should fail")
+	assert.Equal(t, "connection is not ready after 10ms, state: CONNECTING", err.Error())
+	assert.Equal(t, "CONNECTING", details)
+}
+
+func TestConnStateCheck_clientConn(t *testing.T) {
+	listener := bufconn.Listen(1024)
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	check, err := NewConnStateCheck(ConnStateCheckConfig{
+		CheckName:    "grpc.conn",
+		Conn:         conn,
+		WaitForReady: time.Second,
+	})
+	assert.NoError(t, err)
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass once the connection is ready")
+	assert.Equal(t, "READY", details)
+
+	_ = conn.Close()
+	details, err = check.Execute()
+	assert.Error(t, err, "check should fail once the connection is closed")
+	assert.Equal(t, "SHUTDOWN", details)
+}
+
+type fakeConn struct {
+	state connectivity.State
+}
+
+func (c *fakeConn) GetState() connectivity.State {
+	return c.state
+}
+
+func (c *fakeConn) WaitForStateChange(ctx context.Context, _ connectivity.State) bool {
+	<-ctx.Done()
+	return false
+}
+
+func (c *fakeConn) Connect() {}
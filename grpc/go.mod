@@ -0,0 +1,13 @@
+module github.com/AppsFlyer/go-sundheit/grpc
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/pkg/errors v0.8.1
+	github.com/stretchr/testify v1.7.0
+	google.golang.org/grpc v1.45.0
+	google.golang.org/protobuf v1.27.1
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
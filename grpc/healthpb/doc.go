@@ -0,0 +1,4 @@
+// Package healthpb holds the protobuf definitions of the health results service, and its generated code.
+package healthpb
+
+//go:generate sh -c "cd .. && buf generate --template buf.gen.yaml --path healthpb"
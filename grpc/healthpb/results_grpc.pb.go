@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: healthpb/results.proto
+
+package healthpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// HealthResultsClient is the client API for HealthResults service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HealthResultsClient interface {
+	// GetResults returns the current results snapshot.
+	GetResults(ctx context.Context, in *GetResultsRequest, opts ...grpc.CallOption) (*ResultsSnapshot, error)
+	// WatchResults streams the current results snapshot, followed by a snapshot whenever the results are updated.
+	// Slow consumers only receive the latest snapshot.
+	WatchResults(ctx context.Context, in *WatchResultsRequest, opts ...grpc.CallOption) (HealthResults_WatchResultsClient, error)
+	// TriggerCheck executes a registered check right away, out of its schedule, and returns its updated result.
+	TriggerCheck(ctx context.Context, in *TriggerCheckRequest, opts ...grpc.CallOption) (*CheckResult, error)
+}
+
+type healthResultsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHealthResultsClient(cc grpc.ClientConnInterface) HealthResultsClient {
+	return &healthResultsClient{cc}
+}
+
+func (c *healthResultsClient) GetResults(ctx context.Context, in *GetResultsRequest, opts ...grpc.CallOption) (*ResultsSnapshot, error) {
+	out := new(ResultsSnapshot)
+	err := c.cc.Invoke(ctx, "/sundheit.v1.HealthResults/GetResults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthResultsClient) WatchResults(ctx context.Context, in *WatchResultsRequest, opts ...grpc.CallOption) (HealthResults_WatchResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HealthResults_ServiceDesc.Streams[0], "/sundheit.v1.HealthResults/WatchResults", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &healthResultsWatchResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HealthResults_WatchResultsClient interface {
+	Recv() (*ResultsSnapshot, error)
+	grpc.ClientStream
+}
+
+type healthResultsWatchResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *healthResultsWatchResultsClient) Recv() (*ResultsSnapshot, error) {
+	m := new(ResultsSnapshot)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *healthResultsClient) TriggerCheck(ctx context.Context, in *TriggerCheckRequest, opts ...grpc.CallOption) (*CheckResult, error) {
+	out := new(CheckResult)
+	err := c.cc.Invoke(ctx, "/sundheit.v1.HealthResults/TriggerCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HealthResultsServer is the server API for HealthResults service.
+// All implementations must embed UnimplementedHealthResultsServer
+// for forward compatibility
+type HealthResultsServer interface {
+	// GetResults returns the current results snapshot.
+	GetResults(context.Context, *GetResultsRequest) (*ResultsSnapshot, error)
+	// WatchResults streams the current results snapshot, followed by a snapshot whenever the results are updated.
+	// Slow consumers only receive the latest snapshot.
+	WatchResults(*WatchResultsRequest, HealthResults_WatchResultsServer) error
+	// TriggerCheck executes a registered check right away, out of its schedule, and returns its updated result.
+	TriggerCheck(context.Context, *TriggerCheckRequest) (*CheckResult, error)
+	mustEmbedUnimplementedHealthResultsServer()
+}
+
+// UnimplementedHealthResultsServer must be embedded to have forward compatible implementations.
+type UnimplementedHealthResultsServer struct {
+}
+
+func (UnimplementedHealthResultsServer) GetResults(context.Context, *GetResultsRequest) (*ResultsSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResults not implemented")
+}
+func (UnimplementedHealthResultsServer) WatchResults(*WatchResultsRequest, HealthResults_WatchResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchResults not implemented")
+}
+func (UnimplementedHealthResultsServer) TriggerCheck(context.Context, *TriggerCheckRequest) (*CheckResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerCheck not implemented")
+}
+func (UnimplementedHealthResultsServer) mustEmbedUnimplementedHealthResultsServer() {}
+
+// UnsafeHealthResultsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HealthResultsServer will
+// result in compilation errors.
+type UnsafeHealthResultsServer interface {
+	mustEmbedUnimplementedHealthResultsServer()
+}
+
+func RegisterHealthResultsServer(s grpc.ServiceRegistrar, srv HealthResultsServer) {
+	s.RegisterService(&HealthResults_ServiceDesc, srv)
+}
+
+func _HealthResults_GetResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthResultsServer).GetResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sundheit.v1.HealthResults/GetResults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthResultsServer).GetResults(ctx, req.(*GetResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthResults_WatchResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HealthResultsServer).WatchResults(m, &healthResultsWatchResultsServer{stream})
+}
+
+type HealthResults_WatchResultsServer interface {
+	Send(*ResultsSnapshot) error
+	grpc.ServerStream
+}
+
+type healthResultsWatchResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *healthResultsWatchResultsServer) Send(m *ResultsSnapshot) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _HealthResults_TriggerCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthResultsServer).TriggerCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sundheit.v1.HealthResults/TriggerCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthResultsServer).TriggerCheck(ctx, req.(*TriggerCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HealthResults_ServiceDesc is the grpc.ServiceDesc for HealthResults service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HealthResults_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sundheit.v1.HealthResults",
+	HandlerType: (*HealthResultsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetResults",
+			Handler:    _HealthResults_GetResults_Handler,
+		},
+		{
+			MethodName: "TriggerCheck",
+			Handler:    _HealthResults_TriggerCheck_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchResults",
+			Handler:       _HealthResults_WatchResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "healthpb/results.proto",
+}
@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/grpc/healthpb"
+)
+
+// ResultsService exposes the results of a Health instance as the healthpb.HealthResults gRPC service,
+// for fleets with gRPC-only internal APIs.
+// ResultsService is a gosundheit.HealthListener, and must be registered with the Health instance it exposes, e.g.:
+//
+//	service := healthgrpc.NewResultsService()
+//	h := gosundheit.New(gosundheit.WithHealthListeners(service))
+//	healthpb.RegisterHealthResultsServer(server, service.Server(h))
+type ResultsService struct {
+	lock     sync.RWMutex
+	watchers map[chan struct{}]struct{}
+}
+
+// NewResultsService returns a new ResultsService
+func NewResultsService() *ResultsService {
+	return &ResultsService{
+		watchers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// OnResultsUpdated notifies the watchers that the results were updated.
+func (s *ResultsService) OnResultsUpdated(map[string]gosundheit.Result) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for watcher := range s.watchers {
+		select {
+		case watcher <- struct{}{}:
+		default:
+			// the watcher will send the latest snapshot once it consumes the pending notification
+		}
+	}
+}
+
+// Server returns the HealthResults service implementation of the given Health instance.
+func (s *ResultsService) Server(h gosundheit.Health) healthpb.HealthResultsServer {
+	return &resultsServer{service: s, h: h}
+}
+
+func (s *ResultsService) subscribe(watcher chan struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.watchers[watcher] = struct{}{}
+}
+
+func (s *ResultsService) unsubscribe(watcher chan struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.watchers, watcher)
+}
+
+type resultsServer struct {
+	healthpb.UnimplementedHealthResultsServer
+	service *ResultsService
+	h       gosundheit.Health
+}
+
+func (s *resultsServer) GetResults(context.Context, *healthpb.GetResultsRequest) (*healthpb.ResultsSnapshot, error) {
+	return snapshotOf(s.h), nil
+}
+
+func (s *resultsServer) WatchResults(_ *healthpb.WatchResultsRequest, stream healthpb.HealthResults_WatchResultsServer) error {
+	watcher := make(chan struct{}, 1)
+	s.service.subscribe(watcher)
+	defer s.service.unsubscribe(watcher)
+
+	for {
+		if err := stream.Send(snapshotOf(s.h)); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-watcher:
+		}
+	}
+}
+
+func (s *resultsServer) TriggerCheck(_ context.Context, request *healthpb.TriggerCheckRequest) (*healthpb.CheckResult, error) {
+	if _, ok := s.h.Checks()[request.Name]; !ok {
+		return nil, status.Errorf(codes.NotFound, "check %s is not registered", request.Name)
+	}
+	result, err := s.h.Trigger(request.Name)
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	return checkResultOf(request.Name, result), nil
+}
+
+func snapshotOf(h gosundheit.Health) *healthpb.ResultsSnapshot {
+	// the generation is read first, so the results are at least as recent
+	generation := h.Generation()
+	results, healthy := h.Results()
+	snapshot := &healthpb.ResultsSnapshot{
+		Healthy:    healthy,
+		Score:      gosundheit.ScoreOf(results),
+		Generation: generation,
+		Results:    make([]*healthpb.CheckResult, 0, len(results)),
+	}
+	for name, result := range results {
+		snapshot.Results = append(snapshot.Results, checkResultOf(name, result))
+	}
+	sort.Slice(snapshot.Results, func(i, j int) bool {
+		return snapshot.Results[i].Name < snapshot.Results[j].Name
+	})
+	return snapshot
+}
+
+func checkResultOf(name string, result gosundheit.Result) *healthpb.CheckResult {
+	checkResult := &healthpb.CheckResult{
+		Name:               name,
+		Healthy:            result.IsHealthy(),
+		Timestamp:          timestamppb.New(result.Timestamp),
+		Duration:           durationpb.New(result.Duration),
+		Degraded:           result.Degraded,
+		Silenced:           result.Silenced,
+		InGracePeriod:      result.InGracePeriod,
+		ContiguousFailures: result.ContiguousFailures,
+		Classification:     result.Classification,
+		Generation:         result.Generation,
+	}
+	if result.Details != nil {
+		if details, err := json.Marshal(result.Details); err == nil {
+			checkResult.DetailsJson = string(details)
+		}
+	}
+	if result.Error != nil {
+		checkResult.Error = result.Error.Error()
+	}
+	if result.TimeOfFirstFailure != nil {
+		checkResult.TimeOfFirstFailure = timestamppb.New(*result.TimeOfFirstFailure)
+	}
+	return checkResult
+}
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+	"github.com/AppsFlyer/go-sundheit/grpc/healthpb"
+)
+
+func TestResultsService(t *testing.T) {
+	service := NewResultsService()
+	h := gosundheit.New(gosundheit.WithHealthListeners(service))
+	defer h.DeregisterAll()
+	err := h.RegisterCheck(&gosundheit.Config{
+		Check: checks.NewScriptedCheck("db",
+			checks.PassResult(map[string]int{"connections": 3}),
+			checks.FailResult(errors.New("connection refused")),
+		),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		Classification:  "readiness",
+	})
+	assert.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthResultsServer(server, service.Server(h))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	client := healthpb.NewHealthResultsClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	snapshot, err := client.GetResults(ctx, &healthpb.GetResultsRequest{})
+	assert.NoError(t, err)
+	assert.False(t, snapshot.Healthy, "the check didn't run yet")
+	assert.Equal(t, h.Generation(), snapshot.Generation)
+	if assert.Len(t, snapshot.Results, 1) {
+		assert.Equal(t, "db", snapshot.Results[0].Name)
+		assert.Equal(t, "readiness", snapshot.Results[0].Classification)
+		assert.Equal(t, "didn't run yet", snapshot.Results[0].Error)
+	}
+
+	watch, err := client.WatchResults(ctx, &healthpb.WatchResultsRequest{})
+	assert.NoError(t, err)
+	initial, err := watch.Recv()
+	assert.NoError(t, err)
+	assert.False(t, initial.Healthy, "the current snapshot is sent first")
+
+	result, err := client.TriggerCheck(ctx, &healthpb.TriggerCheckRequest{Name: "db"})
+	assert.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, `{"connections":3}`, result.DetailsJson)
+
+	updated, err := watch.Recv()
+	assert.NoError(t, err)
+	assert.True(t, updated.Healthy, "the updated snapshot is sent")
+	assert.Equal(t, float64(100), updated.Score)
+
+	result, err = client.TriggerCheck(ctx, &healthpb.TriggerCheckRequest{Name: "db"})
+	assert.NoError(t, err)
+	assert.False(t, result.Healthy)
+	assert.Equal(t, "connection refused", result.Error)
+	assert.Equal(t, int64(1), result.ContiguousFailures)
+	assert.NotNil(t, result.TimeOfFirstFailure)
+
+	_, err = client.TriggerCheck(ctx, &healthpb.TriggerCheckRequest{Name: "cache"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,194 @@
+package gosundheit
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the Snapshot format, which is bumped on incompatible changes.
+const SnapshotVersion = 1
+
+// Snapshot is the serializable health state of a Health instance, see Health.Export().
+// It is meant to be marshaled to JSON, and handed over to a replacement process (e.g. during a blue-green deployment),
+// which imports it so it starts with the health knowledge of the previous instance, rather than with failing initial results.
+type Snapshot struct {
+	// Version is the version of the snapshot format, i.e. SnapshotVersion
+	Version int `json:"version"`
+	// Time is the time the snapshot was exported at
+	Time time.Time `json:"time"`
+	// Checks are the states of the executed checks, keyed by check name
+	Checks map[string]CheckState `json:"checks"`
+}
+
+// CheckState is the serializable state of a check in a Snapshot.
+type CheckState struct {
+	// Details are the details of the last result. Once unmarshaled, structured details are generic JSON values (e.g. maps).
+	Details interface{} `json:"details,omitempty"`
+	// Error is the error message of the last result, when it failed
+	Error string `json:"error,omitempty"`
+	// Timestamp is the time of the last execution
+	Timestamp time.Time `json:"timestamp"`
+	// Duration is the execution duration of the last execution
+	Duration time.Duration `json:"duration,omitempty"`
+	// Timings is the breakdown of the last execution duration by phase
+	Timings map[string]time.Duration `json:"timings,omitempty"`
+	// Degraded is true when the last execution passed, but reported a degraded status
+	Degraded bool `json:"degraded,omitempty"`
+	// ContiguousFailures is the number of failures that occurred in a row
+	ContiguousFailures int64 `json:"contiguousFailures,omitempty"`
+	// TimeOfFirstFailure is the time of the initial transitional failure
+	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+	// History holds the outcomes of the recent executions, when the health is evaluated by the recent results
+	History *HistoryState `json:"history,omitempty"`
+	// AdaptivePeriod is the current period of an adaptively scheduled check (see Config.MaxExecutionPeriod)
+	AdaptivePeriod time.Duration `json:"adaptivePeriod,omitempty"`
+	// StablePasses is the number of passes in a row counted towards increasing the adaptive period
+	StablePasses int `json:"stablePasses,omitempty"`
+}
+
+// HistoryState is the serializable history of the recent executions of a check.
+type HistoryState struct {
+	// Passed holds a set bit for each passing execution, the last execution being the least significant bit
+	Passed uint64 `json:"passed"`
+	// Size is the number of executions tracked
+	Size uint `json:"size"`
+}
+
+// CheckStateOf returns the serializable state of the given result.
+func CheckStateOf(result Result) CheckState {
+	state := CheckState{
+		Details:            result.Details,
+		Timestamp:          result.Timestamp,
+		Duration:           result.Duration,
+		Timings:            result.Timings,
+		Degraded:           result.Degraded,
+		ContiguousFailures: result.ContiguousFailures,
+		TimeOfFirstFailure: result.TimeOfFirstFailure,
+	}
+	if result.Error != nil {
+		state.Error = result.Error.Error()
+	}
+	if result.history.size > 0 {
+		state.History = &HistoryState{Passed: result.history.passed, Size: result.history.size}
+	}
+	return state
+}
+
+// Result returns the result recorded by the state. The history of the state is restored by Health.Import() only.
+func (s CheckState) Result() Result {
+	result := Result{
+		Details:            s.Details,
+		Timestamp:          s.Timestamp,
+		Duration:           s.Duration,
+		Timings:            s.Timings,
+		Degraded:           s.Degraded,
+		ContiguousFailures: s.ContiguousFailures,
+		TimeOfFirstFailure: s.TimeOfFirstFailure,
+	}
+	if s.Error != "" {
+		result.Error = newMarshalableError(errors.New(s.Error))
+	}
+	return result
+}
+
+func (h *health) Export() Snapshot {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	snapshot := Snapshot{
+		Version: SnapshotVersion,
+		Time:    h.now(),
+		Checks:  make(map[string]CheckState, len(h.checkTasks)),
+	}
+	h.results.forEach(func(name string, result Result) bool {
+		task, ok := h.checkTasks[name]
+		if !ok || !result.Executed() {
+			return true
+		}
+		state := CheckStateOf(result)
+		if task.adaptiveScheduling() {
+			task.adaptive.lock.Lock()
+			state.AdaptivePeriod = task.adaptive.current
+			state.StablePasses = task.adaptive.passes
+			task.adaptive.lock.Unlock()
+		}
+		snapshot.Checks[name] = state
+		return true
+	})
+	return snapshot
+}
+
+func (h *health) Import(snapshot Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return errors.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, SnapshotVersion)
+	}
+
+	imported := make(map[string]Result, len(snapshot.Checks))
+	h.lock.RLock()
+	now := h.now()
+	for name, state := range snapshot.Checks {
+		task, ok := h.checkTasks[name]
+		if !ok {
+			continue
+		}
+		h.results.update(name, func(prevResult Result, ok bool) Result {
+			// results of checks that already executed in this instance are more recent
+			if ok && prevResult.Executed() {
+				return prevResult
+			}
+			result := h.importedResult(task, state, now)
+			h.aggregate.update(task.cfg, !ok || !prevResult.affectsHealth(), !result.affectsHealth())
+			task.importAdaptivePeriod(state)
+			imported[name] = h.reportedResult(task.cfg, result)
+			return result
+		})
+	}
+	h.changes.notify()
+	h.lock.RUnlock()
+
+	// the listeners are notified without holding the lock, as they may query the health
+	for name, result := range imported {
+		h.checksListener.OnCheckCompleted(name, result)
+	}
+	h.reportResults()
+	return nil
+}
+
+// importedResult returns the result restored from the given state, with the check metadata of the registered task.
+func (h *health) importedResult(task *checkTask, state CheckState, now time.Time) Result {
+	cfg := task.cfg
+	result := state.Result()
+	result.Generation = h.invalidateSnapshot()
+	result.Details = limitDetails(result.Details, h.maxDetailsSize)
+	result.Silenced = result.Error != nil && silenced(cfg.Silences, now)
+	result.InGracePeriod = result.Error != nil && now.Sub(task.registered) < cfg.GracePeriod
+	result.Classification = cfg.Classification
+	result.Description = cfg.Description
+	result.RunbookURL = cfg.RunbookURL
+	result.Owner = cfg.Owner
+	result.Annotations = cfg.Annotations
+	result.Weight = cfg.Weight
+	result.Capabilities = cfg.Capabilities
+	result.severity = cfg.Severity
+	h.resultEnrichers.enrich(cfg.Check.Name(), &result)
+
+	if h.historyEvaluation != nil && state.History != nil && state.History.Size > 0 {
+		var ratio float64
+		result.history, ratio = h.historyEvaluation.restore(state.History.Passed, state.History.Size)
+		result.RecentPassRatio = &ratio
+	}
+	return result
+}
+
+// importAdaptivePeriod restores the adaptive period of the task, when it is adaptively scheduled, and the period is in range.
+func (t *checkTask) importAdaptivePeriod(state CheckState) {
+	if !t.adaptiveScheduling() || state.AdaptivePeriod < t.cfg.ExecutionPeriod || state.AdaptivePeriod > t.cfg.MaxExecutionPeriod {
+		return
+	}
+
+	t.adaptive.lock.Lock()
+	defer t.adaptive.lock.Unlock()
+	t.adaptive.current = state.AdaptivePeriod
+	t.adaptive.passes = state.StablePasses
+}
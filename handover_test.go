@@ -0,0 +1,137 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestExportImport(t *testing.T) {
+	old := New(WithHistoryEvaluation(4, 0.5))
+	defer old.DeregisterAll()
+
+	register := func(h Health, flaky ...checks.ResultSpec) {
+		assert.NoError(t, h.RegisterChecks(
+			&Config{
+				Check:           checks.NewScriptedCheck("flaky.check", flaky...),
+				InitialDelay:    time.Hour,
+				ExecutionPeriod: time.Minute,
+				Owner:           "storage",
+			},
+			&Config{
+				Check:              checks.NewScriptedCheck("adaptive.check", checks.PassResult("ok")),
+				InitialDelay:       time.Hour,
+				ExecutionPeriod:    time.Minute,
+				MaxExecutionPeriod: 10 * time.Minute,
+				StableExecutions:   2,
+			},
+			&Config{
+				Check:            checks.NewScriptedCheck("pending.check", checks.PassResult("ok")),
+				InitialDelay:     time.Hour,
+				ExecutionPeriod:  time.Minute,
+				InitiallyPassing: true,
+			},
+		))
+	}
+	register(old, checks.PassResult("ok"), checks.PassResult("ok"), checks.FailResult(errors.New("down")))
+	for i := 0; i < 3; i++ {
+		_, _ = old.Trigger("flaky.check")
+		_, _ = old.Trigger("adaptive.check")
+	}
+	oldResults, _ := old.Results()
+
+	snapshot := old.Export()
+	assert.Equal(t, SnapshotVersion, snapshot.Version)
+	assert.Len(t, snapshot.Checks, 2, "checks that didn't execute yet are not exported")
+	assert.Equal(t, "down", snapshot.Checks["flaky.check"].Error)
+	assert.Equal(t, &HistoryState{Passed: 6, Size: 3}, snapshot.Checks["flaky.check"].History)
+	assert.Equal(t, 2*time.Minute, snapshot.Checks["adaptive.check"].AdaptivePeriod)
+	assert.Equal(t, 1, snapshot.Checks["adaptive.check"].StablePasses)
+
+	encoded, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+	var decoded Snapshot
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	replacement := New(WithHistoryEvaluation(4, 0.5)).(*health)
+	defer replacement.DeregisterAll()
+	register(replacement, checks.FailResult(errors.New("down")))
+	assert.False(t, replacement.IsHealthy(), "checks are initially failing")
+	assert.Equal(t, time.Minute, replacement.checkTasks["adaptive.check"].period())
+	decoded.Checks["unknown.check"] = CheckState{Error: "ignored"}
+
+	assert.NoError(t, replacement.Import(decoded))
+	results, healthy := replacement.Results()
+	assert.True(t, healthy, "the imported history is healthy enough")
+	assert.True(t, replacement.IsHealthy(), "the imported history is healthy enough")
+	assert.NotContains(t, results, "unknown.check", "states of unknown checks are ignored")
+	assert.Equal(t, "didn't run yet", results["pending.check"].Details, "checks without a state keep their initial result")
+
+	flaky := results["flaky.check"]
+	assert.EqualError(t, flaky.Error, "down")
+	assert.Equal(t, oldResults["flaky.check"].ContiguousFailures, flaky.ContiguousFailures)
+	assert.True(t, oldResults["flaky.check"].TimeOfFirstFailure.Equal(*flaky.TimeOfFirstFailure))
+	assert.True(t, oldResults["flaky.check"].Timestamp.Equal(flaky.Timestamp))
+	assert.Equal(t, *oldResults["flaky.check"].RecentPassRatio, *flaky.RecentPassRatio)
+	assert.Equal(t, "storage", flaky.Owner, "metadata is taken from the registered check")
+	assert.Equal(t, 2*time.Minute, replacement.checkTasks["adaptive.check"].period(), "the adaptive period is restored")
+
+	// the next execution continues the imported history and streak
+	result, _ := replacement.Trigger("flaky.check")
+	assert.Equal(t, int64(2), result.ContiguousFailures, "failure streak continues")
+	assert.True(t, flaky.TimeOfFirstFailure.Equal(*result.TimeOfFirstFailure), "failure streak continues")
+	assert.Equal(t, uint(4), result.history.size, "history continues")
+}
+
+func TestImport_keepsExecutedResults(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("passing.check", checks.PassResult("ok")),
+		InitialDelay:    time.Hour,
+		ExecutionPeriod: time.Minute,
+	}))
+	_, err := h.Trigger("passing.check")
+	assert.NoError(t, err)
+
+	err = h.Import(Snapshot{Version: SnapshotVersion, Checks: map[string]CheckState{
+		"passing.check": {Error: "stale failure", ContiguousFailures: 7},
+	}})
+	assert.NoError(t, err)
+	results, healthy := h.Results()
+	assert.True(t, healthy, "results of executed checks are more recent than the imported ones")
+	assert.Nil(t, results["passing.check"].Error)
+
+	err = h.Import(Snapshot{Version: SnapshotVersion + 1})
+	assert.EqualError(t, err, "unsupported snapshot version 2, expected 1")
+}
+
+func TestImport_notifiesListeners(t *testing.T) {
+	checkEvents := &checkEventsRecorder{}
+	var reported map[string]Result
+	h := New(
+		WithCheckListeners(checkEvents),
+		WithHealthListeners(healthListenerFunc(func(results map[string]Result) { reported = results })),
+	)
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("imported.check", checks.PassResult("ok")),
+		InitialDelay:    time.Hour,
+		ExecutionPeriod: time.Minute,
+	}))
+
+	err := h.Import(Snapshot{Version: SnapshotVersion, Checks: map[string]CheckState{
+		"imported.check": {Error: "down", ContiguousFailures: 3},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"registered imported.check", "completed imported.check"}, checkEvents.events)
+	if assert.Contains(t, reported, "imported.check", "the health listener should observe the imported results") {
+		assert.EqualError(t, reported["imported.check"].Error, "down")
+		assert.Equal(t, int64(3), reported["imported.check"].ContiguousFailures)
+	}
+}
@@ -1,11 +1,15 @@
 package gosundheit
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
 )
 
 // Health is the API for registering / deregistering health checks, and for fetching the health checks results.
@@ -13,199 +17,515 @@ type Health interface {
 	// RegisterCheck registers a health check according to the given configuration.
 	// Once RegisterCheck() is called, the check is scheduled to run in it's own goroutine.
 	// Callers must make sure the checks complete at a reasonable time frame, or the next execution will delay.
+	// A check registered with the name of an already registered check replaces it.
 	RegisterCheck(cfg *Config) error
+	// RegisterChecks registers the health checks according to the given configurations, all or nothing:
+	// all the configurations are validated first, and when any of them is invalid, or two checks share a name,
+	// no check is registered, and a MultiError listing all the errors is returned.
+	RegisterChecks(cfgs ...*Config) error
 	// Deregister removes a health check from this instance, and stops it's next executions.
-	// If the check is running while Deregister() is called, the check may complete it's current execution.
-	// Once a check is removed, it's results are no longer returned.
+	// If the check is running while Deregister() is called, the check may complete it's current execution,
+	// but its result is discarded.
+	// The check and its results are removed by the time Deregister() returns, so the name may be registered again right away.
 	Deregister(name string)
 	// Results returns a snapshot of the health checks execution results at the time of calling, and the current health.
-	// A system is considered healthy iff all checks are passing
+	// A system is considered healthy iff all checks are passing, ignoring leader only checks on non leader instances.
+	// The returned results map is shared between callers, and must not be modified.
 	Results() (results map[string]Result, healthy bool)
+	// ResultsJSON returns the JSON encoding of the current results, as served by the HTTP handler.
+	// The encoding is cached until the results change, so it is cheap to call on hot paths.
+	// The returned slice is shared between callers, and must not be modified.
+	// Returns nil when the results can't be encoded (e.g. when a check returns details that can't be marshaled to JSON).
+	ResultsJSON() []byte
 	// IsHealthy returns the current health of the system.
-	// A system is considered healthy iff all checks are passing.
+	// A system is considered healthy iff all checks are passing, ignoring leader only checks on non leader instances.
 	IsHealthy() bool
+	// ImpactedCapabilities returns the sorted capabilities gated by the failing checks (see the check Config.Capabilities),
+	// ignoring leader only checks on non leader instances, so e.g. an API gateway can shed only the affected routes.
+	ImpactedCapabilities() []string
+	// Score returns the weighted health score (0-100), i.e. the percentage of the total weight of the checks which are passing,
+	// ignoring leader only checks on non leader instances. Checks are weighted using the check Config.Weight.
+	// The score allows graded decisions (e.g. by autoscalers and traffic shifters), rather than binary ones.
+	Score() float64
+	// WaitForHealthy blocks until the system is healthy, or until the context is done.
+	// Returns nil once the system is healthy, or the context error otherwise.
+	// The health is re-evaluated whenever the results change.
+	WaitForHealthy(ctx context.Context) error
+	// Checks returns the configurations of the registered checks, keyed by check name.
+	Checks() map[string]Config
+	// Generation returns the generation of the current results.
+	// The generation increases monotonically whenever results change (on check executions, registrations and deregistrations),
+	// so consumers may compare it with the last seen generation to cheaply detect changes, or missed updates.
+	Generation() uint64
+	// Events returns a channel of the check lifecycle events (registered, started, completed, failed and deregistered).
+	// Events are emitted only once Events() was first called, and the same channel is returned on each call.
+	// Emitting events never blocks the checks: once the channel buffer is full, the oldest events are dropped.
+	Events() <-chan Event
+	// Costs returns the execution costs of the registered checks, keyed by check name, accumulated since their registration,
+	// so e.g. capacity planners can find the checks consuming the most wall-clock time, and tune their periods.
+	Costs() map[string]Cost
+	// Trigger executes the registered check with the given name right away, out of its schedule, and returns the updated result.
+	// The triggered execution doesn't affect the schedule of the check, and may run concurrently with a scheduled execution.
+	// Returns an error when no check is registered with the given name.
+	Trigger(name string) (Result, error)
+	// TriggerContext is Trigger, bounded by the deadline of the given context, which is propagated to the check
+	// (see checks.ContextCheck). An execution abandoned once the context is done doesn't update the result of the check,
+	// so impatient callers don't fail it, and the context error is returned.
+	TriggerContext(ctx context.Context, name string) (Result, error)
+	// Export returns the serializable health state of the executed checks: their results, failure streaks and histories,
+	// so a replacement process (e.g. during a blue-green handover) may start with the health knowledge of this instance.
+	Export() Snapshot
+	// Import restores the health state of the given Snapshot, exported by a previous instance.
+	// Only the states of registered checks that didn't execute yet are restored, so checks should be registered
+	// (with an InitialDelay long enough to import the snapshot) before importing it; states of unknown checks are ignored.
+	// Returns an error when the snapshot version is unsupported.
+	Import(snapshot Snapshot) error
 	// DeregisterAll Deregister removes all health checks from this instance, and stops their next executions.
-	// It is equivalent of calling Deregister() for each currently registered check.
+	// It is equivalent of calling Deregister() for each currently registered check,
+	// except that it blocks until the running executions complete, so no check is executing once it returns.
+	// DeregisterAll must not be called from within a check or a listener.
 	DeregisterAll()
 }
 
 // New returns a new Health instance.
 func New(opts ...Option) Health {
 	h := &health{
-		results:    make(map[string]Result, maxExpectedChecks),
-		checkTasks: make(map[string]checkTask, maxExpectedChecks),
+		results:    newResultsStore(),
+		checkTasks: make(map[string]*checkTask, maxExpectedChecks),
 		lock:       sync.RWMutex{},
 	}
+	h.snapshot.Store(&resultsSnapshot{results: map[string]Result{}})
 	for _, opt := range append(opts, WithDefaults()) {
 		opt(h)
 	}
+	h.events.now = h.now
+	h.checksListener = append(append(CheckListeners{}, h.checksListener...), &h.events)
+	if h.sharedSchedulerWorkers > 0 {
+		h.scheduler = newSharedScheduler(h, h.sharedSchedulerWorkers, h.probeShedding)
+	} else {
+		h.scheduler = &goroutineScheduler{h: h}
+	}
 	return h
 }
 
 type health struct {
-	results        map[string]Result
-	checkTasks     map[string]checkTask
-	checksListener CheckListeners
-	healthListener HealthListeners
-	lock           sync.RWMutex
+	// 64 bit atomically accessed fields come first, for alignment on 32 bit platforms
+	generation             uint64
+	schedulerLag           lagTracker
+	aggregate              healthAggregate
+	results                *resultsStore
+	snapshot               atomic.Value
+	snapshotLock           sync.Mutex
+	changes                changeNotifier
+	events                 eventsEmitter
+	checkTasks             map[string]*checkTask
+	checksListener         CheckListeners
+	healthListener         HealthListeners
+	isLeader               func() bool
+	now                    func() time.Time
+	scheduler              scheduler
+	sharedSchedulerWorkers int
+	probeShedding          bool
+	panicQuarantine        int32
+	maxDetailsSize         int
+	strictNames            bool
+	historyEvaluation      *historyEvaluation
+	concurrencyGroups      concurrencyGroups
+	resultEnrichers        resultEnrichers
+	// lock guards the check tasks, results are guarded by the results store
+	lock sync.RWMutex
 }
 
 func (h *health) RegisterCheck(cfg *Config) error {
-	if cfg.Check == nil || cfg.Check.Name() == "" {
-		return errors.Errorf("misconfigured check %v", cfg.Check)
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+	if h.strictNames {
+		if err := ValidateName(cfg.Check.Name()); err != nil {
+			return err
+		}
 	}
 
-	// checks are initially failing by default, but we allow overrides...
-	var initialErr error
-	if !cfg.InitiallyPassing {
-		initialErr = fmt.Errorf(initialResultMsg)
+	if errs := h.register([]*Config{cfg}); len(errs) > 0 {
+		return errs[0]
 	}
+	return nil
+}
 
-	result := h.updateResult(cfg.Check.Name(), initialResultMsg, 0, initialErr, time.Now())
-	h.checksListener.OnCheckRegistered(cfg.Check.Name(), result)
-	h.scheduleCheck(h.createCheckTask(cfg), cfg)
+func (h *health) RegisterChecks(cfgs ...*Config) error {
+	var errs MultiError
+	names := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		if err := validateConfig(cfg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := cfg.Check.Name()
+		if names[name] {
+			errs = append(errs, errors.Errorf("duplicate check %s", name))
+		}
+		names[name] = true
+		if h.strictNames {
+			if err := ValidateName(name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if errs := h.register(cfgs); len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-func (h *health) createCheckTask(cfg *Config) *checkTask {
-	h.lock.Lock()
-	defer h.lock.Unlock()
+func validateConfig(cfg *Config) error {
+	if cfg == nil {
+		return errors.Errorf("misconfigured check: nil config")
+	}
+	if cfg.Check == nil || cfg.Check.Name() == "" {
+		return errors.Errorf("misconfigured check %v", cfg.Check)
+	}
+	if cfg.ExecutionPeriod <= 0 {
+		return errors.Errorf("misconfigured check %s execution period %v, must be positive", cfg.Check.Name(), cfg.ExecutionPeriod)
+	}
+	if cfg.Weight < 0 {
+		return errors.Errorf("misconfigured check %s weight %v, must not be negative", cfg.Check.Name(), cfg.Weight)
+	}
+	if cfg.Retries < 0 || cfg.RetryDelay < 0 || (cfg.RetryBackoff != 0 && cfg.RetryBackoff < 1) {
+		return errors.Errorf("misconfigured check %s retries %d delay %v backoff %v", cfg.Check.Name(), cfg.Retries, cfg.RetryDelay, cfg.RetryBackoff)
+	}
+	if cfg.MaxExecutionPeriod < 0 || cfg.StableExecutions < 0 {
+		return errors.Errorf("misconfigured check %s max execution period %v stable executions %d, must not be negative",
+			cfg.Check.Name(), cfg.MaxExecutionPeriod, cfg.StableExecutions)
+	}
+	if cfg.HedgeDelay < 0 {
+		return errors.Errorf("misconfigured check %s hedge delay %v, must not be negative", cfg.Check.Name(), cfg.HedgeDelay)
+	}
+	if cfg.GracePeriod < 0 {
+		return errors.Errorf("misconfigured check %s grace period %v, must not be negative", cfg.Check.Name(), cfg.GracePeriod)
+	}
+	if cfg.ConcurrencyGroup != "" && cfg.HedgeDelay > 0 {
+		return errors.Errorf("misconfigured check %s, hedged executions can't be serialized by concurrency group %s",
+			cfg.Check.Name(), cfg.ConcurrencyGroup)
+	}
+	if cfg.Timeout < 0 {
+		return errors.Errorf("misconfigured check %s timeout %v, must not be negative", cfg.Check.Name(), cfg.Timeout)
+	}
+	for _, window := range cfg.Silences {
+		if err := window.validate(); err != nil {
+			return errors.Wrapf(err, "misconfigured check %s silence window", cfg.Check.Name())
+		}
+	}
+	return nil
+}
 
-	task := checkTask{
-		stopChan: make(chan bool, 1),
-		check:    cfg.Check,
+// register registers the given valid checks at once, and schedules them.
+// Returns the name collisions errors, in which case no check is registered.
+func (h *health) register(cfgs []*Config) MultiError {
+	added, errs := h.addCheckTasks(cfgs)
+	if len(errs) > 0 {
+		return errs
 	}
-	h.checkTasks[cfg.Check.Name()] = task
+	for _, task := range added {
+		if task.replaced != nil {
+			h.scheduler.unschedule(task.replaced)
+			h.checksListener.OnCheckDeregistered(task.check.Name())
+		}
+		h.checksListener.OnCheckRegistered(task.check.Name(), h.reportedResult(task.cfg, task.result))
+	}
+	for _, task := range added {
+		h.scheduler.schedule(task.checkTask)
+	}
+	return nil
+}
 
-	return &task
+type addedTask struct {
+	*checkTask
+	result   Result
+	replaced *checkTask
 }
 
-func (h *health) stopCheckTask(name string) {
+// addCheckTasks creates the check tasks and records their initial results, replacing the checks registered with the same names, if any.
+// Returns the added tasks, whose replaced tasks must be unscheduled by the caller.
+// With strict names, no task is added when the names collide after normalization, and the collisions errors are returned.
+func (h *health) addCheckTasks(cfgs []*Config) ([]addedTask, MultiError) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
+	if h.strictNames {
+		if errs := h.nameCollisions(cfgs); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+	defer h.changes.notify()
+
+	added := make([]addedTask, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		// checks are initially failing by default, but we allow overrides...
+		var initialErr error
+		if !cfg.InitiallyPassing {
+			initialErr = fmt.Errorf(initialResultMsg)
+		}
 
-	task := h.checkTasks[name]
+		replaced := h.removeCheckTask(cfg.Check.Name())
+		taskCfg := *cfg
+		task := &checkTask{
+			stopChan:   make(chan struct{}),
+			check:      cfg.Check,
+			cfg:        &taskCfg,
+			registered: h.now(),
+			queueIndex: -1,
+			adaptive:   adaptivePeriod{current: cfg.ExecutionPeriod},
+		}
+		task.active.Add(1)
+		if cfg.TTL > 0 {
+			task.expiry = time.AfterFunc(cfg.TTL, func() { h.deregisterTask(task) })
+		}
+		h.checkTasks[cfg.Check.Name()] = task
+		result := h.storeResult(task, checks.ExecutionResult{Details: initialResultMsg, Err: initialErr}, 0, task.registered)
+		added = append(added, addedTask{checkTask: task, result: result, replaced: replaced})
+	}
 
-	task.stop()
+	return added, nil
+}
 
-	delete(h.results, name)
+// removeCheckTask removes the task registered with the given name, and its results.
+// Returns the removed task, or nil when no task is registered with the given name.
+// Callers must hold the lock, and notify h.changes.
+func (h *health) removeCheckTask(name string) *checkTask {
+	task, ok := h.checkTasks[name]
+	if !ok {
+		return nil
+	}
+
+	if task.expiry != nil {
+		task.expiry.Stop()
+	}
+	if removed, existed := h.results.delete(name); existed {
+		h.aggregate.update(task.cfg, !removed.affectsHealth(), true)
+		h.invalidateSnapshot()
+	}
 	delete(h.checkTasks, name)
+	return task
 }
 
-func (h *health) scheduleCheck(task *checkTask, cfg *Config) {
-	go func() {
-		// initial execution
-		if !h.runCheckOrStop(task, time.After(cfg.InitialDelay)) {
-			return
-		}
-		h.reportResults()
-		// scheduled recurring execution
-		task.ticker = time.NewTicker(cfg.ExecutionPeriod)
-		for {
-			if !h.runCheckOrStop(task, task.ticker.C) {
-				return
-			}
-			h.reportResults()
-		}
-	}()
+// runTask executes the task, and reports the updated results
+func (h *health) runTask(task *checkTask) {
+	h.checkAndUpdateResult(context.Background(), task, h.now())
+	h.reportResults()
 }
 
-func (h *health) reportResults() {
+// shedTask marks the result of the task as shed, and reports the updated results.
+// The previous result is kept as is otherwise, so the shed execution doesn't count in the health, the history or the failure streak.
+func (h *health) shedTask(task *checkTask) {
 	h.lock.RLock()
-	resultsCopy := copyResultsMap(h.results)
+	if h.checkTasks[task.check.Name()] == task {
+		h.results.update(task.check.Name(), func(prevResult Result, ok bool) Result {
+			prevResult.Shed = true
+			prevResult.Generation = h.invalidateSnapshot()
+			return prevResult
+		})
+		h.changes.notify()
+	}
 	h.lock.RUnlock()
-	h.healthListener.OnResultsUpdated(resultsCopy)
+	h.reportResults()
 }
 
-func (h *health) runCheckOrStop(task *checkTask, timerChan <-chan time.Time) bool {
-	select {
-	case <-task.stopChan:
-		h.stopCheckTask(task.check.Name())
-		return false
-	case t := <-timerChan:
-		h.checkAndUpdateResult(task, t)
-		return true
+func (h *health) reportResults() {
+	h.healthListener.OnResultsUpdated(h.currentSnapshot().resultsOf(h.isLeader()))
+}
+
+// checkAndUpdateResult executes the task and updates its result, unless the context was done during the execution.
+// The execution waits for the concurrency group of the task, if any.
+func (h *health) checkAndUpdateResult(ctx context.Context, task *checkTask, checkTime time.Time) {
+	if task.cfg.ConcurrencyGroup != "" {
+		if !h.concurrencyGroups.acquire(ctx, task.cfg.ConcurrencyGroup, task.stopChan) {
+			// the task was unscheduled, or the context was done, while waiting for its group
+			return
+		}
+		defer h.concurrencyGroups.release(task.cfg.ConcurrencyGroup)
 	}
+	h.executeAndUpdateResult(ctx, task, checkTime)
 }
 
-func (h *health) checkAndUpdateResult(task *checkTask, checkTime time.Time) {
+// executeAndUpdateResult executes the task and updates its result, unless the context was done during the execution.
+// Callers must have acquired the concurrency group of the task, if any.
+func (h *health) executeAndUpdateResult(ctx context.Context, task *checkTask, checkTime time.Time) {
+	if task.cfg.LeaderPolicy == LeaderOnlyExecution && !h.isLeader() {
+		h.updateResult(task, checks.ExecutionResult{Details: notLeaderMsg}, 0, checkTime)
+		return
+	}
+
 	h.checksListener.OnCheckStarted(task.check.Name())
-	details, duration, err := task.execute()
-	result := h.updateResult(task.check.Name(), details, duration, err, checkTime)
-	h.checksListener.OnCheckCompleted(task.check.Name(), result)
+	outcome, duration := task.execute(ctx, h.now)
+	if ctx.Err() != nil {
+		return
+	}
+	if panics := task.recordPanics(outcome); h.panicQuarantine > 0 && panics >= h.panicQuarantine {
+		h.quarantine(task, outcome, panics, duration, checkTime)
+		return
+	}
+	if result, ok := h.updateResult(task, outcome, duration, checkTime); ok {
+		task.adaptPeriod(result)
+		h.checksListener.OnCheckCompleted(task.check.Name(), result)
+		if task.cfg.DeregisterOnPass && result.IsHealthy() {
+			h.deregisterTask(task)
+		}
+	}
 }
 
 func (h *health) Deregister(name string) {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
+	h.lock.Lock()
+	task := h.removeCheckTask(name)
+	h.changes.notify()
+	h.lock.Unlock()
 
-	task, ok := h.checkTasks[name]
-	if ok {
-		// actual cleanup happens in the task go routine
-		task.stopChan <- true
+	if task != nil {
+		h.scheduler.unschedule(task)
+		h.checksListener.OnCheckDeregistered(name)
+	}
+}
+
+// deregisterTask deregisters the given task, unless it was already deregistered or replaced.
+func (h *health) deregisterTask(task *checkTask) {
+	name := task.check.Name()
+	h.lock.Lock()
+	current := h.checkTasks[name] == task
+	if current {
+		h.removeCheckTask(name)
+		h.changes.notify()
+	}
+	h.lock.Unlock()
+
+	if current {
+		h.scheduler.unschedule(task)
+		h.checksListener.OnCheckDeregistered(name)
 	}
 }
 
 func (h *health) DeregisterAll() {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
+	h.lock.Lock()
+	tasks := make([]*checkTask, 0, len(h.checkTasks))
+	for name := range h.checkTasks {
+		tasks = append(tasks, h.removeCheckTask(name))
+	}
+	h.changes.notify()
+	h.lock.Unlock()
 
-	for _, task := range h.checkTasks {
-		task.stopChan <- true
+	for _, task := range tasks {
+		h.scheduler.unschedule(task)
+	}
+	for _, task := range tasks {
+		task.active.Wait()
+		h.checksListener.OnCheckDeregistered(task.check.Name())
 	}
 }
 
 func (h *health) Results() (results map[string]Result, healthy bool) {
+	snapshot := h.currentSnapshot()
+	leader := h.isLeader()
+	return snapshot.resultsOf(leader), snapshot.healthy(leader)
+}
+
+func (h *health) ResultsJSON() []byte {
+	return h.currentSnapshot().encodeJSON()
+}
+
+func (h *health) Checks() map[string]Config {
 	h.lock.RLock()
 	defer h.lock.RUnlock()
 
-	results = make(map[string]Result, len(h.results))
-
-	healthy = true
-	for k, v := range h.results {
-		results[k] = v
-		healthy = healthy && v.IsHealthy()
+	configs := make(map[string]Config, len(h.checkTasks))
+	for name, task := range h.checkTasks {
+		configs[name] = *task.cfg
 	}
+	return configs
+}
 
-	return
+func (h *health) Generation() uint64 {
+	return atomic.LoadUint64(&h.generation)
 }
 
 func (h *health) IsHealthy() (healthy bool) {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
-
-	return allHealthy(h.results)
+	return h.aggregate.healthy(h.isLeader())
 }
 
+// updateResult records the result of a task execution.
+// Results of tasks that are no longer registered (i.e. deregistered or replaced during the execution) are discarded,
+// in which case ok is false.
 func (h *health) updateResult(
-	name string, details interface{}, checkDuration time.Duration, err error, t time.Time) (result Result) {
+	task *checkTask, outcome checks.ExecutionResult, checkDuration time.Duration, t time.Time) (result Result, ok bool) {
 
-	h.lock.Lock()
-	defer h.lock.Unlock()
+	h.lock.RLock()
+	defer h.lock.RUnlock()
 
-	prevResult, ok := h.results[name]
-	result = Result{
-		Details:            details,
-		Error:              newMarshalableError(err),
-		Timestamp:          t,
-		Duration:           checkDuration,
-		TimeOfFirstFailure: nil,
+	if h.checkTasks[task.check.Name()] != task {
+		return Result{}, false
 	}
+	defer h.changes.notify()
+	return h.reportedResult(task.cfg, h.storeResult(task, outcome, checkDuration, t)), true
+}
 
-	if !result.IsHealthy() {
-		if ok {
-			result.ContiguousFailures = prevResult.ContiguousFailures + 1
-			if prevResult.IsHealthy() {
-				result.TimeOfFirstFailure = &t
+// reportedResult returns the result as reported by this instance:
+// on non leader instances, the results of leader only checks are marked as not affecting the health.
+func (h *health) reportedResult(cfg *Config, result Result) Result {
+	result.notLeader = cfg.LeaderPolicy != AnyInstance && !h.isLeader()
+	return result
+}
+
+// storeResult stores the result of the check. Callers must hold the lock (for reading at least), and notify h.changes.
+func (h *health) storeResult(
+	task *checkTask, outcome checks.ExecutionResult, checkDuration time.Duration, t time.Time) (result Result) {
+
+	cfg := task.cfg
+	return h.results.update(cfg.Check.Name(), func(prevResult Result, ok bool) Result {
+		result := Result{
+			Generation:         h.invalidateSnapshot(),
+			Details:            limitDetails(outcome.Details, h.maxDetailsSize),
+			Error:              newMarshalableError(outcome.Err),
+			Degraded:           outcome.Status == checks.StatusDegraded,
+			Silenced:           outcome.Err != nil && silenced(cfg.Silences, t),
+			InGracePeriod:      outcome.Err != nil && t.Sub(task.registered) < cfg.GracePeriod,
+			Timestamp:          t,
+			Duration:           checkDuration,
+			Timings:            outcome.Timings,
+			TimeOfFirstFailure: nil,
+			Classification:     cfg.Classification,
+			Description:        cfg.Description,
+			RunbookURL:         cfg.RunbookURL,
+			Owner:              cfg.Owner,
+			Annotations:        cfg.Annotations,
+			Weight:             cfg.Weight,
+			Capabilities:       cfg.Capabilities,
+			Quarantined:        task.isQuarantined(),
+			severity:           cfg.Severity,
+		}
+		h.resultEnrichers.enrich(cfg.Check.Name(), &result)
+
+		if !result.IsHealthy() {
+			if ok {
+				result.ContiguousFailures = prevResult.ContiguousFailures + 1
+				if prevResult.IsHealthy() {
+					result.TimeOfFirstFailure = &t
+				} else {
+					result.TimeOfFirstFailure = prevResult.TimeOfFirstFailure
+				}
 			} else {
-				result.TimeOfFirstFailure = prevResult.TimeOfFirstFailure
+				result.ContiguousFailures = 1
+				result.TimeOfFirstFailure = &t
 			}
-		} else {
-			result.ContiguousFailures = 1
-			result.TimeOfFirstFailure = &t
 		}
-	}
 
-	h.results[name] = result
-	return result
+		// the initial result isn't an execution, so it doesn't count in the history
+		if h.historyEvaluation != nil && result.Executed() {
+			var ratio float64
+			result.history, ratio = h.historyEvaluation.record(prevResult.history, result.IsHealthy())
+			result.RecentPassRatio = &ratio
+		}
+
+		h.aggregate.update(cfg, !ok || !prevResult.affectsHealth(), !result.affectsHealth())
+		return result
+	})
 }
@@ -1,6 +1,9 @@
 package gosundheit
 
+// HealthListener can be used to track changes in the registered checks results.
 type HealthListener interface {
+	// OnResultsUpdated is called after each check execution with a snapshot of all the results.
+	// The results map is shared between listeners, and must not be modified.
 	OnResultsUpdated(results map[string]Result)
 }
 
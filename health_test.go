@@ -1,9 +1,11 @@
 package gosundheit
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -79,6 +81,7 @@ func TestRegisterDeregister(t *testing.T) {
 	assert.Contains(t, passingCheck.String(), "didn't run yet", "initial details")
 	assert.Contains(t, failingCheck.String(), "didn't run yet", "initial details")
 	assert.Contains(t, initiallyPassingCheck.String(), "didn't run yet", "initial details")
+	assert.False(t, passingCheck.Executed(), "check didn't execute yet")
 
 	// await first execution
 	time.Sleep(50 * time.Millisecond)
@@ -103,6 +106,7 @@ func TestRegisterDeregister(t *testing.T) {
 	assert.Contains(t, passingCheck.String(), "success", "details after execution")
 	assert.Contains(t, failingCheck.String(), "fail", "details after execution")
 	assert.Contains(t, initiallyPassingCheck.String(), "success", "details after execution")
+	assert.True(t, passingCheck.Executed(), "check executed")
 
 	h.Deregister(failingCheckName)
 	// await check cleanup
@@ -187,6 +191,48 @@ func TestCheckListener(t *testing.T) {
 	}
 }
 
+func TestCheckMetadata(t *testing.T) {
+	listenerMock := &checkListenerMock{}
+	listenerMock.On("OnCheckRegistered", passingCheckName, mock.AnythingOfType("Result")).Return()
+	listenerMock.On("OnCheckStarted", passingCheckName).Return()
+	listenerMock.On("OnCheckCompleted", passingCheckName, mock.AnythingOfType("Result")).Return()
+	h := New(WithCheckListeners(listenerMock))
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: passingCheckName,
+			CheckFunc: func() (details interface{}, err error) { return successMsg, nil },
+		},
+		ExecutionPeriod: 20 * time.Millisecond,
+		Description:     "verifies the passing check passes",
+		RunbookURL:      "https://runbooks.example.org/passing-check",
+		Owner:           "storage-team",
+		Annotations:     map[string]string{"pager": "storage-oncall"},
+	})
+
+	configs := h.Checks()
+	assert.Len(t, configs, 1, "num registered checks")
+	assert.Equal(t, "verifies the passing check passes", configs[passingCheckName].Description)
+	assert.Equal(t, "https://runbooks.example.org/passing-check", configs[passingCheckName].RunbookURL)
+
+	// await first execution
+	time.Sleep(10 * time.Millisecond)
+
+	results, _ := h.Results()
+	assert.Equal(t, "verifies the passing check passes", results[passingCheckName].Description)
+	assert.Equal(t, "https://runbooks.example.org/passing-check", results[passingCheckName].RunbookURL)
+	assert.Contains(t, string(h.ResultsJSON()), "\"runbookUrl\": \"https://runbooks.example.org/passing-check\"")
+	assert.Contains(t, string(h.ResultsJSON()), "\"owner\": \"storage-team\"")
+
+	completedChecks := listenerMock.getCompletedChecks()
+	assert.Len(t, completedChecks, 1, "num completed checks")
+	assert.Equal(t, "verifies the passing check passes", completedChecks[0].res.Description)
+	assert.Equal(t, "https://runbooks.example.org/passing-check", completedChecks[0].res.RunbookURL)
+	assert.Equal(t, "storage-team", completedChecks[0].res.Owner)
+	assert.Equal(t, map[string]string{"pager": "storage-oncall"}, completedChecks[0].res.Annotations)
+}
+
 func TestHealthListeners(t *testing.T) {
 
 	listenerMock := &healthListenerMock{}
@@ -247,3 +293,715 @@ type healthListenerMock struct {
 func (h *healthListenerMock) OnResultsUpdated(results map[string]Result) {
 	h.Called(results)
 }
+
+func TestLeaderElection(t *testing.T) {
+	var leader int32
+	h := New(WithLeaderElection(func() bool { return atomic.LoadInt32(&leader) == 1 }))
+	defer h.DeregisterAll()
+
+	executions := int32(0)
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "leader.execution.check",
+			CheckFunc: func() (details interface{}, err error) {
+				atomic.AddInt32(&executions, 1)
+				return nil, errors.New(failedMsg)
+			},
+		},
+		ExecutionPeriod: 10 * time.Millisecond,
+		LeaderPolicy:    LeaderOnlyExecution,
+	})
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "leader.health.check",
+			CheckFunc: func() (details interface{}, err error) {
+				return nil, errors.New(failedMsg)
+			},
+		},
+		ExecutionPeriod: 10 * time.Millisecond,
+		LeaderPolicy:    LeaderOnlyHealth,
+	})
+
+	// await executions
+	time.Sleep(30 * time.Millisecond)
+
+	assert.True(t, h.IsHealthy(), "leader only checks should not affect the health of a non leader")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "leader only checks should not affect the health results of a non leader")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&executions), "leader only executions should be skipped")
+	assert.Equal(t, "execution skipped - this instance is not the leader", results["leader.execution.check"].Details)
+	assert.False(t, results["leader.health.check"].IsHealthy(), "leader only health checks are still executed")
+	assert.False(t, results["leader.health.check"].AffectsHealth(), "leader only checks should not affect the health of a non leader")
+
+	atomic.StoreInt32(&leader, 1)
+	// await executions
+	time.Sleep(30 * time.Millisecond)
+
+	assert.False(t, h.IsHealthy(), "leader only checks should affect the health of the leader")
+	results, healthy = h.Results()
+	assert.False(t, healthy, "leader only checks should affect the health results of the leader")
+	assert.True(t, results["leader.health.check"].AffectsHealth(), "leader only checks should affect the health of the leader")
+	assert.True(t, atomic.LoadInt32(&executions) > 0, "leader only checks should execute on the leader")
+}
+
+// awaitCondition polls the condition until it holds, and fails the test when it doesn't within a second
+func awaitCondition(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for condition: %s", msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSharedScheduler(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New(WithSharedScheduler(4))
+
+	const numChecks = 100
+	var executions int32
+	for i := 0; i < numChecks; i++ {
+		_ = h.RegisterCheck(&Config{
+			Check: &checks.CustomCheck{
+				CheckName: fmt.Sprintf("check.%d", i),
+				CheckFunc: func() (details interface{}, err error) {
+					atomic.AddInt32(&executions, 1)
+					return successMsg, nil
+				},
+			},
+			InitialDelay:    time.Duration(i%10) * time.Millisecond,
+			ExecutionPeriod: 10 * time.Millisecond,
+		})
+	}
+
+	awaitCondition(t, func() bool {
+		return atomic.LoadInt32(&executions) >= 2*numChecks
+	}, "each check should execute repeatedly")
+	awaitCondition(t, h.IsHealthy, "health after all checks executed")
+	results, _ := h.Results()
+	assert.Equal(t, numChecks, len(results), "num results")
+
+	h.Deregister("check.0")
+	results, _ = h.Results()
+	assert.Equal(t, numChecks-1, len(results), "num results after deregistration")
+	_, ok := results["check.0"]
+	assert.False(t, ok, "check should have been removed")
+
+	h.DeregisterAll()
+	results, _ = h.Results()
+	assert.Empty(t, results, "results after stop")
+}
+
+func TestSharedScheduler_deregisterDuringExecution(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New(WithSharedScheduler(1))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "slow.check",
+			CheckFunc: func() (details interface{}, err error) {
+				close(started)
+				<-release
+				return successMsg, nil
+			},
+		},
+		ExecutionPeriod: time.Hour,
+	})
+
+	<-started
+	h.Deregister("slow.check")
+	close(release)
+
+	// DeregisterAll awaits the completion of the deregistered check execution
+	h.DeregisterAll()
+	results, _ := h.Results()
+	assert.Empty(t, results, "check completing after deregistration should be removed")
+}
+
+func TestSharedScheduler_probeShedding(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	updates := make(chan map[string]Result, 16)
+	h := New(WithSharedScheduler(1), WithProbeShedding(), WithHealthListeners(healthListenerFunc(func(results map[string]Result) {
+		updates <- results
+	})))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "slow.check",
+			CheckFunc: func() (details interface{}, err error) {
+				close(started)
+				<-release
+				return successMsg, nil
+			},
+		},
+		ExecutionPeriod: time.Hour,
+	})
+	<-started
+
+	// the only worker is busy, so the warning check is shed, while the critical check waits for the worker
+	var executions int32
+	newCheck := func(name string) checks.Check {
+		return &checks.CustomCheck{
+			CheckName: name,
+			CheckFunc: func() (details interface{}, err error) {
+				atomic.AddInt32(&executions, 1)
+				return successMsg, nil
+			},
+		}
+	}
+	_ = h.RegisterCheck(&Config{Check: newCheck("warning.check"), ExecutionPeriod: time.Hour, Severity: Warning})
+	shed := <-updates
+	assert.True(t, shed["warning.check"].Shed)
+	assert.Equal(t, initialResultMsg, shed["warning.check"].Details, "shed executions keep the previous result")
+	assert.Equal(t, int64(1), shed["warning.check"].ContiguousFailures, "shed executions don't count as executions")
+
+	_ = h.RegisterCheck(&Config{Check: newCheck("critical.check"), ExecutionPeriod: time.Hour})
+	close(release)
+	for i := 0; i < 2; i++ {
+		<-updates
+	}
+	results, _ := h.Results()
+	assert.Equal(t, successMsg, results["critical.check"].Details, "critical checks are never shed")
+	assert.False(t, results["critical.check"].Shed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&executions))
+
+	h.DeregisterAll()
+}
+
+func TestShedTask_keepsPreviousResult(t *testing.T) {
+	h := New(WithHistoryEvaluation(4, 0.5)).(*health)
+	defer h.DeregisterAll()
+	_ = h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("warning.check", checks.FailResult(errors.New("down"))),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		Severity:        Warning,
+	})
+	failed, _ := h.Trigger("warning.check")
+
+	h.shedTask(h.checkTasks["warning.check"])
+	results, _ := h.Results()
+	shed := results["warning.check"]
+	assert.True(t, shed.Shed)
+	assert.EqualError(t, shed.Error, "down", "shed executions don't pass")
+	assert.Equal(t, failed.ContiguousFailures, shed.ContiguousFailures, "shed executions don't reset the failure streak")
+	assert.Equal(t, failed.TimeOfFirstFailure, shed.TimeOfFirstFailure)
+	assert.Equal(t, failed.history, shed.history, "shed executions don't count in the history")
+	assert.True(t, shed.Generation > failed.Generation, "shed results are a new generation")
+
+	executed, _ := h.Trigger("warning.check")
+	assert.False(t, executed.Shed, "executions clear the shed mark")
+}
+
+type healthListenerFunc func(results map[string]Result)
+
+func (f healthListenerFunc) OnResultsUpdated(results map[string]Result) {
+	f(results)
+}
+
+func TestDeregisterThenRegister(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"goroutine scheduler": nil,
+		"shared scheduler":    {WithSharedScheduler(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			listener := &checkEventsCounter{}
+			h := New(append(opts, WithCheckListeners(listener))...)
+			defer h.DeregisterAll()
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			_ = h.RegisterCheck(&Config{
+				Check: &checks.CustomCheck{
+					CheckName: "reused.check",
+					CheckFunc: func() (details interface{}, err error) {
+						close(started)
+						<-release
+						return "old", errors.New("old check failed")
+					},
+				},
+				ExecutionPeriod: time.Hour,
+			})
+
+			<-started
+			h.Deregister("reused.check")
+			results, _ := h.Results()
+			assert.Empty(t, results, "results right after deregistration")
+
+			_ = h.RegisterCheck(&Config{
+				Check: &checks.CustomCheck{
+					CheckName: "reused.check",
+					CheckFunc: func() (details interface{}, err error) { return "new", nil },
+				},
+				InitialDelay:     time.Hour,
+				ExecutionPeriod:  time.Hour,
+				InitiallyPassing: true,
+			})
+			close(release)
+
+			// await the old execution completion
+			time.Sleep(20 * time.Millisecond)
+			results, healthy := h.Results()
+			assert.True(t, healthy, "old execution completing after re-registration should be discarded")
+			assert.Equal(t, initialResultMsg, results["reused.check"].Details, "result of the new check")
+			assert.Len(t, h.Checks(), 1, "registered checks")
+			assert.Equal(t, int64(0), atomic.LoadInt64(&listener.completed), "discarded executions should not be reported")
+			assert.Equal(t, int64(1), atomic.LoadInt64(&listener.deregistered), "deregistration events")
+		})
+	}
+}
+
+func TestRegisterReplacesCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New()
+	var oldExecutions int64
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "replaced.check",
+			CheckFunc: func() (details interface{}, err error) {
+				atomic.AddInt64(&oldExecutions, 1)
+				return nil, errors.New("old check failed")
+			},
+		},
+		ExecutionPeriod: 5 * time.Millisecond,
+	})
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "replaced.check",
+			CheckFunc: func() (details interface{}, err error) { return "new", nil },
+		},
+		ExecutionPeriod: 5 * time.Millisecond,
+	})
+
+	// await a few executions
+	time.Sleep(30 * time.Millisecond)
+	executions := atomic.LoadInt64(&oldExecutions)
+	assert.True(t, executions <= 1, "the replaced check should stop executing")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "health of the replacing check")
+	assert.Equal(t, "new", results["replaced.check"].Details)
+
+	h.DeregisterAll()
+	// await stop
+	time.Sleep(10 * time.Millisecond)
+}
+
+// checkEventsCounter counts completion and deregistration events
+type checkEventsCounter struct {
+	completed    int64
+	deregistered int64
+}
+
+func (c *checkEventsCounter) OnCheckRegistered(string, Result) {}
+
+func (c *checkEventsCounter) OnCheckStarted(string) {}
+
+func (c *checkEventsCounter) OnCheckCompleted(string, Result) {
+	atomic.AddInt64(&c.completed, 1)
+}
+
+func (c *checkEventsCounter) OnCheckDeregistered(string) {
+	atomic.AddInt64(&c.deregistered, 1)
+}
+
+func TestDeregisterAllWaitsForExecutions(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"goroutine scheduler": nil,
+		"shared scheduler":    {WithSharedScheduler(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer leaktest.Check(t)()
+
+			h := New(opts...)
+			started := make(chan struct{})
+			release := make(chan struct{})
+			_ = h.RegisterCheck(&Config{
+				Check: &checks.CustomCheck{
+					CheckName: "slow.check",
+					CheckFunc: func() (details interface{}, err error) {
+						close(started)
+						<-release
+						return successMsg, nil
+					},
+				},
+				ExecutionPeriod: time.Hour,
+			})
+			registerCheck(h, passingCheckName, true, false)
+
+			<-started
+			deregistered := make(chan struct{})
+			go func() {
+				h.DeregisterAll()
+				close(deregistered)
+			}()
+
+			select {
+			case <-deregistered:
+				t.Fatal("DeregisterAll should wait for the running execution")
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			close(release)
+			select {
+			case <-deregistered:
+			case <-time.After(time.Second):
+				t.Fatal("DeregisterAll should return once the running execution completes")
+			}
+			results, _ := h.Results()
+			assert.Empty(t, results, "results after DeregisterAll")
+		})
+	}
+}
+
+func TestRegisterDeregisterChurn(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New()
+	for i := 0; i < 100; i++ {
+		registerCheck(h, passingCheckName, true, false)
+		registerCheck(h, failingCheckName, false, false)
+		h.Deregister(passingCheckName)
+	}
+	h.DeregisterAll()
+
+	results, _ := h.Results()
+	assert.Empty(t, results, "results after churn")
+}
+
+func TestDeregisterOnPass(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"goroutine scheduler": nil,
+		"shared scheduler":    {WithSharedScheduler(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer leaktest.Check(t)()
+
+			listener := &checkEventsCounter{}
+			h := New(append(opts, WithCheckListeners(listener))...)
+			defer h.DeregisterAll()
+
+			check := checks.NewScriptedCheck("setup.check",
+				checks.FailResult(errors.New("not ready")),
+				checks.PassResult("ready"),
+			)
+			_ = h.RegisterCheck(&Config{
+				Check:            check,
+				ExecutionPeriod:  20 * time.Millisecond,
+				Classification:   "setup",
+				DeregisterOnPass: true,
+			})
+
+			// await the first execution
+			time.Sleep(5 * time.Millisecond)
+			assert.False(t, h.IsHealthy(), "health while the setup check fails")
+			assert.Len(t, h.Checks(), 1, "the failing setup check should remain registered")
+
+			// await the passing execution
+			time.Sleep(40 * time.Millisecond)
+			assert.True(t, h.IsHealthy(), "health once the setup check passed")
+			assert.Empty(t, h.Checks(), "the setup check should be deregistered once it passes")
+			results, _ := h.Results()
+			assert.Empty(t, results, "results once the setup check passed")
+			assert.Equal(t, int64(1), atomic.LoadInt64(&listener.deregistered), "deregistration events")
+		})
+	}
+}
+
+func TestTTL(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New()
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "expiring.check"},
+		ExecutionPeriod: time.Hour,
+		TTL:             10 * time.Millisecond,
+	})
+	_ = h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "deregistered.check"},
+		ExecutionPeriod: time.Hour,
+		TTL:             10 * time.Millisecond,
+	})
+	h.Deregister("deregistered.check")
+	_ = h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "deregistered.check"},
+		ExecutionPeriod: time.Hour,
+	})
+	assert.Len(t, h.Checks(), 2, "checks before expiry")
+
+	// await expiry
+	time.Sleep(30 * time.Millisecond)
+	checks := h.Checks()
+	assert.Len(t, checks, 1, "checks after expiry")
+	assert.Contains(t, checks, "deregistered.check", "the TTL of a deregistered check should not affect its replacement")
+}
+
+func TestRetries(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New()
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("retried.check",
+			checks.FailResult(errors.New("transient")),
+			checks.FailResult(errors.New("transient")),
+			checks.PassResult("recovered"),
+		),
+		ExecutionPeriod: time.Minute,
+		Retries:         2,
+		RetryDelay:      5 * time.Millisecond,
+		RetryBackoff:    2,
+	})
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("exhausted.check",
+			checks.FailResult(errors.New("transient")),
+			checks.FailResult(errors.New("still failing")),
+			checks.PassResult("recovered"),
+		),
+		ExecutionPeriod: time.Minute,
+		Retries:         1,
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	results, _ := h.Results()
+
+	retried := results["retried.check"]
+	assert.True(t, retried.IsHealthy(), "the check should pass once retried")
+	assert.Equal(t, "recovered", retried.Details)
+	assert.Equal(t, int64(0), retried.ContiguousFailures, "retried failures are not recorded")
+	assert.True(t, retried.Duration >= 15*time.Millisecond, "duration should include the retry delays, got %v", retried.Duration)
+
+	exhausted := results["exhausted.check"]
+	assert.False(t, exhausted.IsHealthy(), "the check should fail once the retries are exhausted")
+	assert.EqualError(t, exhausted.Error, "still failing", "the last failure is recorded")
+}
+
+func TestRetriesStopOnDeregister(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	h := New()
+	check := checks.NewScriptedCheck("retried.check", checks.FailResult(errors.New("down")))
+	_ = h.RegisterCheck(&Config{
+		Check:           check,
+		ExecutionPeriod: time.Minute,
+		Retries:         1,
+		RetryDelay:      time.Minute,
+	})
+
+	time.Sleep(5 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		h.DeregisterAll()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deregistration should interrupt the retry delay")
+	}
+}
+
+func TestRetriesValidation(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	for name, cfg := range map[string]*Config{
+		"negative retries": {Check: &checks.CustomCheck{CheckName: "check"}, ExecutionPeriod: time.Minute, Retries: -1},
+		"negative delay":   {Check: &checks.CustomCheck{CheckName: "check"}, ExecutionPeriod: time.Minute, Retries: 1, RetryDelay: -time.Second},
+		"shrinking delay":  {Check: &checks.CustomCheck{CheckName: "check"}, ExecutionPeriod: time.Minute, Retries: 1, RetryBackoff: 0.5},
+	} {
+		assert.Error(t, h.RegisterCheck(cfg), name)
+	}
+	assert.Empty(t, h.Checks(), "misconfigured checks should not be registered")
+}
+
+func TestExecutionPeriodValidation(t *testing.T) {
+	h := New(WithSharedScheduler(1))
+	defer h.DeregisterAll()
+
+	err := h.RegisterCheck(&Config{Check: &checks.CustomCheck{CheckName: "unscheduled"}})
+	assert.EqualError(t, err, "misconfigured check unscheduled execution period 0s, must be positive")
+	err = h.RegisterCheck(&Config{Check: &checks.CustomCheck{CheckName: "negative"}, ExecutionPeriod: -time.Second})
+	assert.EqualError(t, err, "misconfigured check negative execution period -1s, must be positive")
+	assert.Empty(t, h.Checks(), "checks without a positive execution period should not be registered")
+}
+
+func TestHedgedExecution(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("hedged.check",
+			checks.SlowResult(200*time.Millisecond, checks.FailResult(errors.New("too late"))),
+			checks.PassResult("hedged"),
+		),
+		ExecutionPeriod: time.Minute,
+		HedgeDelay:      10 * time.Millisecond,
+	})
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("fast.check",
+			checks.PassResult("first"),
+			checks.PassResult("hedged"),
+		),
+		ExecutionPeriod: time.Minute,
+		HedgeDelay:      10 * time.Millisecond,
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	results, _ := h.Results()
+	assert.True(t, results["hedged.check"].IsHealthy(), "the hedged attempt should be recorded when the first one is slow")
+	assert.Equal(t, "hedged", results["hedged.check"].Details)
+	assert.Equal(t, "first", results["fast.check"].Details, "no hedged attempt when the first one completes in time")
+
+	assert.Error(t, h.RegisterCheck(&Config{Check: &checks.CustomCheck{CheckName: "negative"}, ExecutionPeriod: time.Minute, HedgeDelay: -time.Second}))
+
+	// let the abandoned attempt complete
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestRegisterChecks(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	err := h.RegisterChecks(
+		&Config{Check: &checks.CustomCheck{CheckName: "valid.check"}, ExecutionPeriod: time.Minute},
+		&Config{Check: &checks.CustomCheck{CheckName: "negative.weight"}, ExecutionPeriod: time.Minute, Weight: -1},
+		&Config{Check: &checks.CustomCheck{CheckName: "valid.check"}, ExecutionPeriod: time.Minute},
+		nil,
+	)
+	assert.Error(t, err, "invalid configurations should fail the batch")
+	multiErr, ok := err.(MultiError)
+	assert.True(t, ok, "a MultiError is expected, got %T", err)
+	assert.Len(t, multiErr, 3, "all the errors should be reported")
+	assert.Contains(t, err.Error(), "duplicate check valid.check")
+	assert.Empty(t, h.Checks(), "no check should be registered when any configuration is invalid")
+
+	err = h.RegisterChecks(
+		&Config{Check: &checks.CustomCheck{CheckName: "check.1"}, ExecutionPeriod: time.Minute},
+		&Config{Check: &checks.CustomCheck{CheckName: "check.2"}, ExecutionPeriod: time.Minute},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, h.Checks(), 2, "all the checks should be registered")
+	results, _ := h.Results()
+	assert.Len(t, results, 2, "all the checks should have results")
+}
+
+func TestSeverity(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check:            checks.NewScriptedCheck("warning.check", checks.FailResult(errors.New("degraded"))),
+		ExecutionPeriod:  time.Minute,
+		InitiallyPassing: true,
+		Severity:         Warning,
+		Capabilities:     []string{"search"},
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	results, healthy := h.Results()
+	assert.False(t, results["warning.check"].IsHealthy(), "the warning check result should be failing")
+	assert.True(t, healthy, "failing warning checks should not fail the health")
+	assert.True(t, h.IsHealthy(), "failing warning checks should not fail the health")
+	assert.Equal(t, []string{"search"}, h.ImpactedCapabilities(), "failing warning checks impact their capabilities")
+
+	_ = h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("critical.check", checks.FailResult(errors.New("down"))),
+		ExecutionPeriod: time.Minute,
+	})
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, h.IsHealthy(), "failing critical checks should fail the health")
+}
+
+func TestTimeout(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check:           checks.NewScriptedCheck("slow.check", checks.SlowResult(100*time.Millisecond, checks.PassResult("too late"))),
+		ExecutionPeriod: time.Minute,
+		Timeout:         10 * time.Millisecond,
+	})
+	time.Sleep(40 * time.Millisecond)
+
+	results, _ := h.Results()
+	assert.EqualError(t, results["slow.check"].Error, "check timed out after 10ms")
+	assert.True(t, results["slow.check"].Executed(), "timed out executions are recorded")
+
+	// let the abandoned attempt complete
+	time.Sleep(100 * time.Millisecond)
+}
+
+type contextCheck struct {
+	name    string
+	execute func(ctx context.Context) checks.ExecutionResult
+}
+
+func (c *contextCheck) Name() string {
+	return c.name
+}
+
+func (c *contextCheck) Execute() (details interface{}, err error) {
+	panic("ExecuteWithContext should be called instead")
+}
+
+func (c *contextCheck) ExecuteWithContext(ctx context.Context) checks.ExecutionResult {
+	return c.execute(ctx)
+}
+
+func TestContextCheck(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	register := func(name string, execute func(ctx context.Context) checks.ExecutionResult) {
+		_ = h.RegisterCheck(&Config{
+			Check:           &contextCheck{name: name, execute: execute},
+			ExecutionPeriod: time.Minute,
+			Timeout:         20 * time.Millisecond,
+		})
+	}
+	register("degraded.check", func(ctx context.Context) checks.ExecutionResult {
+		return checks.ExecutionResult{Details: "slow replica", Latency: 3 * time.Second, Status: checks.StatusDegraded,
+			Timings: map[string]time.Duration{checks.TimingConnect: time.Second, checks.TimingQuery: 2 * time.Second}}
+	})
+	register("failing.status.check", func(ctx context.Context) checks.ExecutionResult {
+		return checks.ExecutionResult{Status: checks.StatusFailing}
+	})
+	register("failing.degraded.check", func(ctx context.Context) checks.ExecutionResult {
+		return checks.ExecutionResult{Err: errors.New("down"), Status: checks.StatusDegraded}
+	})
+	deadline := make(chan bool, 1)
+	register("deadline.check", func(ctx context.Context) checks.ExecutionResult {
+		_, ok := ctx.Deadline()
+		deadline <- ok
+		return checks.ExecutionResult{}
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	results, _ := h.Results()
+	degraded := results["degraded.check"]
+	assert.True(t, degraded.IsHealthy(), "degraded checks pass")
+	assert.True(t, degraded.Degraded, "degraded status")
+	assert.Equal(t, 3*time.Second, degraded.Duration, "the latency measured by the check is reported")
+	assert.Equal(t, map[string]time.Duration{checks.TimingConnect: time.Second, checks.TimingQuery: 2 * time.Second},
+		degraded.Timings, "the timings reported by the check")
+
+	assert.False(t, results["failing.status.check"].IsHealthy(), "failing status fails the check")
+	assert.EqualError(t, results["failing.status.check"].Error, "check reported a failing status")
+
+	assert.False(t, results["failing.degraded.check"].IsHealthy(), "errors fail the check regardless of the status")
+	assert.False(t, results["failing.degraded.check"].Degraded, "failing checks are not degraded")
+
+	assert.True(t, <-deadline, "the context should have the timeout deadline")
+}
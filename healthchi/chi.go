@@ -0,0 +1,25 @@
+// Package healthchi exposes the health handlers as chi routes.
+package healthchi
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AppsFlyer/go-sundheit"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+// Register registers the health JSON handler (see healthhttp.HandleHealthJSON) on the given router, at the given pattern.
+func Register(r chi.Router, pattern string, h gosundheit.Health, opts ...healthhttp.HandlerOption) {
+	handler := healthhttp.HandleHealthJSON(h, opts...)
+	r.Get(pattern, handler)
+	r.Head(pattern, handler)
+}
+
+// Routes returns a router serving the health JSON handler at its root, to be mounted by the application router, e.g.:
+//
+//	r.Mount("/admin/health", healthchi.Routes(h))
+func Routes(h gosundheit.Health, opts ...healthhttp.HandlerOption) chi.Router {
+	r := chi.NewRouter()
+	Register(r, "/", h, opts...)
+	return r
+}
@@ -0,0 +1,42 @@
+package healthchi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestRegister(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+
+	r := chi.NewRouter()
+	Register(r, "/admin/health.json", h)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health.json?type=short", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"db": "PASS"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/admin/health.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRoutes(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetFailing("db", nil)
+
+	r := chi.NewRouter()
+	r.Mount("/admin/health", Routes(h))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health?type=short", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"db": "FAIL"}`, w.Body.String())
+}
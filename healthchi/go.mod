@@ -0,0 +1,11 @@
+module github.com/AppsFlyer/go-sundheit/healthchi
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/go-chi/chi/v5 v5.0.7
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
@@ -0,0 +1,217 @@
+// Package healthclient queries a health endpoint served by the http package, and maps the response to a process exit code.
+// It provides exactly what a container `HEALTHCHECK CMD` needs, either as a subcommand of the service binary,
+// or as the standalone `cmd/healthclient` binary.
+package healthclient
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/nagios"
+)
+
+const (
+	// DefaultURL is the health endpoint queried when no URL is specified
+	DefaultURL = "http://localhost:8080/admin/health.json"
+	// DefaultTimeout is the time to wait for the health endpoint response, when no timeout is specified
+	DefaultTimeout = 5 * time.Second
+
+	maxBodySize = 1 << 20
+
+	formatText   = "text"
+	formatNagios = "nagios"
+)
+
+// Exit codes returned by Run, as expected by `HEALTHCHECK`
+const (
+	ExitHealthy   = 0
+	ExitUnhealthy = 1
+)
+
+// Config configures the health endpoint query
+type Config struct {
+	// URL is the health endpoint URL, defaults to DefaultURL.
+	// When querying over a unix socket, only the path (and query) of the URL are used.
+	URL string
+	// Socket is the path of a unix domain socket the health endpoint is served on, optional.
+	Socket string
+	// Timeout is the time to wait for the response, defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Check queries the health endpoint, and returns nil when it responds with `200`.
+// Otherwise the returned error describes the failure, including the names of the failing checks when the response lists them.
+func Check(ctx context.Context, cfg Config) error {
+	status, body, err := get(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if status == http.StatusOK {
+		return nil
+	}
+	if failing := failingChecks(body); len(failing) > 0 {
+		return errors.Errorf("status %d, failing checks: %s", status, strings.Join(failing, ", "))
+	}
+	return errors.Errorf("status %d", status)
+}
+
+// jsonResult decodes a result encoded by the health endpoint, whose error is encoded as an object
+type jsonResult struct {
+	gosundheit.Result
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Results queries the health endpoint, and returns the results it responds with, regardless of the response status.
+// The errors of the failing results hold only the error messages.
+func Results(ctx context.Context, cfg Config) (map[string]gosundheit.Result, error) {
+	status, body, err := get(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]jsonResult
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the response with status %d", status)
+	}
+	results := make(map[string]gosundheit.Result, len(decoded))
+	for name, result := range decoded {
+		if result.Error != nil {
+			message := result.Error.Message
+			if message == "" {
+				message = "check failed"
+			}
+			result.Result.Error = errors.New(message)
+		}
+		results[name] = result.Result
+	}
+	return results, nil
+}
+
+// get queries the health endpoint, and returns the response status and body
+func get(ctx context.Context, cfg Config) (int, []byte, error) {
+	if cfg.URL == "" {
+		cfg.URL = DefaultURL
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	transport := &http.Transport{}
+	if cfg.Socket != "" {
+		socket := cfg.Socket
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "invalid URL %s", cfg.URL)
+	}
+	if cfg.Socket != "" {
+		req.URL.Scheme = "http"
+		req.URL.Host = "localhost"
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "health endpoint request failed")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to read the health endpoint response")
+	}
+	return resp.StatusCode, body, nil
+}
+
+// failingChecks returns the sorted names of the failing checks listed in a health endpoint response body
+func failingChecks(body []byte) []string {
+	var results map[string]struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil
+	}
+	var failing []string
+	for name, result := range results {
+		if len(result.Error) > 0 && string(result.Error) != "null" {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// Run parses the command line arguments, queries the health endpoint, writes a short outcome line to out
+// and returns the exit code: ExitHealthy or ExitUnhealthy.
+// It is meant to be called from main, e.g. when the service binary is invoked with a `healthcheck` subcommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+//		os.Exit(healthclient.Run(os.Args[2:], os.Stdout))
+//	}
+//
+// The supported arguments are `-url`, `-socket`, `-timeout` and `-format`; the URL may also be given as a positional argument.
+// With `-format nagios`, the output and the exit code follow the Nagios plugin guidelines instead (see the nagios package),
+// so the health can be surfaced through NRPE.
+func Run(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	flags.SetOutput(out)
+	cfg := Config{}
+	flags.StringVar(&cfg.URL, "url", DefaultURL, "health endpoint URL")
+	flags.StringVar(&cfg.Socket, "socket", "", "unix domain socket path the health endpoint is served on")
+	flags.DurationVar(&cfg.Timeout, "timeout", DefaultTimeout, "time to wait for the response")
+	format := flags.String("format", formatText, "output format: text or nagios")
+	if err := flags.Parse(args); err != nil {
+		return ExitUnhealthy
+	}
+	if flags.NArg() > 0 {
+		cfg.URL = flags.Arg(0)
+	}
+
+	switch *format {
+	case formatText:
+	case formatNagios:
+		return runNagios(cfg, out)
+	default:
+		_, _ = fmt.Fprintf(out, "unknown format: %s\n", *format)
+		return ExitUnhealthy
+	}
+
+	if err := Check(context.Background(), cfg); err != nil {
+		_, _ = fmt.Fprintf(out, "unhealthy: %v\n", err)
+		return ExitUnhealthy
+	}
+	_, _ = fmt.Fprintln(out, "healthy")
+	return ExitHealthy
+}
+
+func runNagios(cfg Config, out io.Writer) int {
+	results, err := Results(context.Background(), cfg)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "HEALTH %s - %v\n", nagios.StatusUnknown, err)
+		return int(nagios.StatusUnknown)
+	}
+	output, status := nagios.Encode(results)
+	_, _ = fmt.Fprint(out, output)
+	return int(status)
+}
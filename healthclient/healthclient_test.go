@@ -0,0 +1,135 @@
+package healthclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+func TestCheck(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(h))
+	defer server.Close()
+
+	assert.NoError(t, Check(context.Background(), Config{URL: server.URL}))
+
+	h.SetFailing("db", errors.New("down"))
+	h.SetFailing("cache", errors.New("down"))
+	err := Check(context.Background(), Config{URL: server.URL})
+	assert.EqualError(t, err, "status 503, failing checks: cache, db")
+}
+
+func TestCheckNoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := Check(context.Background(), Config{URL: server.URL})
+	assert.EqualError(t, err, "status 500")
+}
+
+func TestCheckUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthclient")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "health.sock")
+
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	mux := http.NewServeMux()
+	mux.Handle("/health", healthhttp.HandleHealthJSON(h))
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	assert.NoError(t, Check(context.Background(), Config{URL: "http://unused/health", Socket: socket}))
+}
+
+func TestRun(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(h))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	assert.Equal(t, ExitHealthy, Run([]string{"-url", server.URL}, out))
+	assert.Equal(t, "healthy\n", out.String())
+
+	h.SetFailing("db", errors.New("down"))
+	out.Reset()
+	assert.Equal(t, ExitUnhealthy, Run([]string{"-timeout", "1s", server.URL}, out), "positional url")
+	assert.Equal(t, "unhealthy: status 503, failing checks: db\n", out.String())
+
+	out.Reset()
+	assert.Equal(t, ExitUnhealthy, Run([]string{"-timeout", "invalid"}, out), "invalid arguments")
+}
+
+func TestResults(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	_ = h.RegisterChecks(
+		&gosundheit.Config{Check: &checks.CustomCheck{CheckName: "cache"}, InitiallyPassing: true, InitialDelay: time.Minute, ExecutionPeriod: time.Minute},
+		&gosundheit.Config{Check: &checks.CustomCheck{CheckName: "db"}, InitialDelay: time.Minute, ExecutionPeriod: time.Minute},
+	)
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(h))
+	defer server.Close()
+
+	results, err := Results(context.Background(), Config{URL: server.URL})
+	assert.NoError(t, err)
+	assert.True(t, results["cache"].IsHealthy())
+	assert.EqualError(t, results["db"].Error, "didn't run yet")
+
+	_, err = Results(context.Background(), Config{URL: server.URL + "/missing"})
+	assert.NoError(t, err, "the handler responds to any path")
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer plain.Close()
+	_, err = Results(context.Background(), Config{URL: plain.URL})
+	assert.Error(t, err, "non JSON responses should fail")
+}
+
+func TestRunNagios(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(h))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	assert.Equal(t, 0, Run([]string{"-format", "nagios", server.URL}, out))
+	assert.Contains(t, out.String(), "HEALTH OK - 1 checks passing")
+
+	h.SetFailing("db", errors.New("down"))
+	out.Reset()
+	assert.Equal(t, 2, Run([]string{"-format", "nagios", server.URL}, out), "critical exit code")
+	assert.Contains(t, out.String(), "HEALTH CRITICAL - 1 of 1 checks failing: db")
+
+	out.Reset()
+	assert.Equal(t, 3, Run([]string{"-format", "nagios", "-socket", "/missing.sock", server.URL}, out), "unknown exit code")
+	assert.Contains(t, out.String(), "HEALTH UNKNOWN - ")
+
+	out.Reset()
+	assert.Equal(t, ExitUnhealthy, Run([]string{"-format", "xml", server.URL}, out), "unknown format")
+}
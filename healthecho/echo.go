@@ -0,0 +1,27 @@
+// Package healthecho exposes the health handlers as echo handlers.
+package healthecho
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/AppsFlyer/go-sundheit"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+// Router is the routing API shared by *echo.Echo and *echo.Group.
+type Router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// Handler returns the health JSON handler (see healthhttp.HandleHealthJSON) as an echo handler.
+func Handler(h gosundheit.Health, opts ...healthhttp.HandlerOption) echo.HandlerFunc {
+	return echo.WrapHandler(healthhttp.HandleHealthJSON(h, opts...))
+}
+
+// Register registers the health JSON handler on the given router, at the given path.
+func Register(r Router, path string, h gosundheit.Health, opts ...healthhttp.HandlerOption) {
+	handler := Handler(h, opts...)
+	r.GET(path, handler)
+	r.HEAD(path, handler)
+}
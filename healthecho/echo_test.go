@@ -0,0 +1,30 @@
+package healthecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestRegister(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+
+	e := echo.New()
+	Register(e.Group("/admin"), "/health.json", h)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health.json?type=short", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"db": "PASS"}`, w.Body.String())
+
+	h.SetFailing("db", nil)
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/admin/health.json", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,11 @@
+module github.com/AppsFlyer/go-sundheit/healthecho
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/labstack/echo/v4 v4.6.3
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
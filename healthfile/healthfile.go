@@ -0,0 +1,165 @@
+// Package healthfile shares the health of a process with other processes on the same node (e.g. sidecars and node agents)
+// through a file, so they can consume it without HTTP.
+// The Writer atomically replaces the file whenever the results are updated, so readers never observe a partially written file.
+package healthfile
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// Snapshot is the content of the health file.
+type Snapshot struct {
+	// Healthy is the health of the instance when the snapshot was written.
+	Healthy bool `json:"healthy"`
+	// Generation is the results generation, see gosundheit.Health.Generation().
+	Generation uint64 `json:"generation"`
+	// Timestamp is the time the snapshot was written.
+	Timestamp time.Time `json:"timestamp"`
+	// Results are the check results, keyed by check name.
+	Results map[string]CheckResult `json:"results"`
+}
+
+// CheckResult is the result of a single check, as recorded in the health file.
+type CheckResult struct {
+	Details   interface{} `json:"message,omitempty"`
+	Error     *CheckError `json:"error,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	// ContiguousFailures is the number of failures that occurred in a row.
+	ContiguousFailures int64 `json:"contiguousFailures"`
+}
+
+// CheckError is the error of a failed check.
+type CheckError struct {
+	Message string `json:"message"`
+}
+
+// IsHealthy returns true iff the check passed.
+func (r CheckResult) IsHealthy() bool {
+	return r.Error == nil
+}
+
+// Stale returns true when the snapshot was written more than maxAge ago, e.g. when the writing process is gone.
+// Snapshots are written whenever the results are updated, i.e. on every check execution.
+func (s *Snapshot) Stale(maxAge time.Duration) bool {
+	return time.Since(s.Timestamp) > maxAge
+}
+
+// Writer writes the health of a Health instance to a file, whenever its results are updated.
+// Writer is a gosundheit.HealthListener, and must be registered with the Health instance it writes, e.g.:
+//
+//	writer := healthfile.NewWriter("/run/myservice/health.json")
+//	h := gosundheit.New(gosundheit.WithHealthListeners(writer))
+//	writer.Attach(h)
+type Writer struct {
+	path string
+
+	lock       sync.Mutex
+	h          gosundheit.Health
+	generation uint64
+	err        error
+}
+
+// NewWriter returns a new Writer, writing to the given path.
+// The file is replaced by renaming a temporary file in the same directory, which must be writable.
+func NewWriter(path string) *Writer {
+	return &Writer{path: path}
+}
+
+// Attach sets the Health instance whose health is written, and writes its current health.
+// Results updates are ignored until the writer is attached.
+func (w *Writer) Attach(h gosundheit.Health) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.h = h
+	return w.write()
+}
+
+// OnResultsUpdated writes the current health to the file.
+// Write errors don't affect the health; the last error is returned by Err.
+func (w *Writer) OnResultsUpdated(map[string]gosundheit.Result) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.h == nil || w.h.Generation() == w.generation {
+		return
+	}
+	w.err = w.write()
+}
+
+// Err returns the error of the last write, if it failed.
+func (w *Writer) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.err
+}
+
+// write atomically replaces the file with the current health. Callers must hold the lock.
+func (w *Writer) write() error {
+	generation := w.h.Generation()
+	results := w.h.ResultsJSON()
+	if results == nil {
+		return errors.New("failed to encode the results")
+	}
+
+	data, err := json.Marshal(struct {
+		Healthy    bool            `json:"healthy"`
+		Generation uint64          `json:"generation"`
+		Timestamp  time.Time       `json:"timestamp"`
+		Results    json.RawMessage `json:"results"`
+	}{
+		Healthy:    w.h.IsHealthy(),
+		Generation: generation,
+		Timestamp:  time.Now(),
+		Results:    results,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the health")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(w.path), filepath.Base(w.path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary health file")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write the health file")
+	}
+	// temporary files are created private, while the health is meant to be read by other processes
+	if err = os.Chmod(tmp.Name(), 0644); err != nil { // nolint:gosec
+		return errors.Wrap(err, "failed to set the health file mode")
+	}
+	if err = os.Rename(tmp.Name(), w.path); err != nil {
+		return errors.Wrap(err, "failed to replace the health file")
+	}
+	w.generation = generation
+	return nil
+}
+
+// Read reads the health snapshot from the given file.
+func Read(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the health file")
+	}
+
+	var snapshot Snapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the health file")
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,103 @@
+package healthfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestWriterAndRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthfile")
+	if err != nil {
+		t.Fatal("Failed to create temp dir: ", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "health.json")
+
+	writer := NewWriter(path)
+	h := gosundheit.New(gosundheit.WithHealthListeners(writer))
+	defer h.DeregisterAll()
+
+	writer.OnResultsUpdated(nil)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "nothing is written before attaching")
+
+	assert.NoError(t, writer.Attach(h))
+	snapshot, err := Read(path)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Healthy)
+	assert.Empty(t, snapshot.Results)
+	assert.False(t, snapshot.Stale(time.Minute))
+
+	err = h.RegisterCheck(&gosundheit.Config{
+		Check: &checks.CustomCheck{
+			CheckName: "check1",
+			CheckFunc: func() (details interface{}, err error) { return "pass", nil },
+		},
+		ExecutionPeriod: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.WaitForHealthy(ctx))
+	writer.OnResultsUpdated(nil)
+	assert.NoError(t, writer.Err())
+
+	snapshot, err = Read(path)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Healthy)
+	assert.True(t, snapshot.Generation > 0)
+	if assert.Contains(t, snapshot.Results, "check1") {
+		assert.True(t, snapshot.Results["check1"].IsHealthy())
+		assert.Equal(t, "pass", snapshot.Results["check1"].Details)
+	}
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1, "temporary files are cleaned up")
+}
+
+func TestWriterError(t *testing.T) {
+	writer := NewWriter(filepath.Join("no", "such", "dir", "health.json"))
+	assert.Error(t, writer.Attach(gosundheit.New()))
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthfile")
+	if err != nil {
+		t.Fatal("Failed to create temp dir: ", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "health.json")
+
+	_, err = Read(path)
+	assert.Error(t, err, "missing file")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = Read(path)
+	assert.Error(t, err, "malformed file")
+
+	content := `{"healthy":false,"generation":3,"timestamp":"2020-01-01T00:00:00Z",` +
+		`"results":{"db":{"error":{"message":"connection refused"},"timestamp":"2020-01-01T00:00:00Z","contiguousFailures":2}}}`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	snapshot, err := Read(path)
+	assert.NoError(t, err)
+	assert.False(t, snapshot.Healthy)
+	assert.Equal(t, uint64(3), snapshot.Generation)
+	assert.True(t, snapshot.Stale(time.Minute))
+	assert.False(t, snapshot.Results["db"].IsHealthy())
+	assert.Equal(t, "connection refused", snapshot.Results["db"].Error.Message)
+	assert.Equal(t, int64(2), snapshot.Results["db"].ContiguousFailures)
+}
@@ -0,0 +1,21 @@
+// Package healthgin exposes the health handlers as gin handlers.
+package healthgin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/AppsFlyer/go-sundheit"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+// Handler returns the health JSON handler (see healthhttp.HandleHealthJSON) as a gin handler.
+func Handler(h gosundheit.Health, opts ...healthhttp.HandlerOption) gin.HandlerFunc {
+	return gin.WrapF(healthhttp.HandleHealthJSON(h, opts...))
+}
+
+// Register registers the health JSON handler on the given routes (e.g. a *gin.Engine or a *gin.RouterGroup), at the given path.
+func Register(r gin.IRoutes, path string, h gosundheit.Health, opts ...healthhttp.HandlerOption) {
+	handler := Handler(h, opts...)
+	r.GET(path, handler)
+	r.HEAD(path, handler)
+}
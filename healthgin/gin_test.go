@@ -0,0 +1,31 @@
+package healthgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+
+	r := gin.New()
+	Register(r.Group("/admin"), "/health.json", h)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health.json?type=short", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"db": "PASS"}`, w.Body.String())
+
+	h.SetFailing("db", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/admin/health.json", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,11 @@
+module github.com/AppsFlyer/go-sundheit/healthgin
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/gin-gonic/gin v1.7.7
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
@@ -0,0 +1,55 @@
+package gosundheit
+
+// maxHistoryWindow is the maximal number of recent results a check is evaluated by, see WithHistoryEvaluation.
+const maxHistoryWindow = 64
+
+// resultHistory tracks the outcomes of the recent executions of a check, as a bit per execution.
+type resultHistory struct {
+	// passed holds a set bit for each passing execution, the last execution being the least significant bit
+	passed uint64
+	// size is the number of executions tracked, up to the window size
+	size uint
+	// healthy is true when enough of the tracked executions passed
+	healthy bool
+}
+
+// historyEvaluation evaluates the health of each check by its recent results, instead of its last result only.
+type historyEvaluation struct {
+	window       uint
+	minPassRatio float64
+}
+
+// record adds the outcome of the given result to the previous history, and evaluates it.
+// Returns the ratio of passing executions in the updated history.
+func (e *historyEvaluation) record(prev resultHistory, passed bool) (resultHistory, float64) {
+	history := resultHistory{passed: prev.passed << 1, size: prev.size + 1}
+	if passed {
+		history.passed |= 1
+	}
+	return e.evaluate(history)
+}
+
+// restore evaluates the given history outcomes (e.g. imported from a Snapshot), trimmed to the window size.
+// Returns the ratio of passing executions in the restored history.
+func (e *historyEvaluation) restore(passed uint64, size uint) (resultHistory, float64) {
+	return e.evaluate(resultHistory{passed: passed, size: size})
+}
+
+// evaluate trims the history to the window size, and evaluates it.
+// Returns the ratio of passing executions in the trimmed history.
+func (e *historyEvaluation) evaluate(history resultHistory) (resultHistory, float64) {
+	if history.size > e.window {
+		history.size = e.window
+	}
+	if history.size < maxHistoryWindow {
+		history.passed &= 1<<history.size - 1
+	}
+
+	passes := 0
+	for bits := history.passed; bits != 0; bits &= bits - 1 {
+		passes++
+	}
+	ratio := float64(passes) / float64(history.size)
+	history.healthy = ratio >= e.minPassRatio
+	return history, ratio
+}
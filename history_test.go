@@ -0,0 +1,104 @@
+package gosundheit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestHistoryEvaluation_record(t *testing.T) {
+	evaluation := &historyEvaluation{window: 4, minPassRatio: 0.75}
+	var history resultHistory
+	var ratio float64
+
+	history, ratio = evaluation.record(history, false)
+	assert.Equal(t, float64(0), ratio)
+	assert.False(t, history.healthy)
+
+	for i := 0; i < 3; i++ {
+		history, ratio = evaluation.record(history, true)
+	}
+	assert.Equal(t, 0.75, ratio, "3 out of 4 executions passed")
+	assert.True(t, history.healthy)
+
+	history, ratio = evaluation.record(history, true)
+	assert.Equal(t, float64(1), ratio, "the first failure is out of the window")
+	assert.Equal(t, uint(4), history.size)
+
+	history, _ = evaluation.record(history, false)
+	history, ratio = evaluation.record(history, false)
+	assert.Equal(t, 0.5, ratio)
+	assert.False(t, history.healthy)
+
+	full := &historyEvaluation{window: maxHistoryWindow, minPassRatio: 1}
+	history = resultHistory{}
+	for i := 0; i < 2*maxHistoryWindow; i++ {
+		history, ratio = full.record(history, true)
+	}
+	assert.Equal(t, float64(1), ratio, "full window")
+	assert.Equal(t, uint(maxHistoryWindow), history.size)
+}
+
+func TestWithHistoryEvaluation(t *testing.T) {
+	h := New(WithHistoryEvaluation(5, 0.8)).(*health)
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "flaky"},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+	results, healthy := h.Results()
+	assert.Nil(t, results["flaky"].RecentPassRatio, "the initial result doesn't count in the history")
+	assert.False(t, healthy, "initially failing")
+
+	task := h.checkTasks["flaky"]
+	execute := func(passing bool) (ratio float64, healthy bool) {
+		outcome := checks.ExecutionResult{Details: successMsg}
+		if !passing {
+			outcome.Err = errors.New(failedMsg)
+		}
+		result, ok := h.updateResult(task, outcome, 0, time.Now())
+		assert.True(t, ok)
+		return *result.RecentPassRatio, h.IsHealthy()
+	}
+
+	for i := 0; i < 4; i++ {
+		ratio, healthy := execute(true)
+		assert.Equal(t, float64(1), ratio)
+		assert.True(t, healthy)
+	}
+
+	ratio, healthy := execute(false)
+	assert.Equal(t, 0.8, ratio)
+	assert.True(t, healthy, "a single failure is smoothed out")
+	results, _ = h.Results()
+	assert.False(t, results["flaky"].IsHealthy(), "the failure is still reported")
+
+	ratio, healthy = execute(false)
+	assert.Equal(t, 0.6, ratio)
+	assert.False(t, healthy)
+
+	ratio, healthy = execute(true)
+	assert.Equal(t, 0.6, ratio)
+	assert.False(t, healthy, "a recovered check affects the health, until enough executions pass")
+
+	execute(true)
+	execute(true)
+	ratio, healthy = execute(true)
+	assert.Equal(t, 0.8, ratio)
+	assert.True(t, healthy)
+}
+
+func TestWithHistoryEvaluation_disabled(t *testing.T) {
+	h := New(WithHistoryEvaluation(0, 0.8)).(*health)
+	assert.Nil(t, h.historyEvaluation, "invalid window disables the history evaluation")
+	h = New(WithHistoryEvaluation(10, 1.5)).(*health)
+	assert.Nil(t, h.historyEvaluation, "invalid ratio disables the history evaluation")
+	h = New(WithHistoryEvaluation(100, 0.5)).(*health)
+	assert.Equal(t, uint(maxHistoryWindow), h.historyEvaluation.window, "window is capped")
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// checkConfig is the JSON representation of the effective configuration of a check
+type checkConfig struct {
+	ExecutionPeriod  string `json:"executionPeriod"`
+	SchedulingMode   string `json:"schedulingMode"`
+	InitialDelay     string `json:"initialDelay"`
+	InitiallyPassing bool   `json:"initiallyPassing"`
+	Classification   string `json:"classification,omitempty"`
+	LeaderPolicy     string `json:"leaderPolicy"`
+	Severity         string `json:"severity"`
+	Timeout          string `json:"timeout,omitempty"`
+	Retries          int    `json:"retries,omitempty"`
+}
+
+type resultWithConfig struct {
+	gosundheit.Result
+	Config *checkConfig `json:"config,omitempty"`
+}
+
+// resultsWithConfig returns the given results, each with the configuration of its check.
+func resultsWithConfig(h gosundheit.Health, results map[string]gosundheit.Result) map[string]resultWithConfig {
+	configs := h.Checks()
+	echoed := make(map[string]resultWithConfig, len(results))
+	for name, result := range results {
+		echo := resultWithConfig{Result: result}
+		if cfg, ok := configs[name]; ok {
+			echo.Config = &checkConfig{
+				ExecutionPeriod:  cfg.ExecutionPeriod.String(),
+				SchedulingMode:   cfg.SchedulingMode.String(),
+				InitialDelay:     cfg.InitialDelay.String(),
+				InitiallyPassing: cfg.InitiallyPassing,
+				Classification:   cfg.Classification,
+				LeaderPolicy:     cfg.LeaderPolicy.String(),
+				Severity:         cfg.Severity.String(),
+				Retries:          cfg.Retries,
+			}
+			if cfg.Timeout > 0 {
+				echo.Config.Timeout = cfg.Timeout.String()
+			}
+		}
+		echoed[name] = echo
+	}
+	return echoed
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestHandleHealthJSON_configEcho(t *testing.T) {
+	h := gosundheit.New()
+	check := createCheck("check1", true, 10*time.Millisecond)
+	check.Classification = "readiness"
+	check.SchedulingMode = gosundheit.FixedDelay
+	err := h.RegisterCheck(check)
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	defer h.DeregisterAll()
+
+	handler := HandleHealthJSON(h, WithConfigEcho())
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meh", nil))
+	resp := w.Result()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "status before first run")
+
+	var respMsg map[string]struct {
+		checkResult
+		Config checkConfig `json:"config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respMsg); err != nil {
+		t.Fatal("Failed to decode response: ", err)
+	}
+	assert.Equal(t, "didn't run yet", respMsg["check1"].Message, "result next to the config")
+	assert.Equal(t, checkConfig{
+		ExecutionPeriod:  "10ms",
+		SchedulingMode:   "FixedDelay",
+		InitialDelay:     "10ms",
+		InitiallyPassing: false,
+		Classification:   "readiness",
+		LeaderPolicy:     "AnyInstance",
+		Severity:         "Critical",
+	}, respMsg["check1"].Config)
+
+	plainETag := execReq(h, true).Header.Get("ETag")
+	assert.NotEqual(t, plainETag, resp.Header.Get("ETag"), "ETag should differ from the plain long format")
+}
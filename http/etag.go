@@ -0,0 +1,86 @@
+package http
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// maxConsistentReads bounds the attempts to read the results of a single snapshot, while the results keep changing
+const maxConsistentReads = 3
+
+// resultsView holds the results, and their cached encoding, read from a single results snapshot when consistent.
+type resultsView struct {
+	generation uint64
+	consistent bool
+	results    map[string]gosundheit.Result
+	healthy    bool
+	// encoded is the cached encoding of all the results, when requested
+	encoded []byte
+}
+
+// readResults reads the results, and their cached encoding when withEncoding is set. The view is consistent when the
+// results generation didn't change while reading, i.e. when the results (and encoding) are of the snapshot of the generation.
+func readResults(h gosundheit.Health, withEncoding bool) resultsView {
+	var view resultsView
+	for i := 0; i < maxConsistentReads && !view.consistent; i++ {
+		view.generation = h.Generation()
+		view.results, view.healthy = h.Results()
+		if withEncoding {
+			view.encoded = h.ResultsJSON()
+		}
+		view.consistent = h.Generation() == view.generation
+	}
+	return view
+}
+
+// resultsETag returns the entity tag of the results view, served with the given status,
+// in the given report type, filtered by the given filter (which may be nil).
+// The tag is derived from the results generation, so it changes exactly when the results snapshot changes,
+// and from the health, and the checks affecting it (which change along with the leadership of the instance).
+// Compressed representations, and differently filtered or paginated views, are tagged separately.
+// Returns an empty string when the view isn't consistent.
+func resultsETag(view resultsView, status int, reportType string, filter *resultsFilter, compressed bool) string {
+	if !view.consistent {
+		return ""
+	}
+
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%d;%d;%t;%x", view.generation, status, view.healthy, affectingHealth(view.results))
+	if filter != nil {
+		_, _ = hash.Write([]byte(filter.key()))
+	}
+	if reportType == "" {
+		reportType = "long"
+	}
+	if compressed {
+		return fmt.Sprintf(`"%s-%x-gzip"`, reportType, hash.Sum64())
+	}
+	return fmt.Sprintf(`"%s-%x"`, reportType, hash.Sum64())
+}
+
+// affectingHealth returns an order independent digest of the names of the results affecting the health
+func affectingHealth(results map[string]gosundheit.Result) uint64 {
+	var digest uint64
+	for name, result := range results {
+		if result.AffectsHealth() {
+			hash := fnv.New64a()
+			_, _ = hash.Write([]byte(name))
+			digest += hash.Sum64()
+		}
+	}
+	return digest
+}
+
+// etagMatches returns true iff the If-None-Match header value matches the given entity tag.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
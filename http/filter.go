@@ -0,0 +1,161 @@
+package http
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	// QueryChecks is the request parameter holding the comma separated names of the checks to respond with, e.g. `?checks=db,cache`.
+	QueryChecks = "checks"
+	// QueryPage is the request parameter holding the 1 based page of the results to respond with, ordered by check name.
+	QueryPage = "page"
+	// QueryPageSize is the request parameter holding the number of results per page; defaults to 100.
+	QueryPageSize = "pageSize"
+	// HeaderTotalCount is the response header holding the number of results matching the filter, across all the pages.
+	HeaderTotalCount = "X-Total-Count"
+
+	defaultPageSize = 100
+)
+
+// resultsFilter selects the results of the requested checks, and page.
+type resultsFilter struct {
+	checks   map[string]bool
+	page     int
+	pageSize int
+}
+
+// parseResultsFilter parses the filter request parameters. Returns nil when the request doesn't filter the results.
+func parseResultsFilter(query url.Values) (*resultsFilter, error) {
+	checksParam, pageParam, pageSizeParam := query.Get(QueryChecks), query.Get(QueryPage), query.Get(QueryPageSize)
+	if checksParam == "" && pageParam == "" && pageSizeParam == "" {
+		return nil, nil
+	}
+
+	filter := &resultsFilter{}
+	if checksParam != "" {
+		filter.checks = make(map[string]bool)
+		for _, name := range strings.Split(checksParam, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter.checks[name] = true
+			}
+		}
+	}
+	if pageParam != "" || pageSizeParam != "" {
+		var err error
+		if filter.page, err = positiveParam(pageParam, 1); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", QueryPage)
+		}
+		if filter.pageSize, err = positiveParam(pageSizeParam, defaultPageSize); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", QueryPageSize)
+		}
+	}
+	return filter, nil
+}
+
+func positiveParam(param string, defaultValue int) (int, error) {
+	if param == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, err
+	}
+	if value < 1 {
+		return 0, errors.Errorf("%d must be positive", value)
+	}
+	return value, nil
+}
+
+// key returns the normalized filter, identifying the view it selects
+func (f *resultsFilter) key() string {
+	key := fmt.Sprintf("page=%d;pageSize=%d", f.page, f.pageSize)
+	if f.checks != nil {
+		names := make([]string, 0, len(f.checks))
+		for name := range f.checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		key += ";checks=" + strings.Join(names, ",")
+	}
+	return key
+}
+
+// apply returns the results selected by the filter, and the number of results matching the filter across all the pages.
+func (f *resultsFilter) apply(results map[string]gosundheit.Result) (map[string]gosundheit.Result, int) {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		if f.checks == nil || f.checks[name] {
+			names = append(names, name)
+		}
+	}
+	total := len(names)
+	if f.page > 0 {
+		sort.Strings(names)
+		start := (f.page - 1) * f.pageSize
+		if start > len(names) {
+			start = len(names)
+		}
+		end := start + f.pageSize
+		if end > len(names) {
+			end = len(names)
+		}
+		names = names[start:end]
+	}
+
+	filtered := make(map[string]gosundheit.Result, len(names))
+	for _, name := range names {
+		filtered[name] = results[name]
+	}
+	return filtered, total
+}
+
+// setPaginationHeaders sets the total count header, and a link to the next page when there are more results.
+func (f *resultsFilter) setPaginationHeaders(w http.ResponseWriter, request *http.Request, total int) {
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(total))
+	if f.page == 0 || f.page*f.pageSize >= total {
+		return
+	}
+
+	next := *request.URL
+	query := next.Query()
+	query.Set(QueryPage, strconv.Itoa(f.page+1))
+	next.RawQuery = query.Encode()
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
+
+// acceptsGzip returns true iff the request accepts gzip encoded responses.
+func acceptsGzip(request *http.Request) bool {
+	for _, encoding := range strings.Split(request.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(encoding, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				if value, err := strconv.ParseFloat(q[2:], 64); err == nil && value == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter returns a writer that compresses the response body, and a function that completes the compression.
+// Callers must set the response headers, including the encoding headers, before writing the body.
+func gzipWriter(w http.ResponseWriter) (io.Writer, func()) {
+	compressor := gzip.NewWriter(w)
+	return compressor, func() { _ = compressor.Close() }
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestParseResultsFilter(t *testing.T) {
+	filter, err := parseResultsFilter(url.Values{"type": {"short"}})
+	assert.NoError(t, err)
+	assert.Nil(t, filter, "no filter parameters")
+
+	filter, err = parseResultsFilter(url.Values{"checks": {"db, cache,,"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &resultsFilter{checks: map[string]bool{"db": true, "cache": true}}, filter)
+
+	filter, err = parseResultsFilter(url.Values{"page": {"2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &resultsFilter{page: 2, pageSize: 100}, filter)
+
+	for _, query := range []url.Values{{"page": {"0"}}, {"page": {"first"}}, {"pageSize": {"-1"}}} {
+		_, err = parseResultsFilter(query)
+		assert.Error(t, err, query.Encode())
+	}
+}
+
+func TestHandleHealthJSON_filter(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	for i := 1; i <= 5; i++ {
+		h.SetPassing(fmt.Sprintf("check.%d", i))
+	}
+	h.SetFailing("check.6", nil)
+	handler := HandleHealthJSON(h)
+
+	get := func(target string) (*httptest.ResponseRecorder, map[string]string) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		var results map[string]string
+		_ = json.Unmarshal(recorder.Body.Bytes(), &results)
+		return recorder, results
+	}
+
+	recorder, results := get("/health?type=short&checks=check.1,check.3,missing")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "the status reflects all the checks")
+	assert.Equal(t, map[string]string{"check.1": "PASS", "check.3": "PASS"}, results)
+	assert.Equal(t, "2", recorder.Header().Get(HeaderTotalCount))
+	assert.Empty(t, recorder.Header().Get("Link"))
+
+	recorder, results = get("/health?type=short&pageSize=4")
+	assert.Equal(t, map[string]string{"check.1": "PASS", "check.2": "PASS", "check.3": "PASS", "check.4": "PASS"}, results)
+	assert.Equal(t, "6", recorder.Header().Get(HeaderTotalCount))
+	assert.Equal(t, `</health?page=2&pageSize=4&type=short>; rel="next"`, recorder.Header().Get("Link"))
+
+	recorder, results = get("/health?type=short&pageSize=4&page=2")
+	assert.Equal(t, map[string]string{"check.5": "PASS", "check.6": "FAIL"}, results)
+	assert.Empty(t, recorder.Header().Get("Link"), "last page")
+
+	_, results = get("/health?type=short&pageSize=4&page=3")
+	assert.Empty(t, results, "out of range page")
+
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health?checks=check.6", nil))
+	var long map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &long))
+	assert.Len(t, long, 1, "long results are filtered as well")
+	assert.Contains(t, long, "check.6")
+
+	recorder, _ = get("/health?page=none")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestHandleHealthJSON_filterETag(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	h.SetPassing("cache")
+	handler := HandleHealthJSON(h)
+
+	serve := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		return recorder
+	}
+
+	etag := serve("/health?checks=db", "").Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, serve("/health?checks=db", etag).Code, "same view")
+	assert.Equal(t, http.StatusNotModified, serve("/health?checks=+db,", etag).Code, "same normalized view")
+
+	recorder := serve("/health?checks=cache", etag)
+	assert.Equal(t, http.StatusOK, recorder.Code, "other filters are tagged separately")
+	assert.Contains(t, recorder.Body.String(), "cache")
+	assert.Equal(t, http.StatusOK, serve("/health", etag).Code, "unfiltered views are tagged separately")
+
+	etag = serve("/health?pageSize=1&page=1", "").Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, serve("/health?pageSize=1&page=2", etag).Code, "other pages are tagged separately")
+	assert.Equal(t, http.StatusOK, serve("/health?pageSize=2&page=1", etag).Code, "other page sizes are tagged separately")
+}
+
+func TestHandleHealthJSON_gzip(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	handler := HandleHealthJSON(h)
+
+	request := httptest.NewRequest(http.MethodGet, "/health?type=short", nil)
+	request.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+	compressedETag := recorder.Header().Get("ETag")
+	reader, err := gzip.NewReader(recorder.Body)
+	if assert.NoError(t, err) {
+		body, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n\t\"db\": \"PASS\"\n}\n", string(body))
+	}
+
+	request.Header.Set("Accept-Encoding", "gzip;q=0")
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"), "gzip is refused")
+	assert.Equal(t, "{\n\t\"db\": \"PASS\"\n}\n", recorder.Body.String())
+	assert.NotEqual(t, compressedETag, recorder.Header().Get("ETag"), "representations are tagged separately")
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// groupSeparator separates the group names from the check name, e.g. "databases.primary"
+const groupSeparator = "."
+
+// resultsGroup is the JSON representation of a group of results, nested by the check names
+type resultsGroup struct {
+	Healthy bool                         `json:"healthy"`
+	Checks  map[string]gosundheit.Result `json:"checks,omitempty"`
+	Groups  map[string]*resultsGroup     `json:"groups,omitempty"`
+}
+
+// groupResults nests the given results by the groups in their check names, e.g. the result of "databases.primary"
+// is nested as the "primary" check of the "databases" group.
+// A group is healthy when all of its checks, including the checks of its nested groups, pass; the root group reports the given health.
+func groupResults(results map[string]gosundheit.Result, healthy bool) *resultsGroup {
+	root := &resultsGroup{Healthy: true}
+	for name, result := range results {
+		path := strings.Split(name, groupSeparator)
+		group := root
+		for _, groupName := range path[:len(path)-1] {
+			if group.Groups == nil {
+				group.Groups = make(map[string]*resultsGroup)
+			}
+			nested, ok := group.Groups[groupName]
+			if !ok {
+				nested = &resultsGroup{Healthy: true}
+				group.Groups[groupName] = nested
+			}
+			group.Healthy = group.Healthy && !result.AffectsHealth()
+			group = nested
+		}
+		if group.Checks == nil {
+			group.Checks = make(map[string]gosundheit.Result)
+		}
+		group.Checks[path[len(path)-1]] = result
+		group.Healthy = group.Healthy && !result.AffectsHealth()
+	}
+	root.Healthy = healthy
+	return root
+}
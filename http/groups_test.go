@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestGroupResults(t *testing.T) {
+	failure := errors.New("connection refused")
+	results := map[string]gosundheit.Result{
+		"cache":                    {Details: "ok"},
+		"databases.primary":        {Details: "ok"},
+		"databases.replica":        {Error: failure},
+		"databases.archive.legacy": {Details: "ok"},
+	}
+
+	grouped := groupResults(results, false)
+	assert.False(t, grouped.Healthy)
+	assert.Equal(t, map[string]gosundheit.Result{"cache": {Details: "ok"}}, grouped.Checks)
+
+	databases := grouped.Groups["databases"]
+	if assert.NotNil(t, databases) {
+		assert.False(t, databases.Healthy, "a nested check is failing")
+		assert.Equal(t, map[string]gosundheit.Result{
+			"primary": {Details: "ok"},
+			"replica": {Error: failure},
+		}, databases.Checks)
+		assert.Equal(t, &resultsGroup{
+			Healthy: true,
+			Checks:  map[string]gosundheit.Result{"legacy": {Details: "ok"}},
+		}, databases.Groups["archive"])
+	}
+
+	assert.True(t, groupResults(map[string]gosundheit.Result{"databases.primary": {}}, true).Groups["databases"].Healthy)
+}
+
+func TestHandleHealthJSON_grouped(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("databases.primary")
+	h.SetFailing("databases.replica", errors.New("lagging"))
+	h.SetPassing("cache")
+
+	handler := HandleHealthJSON(h, WithGroupedResults())
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meh", nil))
+	resp := w.Result()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var respMsg struct {
+		Healthy bool                   `json:"healthy"`
+		Checks  map[string]checkResult `json:"checks"`
+		Groups  map[string]struct {
+			Healthy bool                   `json:"healthy"`
+			Checks  map[string]checkResult `json:"checks"`
+		} `json:"groups"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respMsg); err != nil {
+		t.Fatal("Failed to decode response: ", err)
+	}
+	assert.False(t, respMsg.Healthy)
+	assert.Contains(t, respMsg.Checks, "cache")
+	assert.False(t, respMsg.Groups["databases"].Healthy)
+	assert.Contains(t, respMsg.Groups["databases"].Checks, "primary")
+	assert.Equal(t, int64(1), respMsg.Groups["databases"].Checks["replica"].ContiguousFailures)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meh?type=long", nil))
+	var flat map[string]checkResult
+	if err := json.NewDecoder(w.Result().Body).Decode(&flat); err != nil {
+		t.Fatal("Failed to decode response: ", err)
+	}
+	assert.Contains(t, flat, "databases.replica", "flat results are still served")
+	assert.NotEqual(t, w.Result().Header.Get("ETag"), resp.Header.Get("ETag"), "ETag should differ from the flat long format")
+}
@@ -3,7 +3,9 @@ package http
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/AppsFlyer/go-sundheit"
 )
@@ -11,23 +13,94 @@ import (
 const (
 	// ReportTypeShort is the value to be passed in the request parameter `type` when a short response is desired.
 	ReportTypeShort = "short"
+
+	// ReportTypeGrouped is the value to be passed in the request parameter `type` when the results should be nested by group,
+	// where the groups are the dot separated prefixes of the check names (e.g. the `databases` group of `databases.primary`).
+	ReportTypeGrouped = "grouped"
+
+	// HeaderHealthScore is the response header holding the weighted health score (see gosundheit.Health.Score())
+	HeaderHealthScore = "X-Health-Score"
+
+	// reportTypeConfigEcho is the report type of long responses with the checks configuration
+	reportTypeConfigEcho = "config"
 )
 
-// HandleHealthJSON returns an HandlerFunc that can be used as an endpoints that exposes the service health
-func HandleHealthJSON(h gosundheit.Health) http.HandlerFunc {
+// HandleHealthJSON returns an HandlerFunc that can be used as an endpoints that exposes the service health.
+// Responses carry an ETag header, and conditional requests (using If-None-Match) are answered with
+// `304 Not Modified` while the results are unchanged.
+// The results may be filtered by check names, and paginated, using the QueryChecks, QueryPage and QueryPageSize request parameters,
+// while the response status reflects the health of all the checks.
+// Responses are gzip encoded for requests accepting the gzip encoding.
+// When enabled using WithSync, requests with the QuerySync parameter (e.g. `?sync=1`) execute the selected checks right away,
+// and respond with their fresh results; the executions are bounded by the request context deadline (see WithSyncTimeout),
+// and the checks whose execution didn't complete in time are listed in the HeaderSyncIncomplete response header.
+// The response may be customized using HandlerOptions.
+func HandleHealthJSON(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	cfg := newHandlerConfig(opts)
 	return func(w http.ResponseWriter, request *http.Request) {
-		results, healthy := h.Results()
+		if cfg.failOpen {
+			if warning := bootstrapWarning(h); warning != "" {
+				writeFailOpen(w, warning)
+				return
+			}
+		}
+
+		filter, err := parseResultsFilter(request.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if cfg.sync {
+			sync, err := parseSync(request.URL.Query())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if sync {
+				setSyncHeaders(w, syncChecks(request, h, filter, cfg))
+			}
+		}
+
+		reportType := request.URL.Query().Get("type")
+		if reportType == "" && cfg.groupedResults {
+			reportType = ReportTypeGrouped
+		}
+		if reportType != ReportTypeShort && reportType != ReportTypeGrouped && cfg.configEcho {
+			reportType = reportTypeConfigEcho
+		}
+		compress := acceptsGzip(request)
+		w.Header().Add("Vary", "Accept-Encoding")
+		longFormat := reportType != ReportTypeShort && reportType != ReportTypeGrouped && reportType != reportTypeConfigEcho
+		view := readResults(h, longFormat && filter == nil)
+		results, healthy := view.results, view.healthy
+		status := cfg.statusOf(results, healthy)
+		if etag := resultsETag(view, status, reportType, filter, compress); etag != "" {
+			w.Header().Set("ETag", etag)
+			if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		if filter != nil {
+			var total int
+			results, total = filter.apply(results)
+			filter.setPaginationHeaders(w, request, total)
+		}
 		w.Header().Set("Content-Type", "application/json")
-		if healthy {
-			w.WriteHeader(200)
-		} else {
-			w.WriteHeader(503)
+		w.Header().Set(HeaderHealthScore, strconv.FormatFloat(h.Score(), 'f', -1, 64))
+		var body io.Writer = w
+		if compress {
+			w.Header().Set("Content-Encoding", "gzip")
+			var closeBody func()
+			body, closeBody = gzipWriter(w)
+			defer closeBody()
 		}
+		w.WriteHeader(status)
 
-		encoder := json.NewEncoder(w)
+		encoder := json.NewEncoder(body)
 		encoder.SetIndent("", "\t")
-		var err error
-		if request.URL.Query().Get("type") == ReportTypeShort {
+		if reportType == ReportTypeShort {
 			shortResults := make(map[string]string)
 			for k, v := range results {
 				if v.IsHealthy() {
@@ -38,12 +111,43 @@ func HandleHealthJSON(h gosundheit.Health) http.HandlerFunc {
 			}
 
 			err = encoder.Encode(shortResults)
+		} else if reportType == ReportTypeGrouped {
+			err = encoder.Encode(groupResults(results, healthy))
+		} else if reportType == reportTypeConfigEcho {
+			err = encoder.Encode(resultsWithConfig(h, results))
+		} else if view.encoded != nil {
+			_, _ = body.Write(view.encoded)
 		} else {
+			// the cached encoding holds all the results, and is missing when the results can't be encoded,
+			// in which case encoding again reports the error
 			err = encoder.Encode(results)
 		}
 
 		if err != nil {
-			_, _ = fmt.Fprintf(w, "Failed to render results JSON: %s", err)
+			_, _ = fmt.Fprintf(body, "Failed to render results JSON: %s", err)
 		}
 	}
 }
+
+// bootstrapWarning returns a warning when there are no registered checks, or when some checks didn't run yet
+func bootstrapWarning(h gosundheit.Health) string {
+	results, _ := h.Results()
+	if len(results) == 0 {
+		return "no checks are registered"
+	}
+	for _, result := range results {
+		if !result.Executed() {
+			return "some checks did not complete their first execution yet"
+		}
+	}
+	return ""
+}
+
+func writeFailOpen(w http.ResponseWriter, warning string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	_ = encoder.Encode(map[string]string{"warning": warning})
+}
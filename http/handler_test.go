@@ -2,11 +2,13 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -153,3 +155,109 @@ type checkResult struct {
 type Err struct {
 	Message string `json:"message"`
 }
+
+func BenchmarkHandleHealthJSON(b *testing.B) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	for i := 0; i < 100; i++ {
+		_ = h.RegisterCheck(createCheck(fmt.Sprintf("check.%d", i), true, time.Hour))
+	}
+	handler := HandleHealthJSON(h)
+
+	for _, format := range []string{"long", ReportTypeShort} {
+		b.Run(format, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, "/meh?type="+format, nil)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+		})
+	}
+}
+
+func TestHandleHealthJSON_etag(t *testing.T) {
+	h := gosundheit.New()
+	err := h.RegisterCheck(createCheck("check1", true, 10*time.Millisecond))
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	defer h.DeregisterAll()
+
+	handler := HandleHealthJSON(h)
+	serve := func(path, ifNoneMatch string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	resp := serve("/meh", "")
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag, "response should carry an ETag")
+	shortETag := serve("/meh?type=short", "").Header.Get("ETag")
+	assert.NotEqual(t, etag, shortETag, "ETag should differ between report types")
+
+	resp = serve("/meh", etag)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode, "status for unchanged results")
+	assert.Empty(t, body, "body for unchanged results")
+	assert.Equal(t, http.StatusNotModified, serve("/meh", `"other", W/`+etag).StatusCode, "status for matching tag list")
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/meh", `"other"`).StatusCode, "status for non matching tag")
+
+	// await first run
+	time.Sleep(15 * time.Millisecond)
+
+	resp = serve("/meh", etag)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status once results changed")
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"), "ETag should change once results changed")
+}
+
+func TestHandleHealthJSON_etagLeadership(t *testing.T) {
+	leader := int32(1)
+	h := gosundheit.New(gosundheit.WithLeaderElection(func() bool { return atomic.LoadInt32(&leader) == 1 }))
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterCheck(&gosundheit.Config{
+		Check:           checks.NewScriptedCheck("leader.check", checks.FailResult(errors.New("down"))),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		LeaderPolicy:    gosundheit.LeaderOnlyHealth,
+	}))
+	_, _ = h.Trigger("leader.check")
+
+	handler := HandleHealthJSON(h)
+	serve := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/health", nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		return recorder
+	}
+
+	recorder := serve("")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "leader only failures fail the leader")
+	etag := recorder.Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, serve(etag).Code)
+
+	atomic.StoreInt32(&leader, 0)
+	recorder = serve(etag)
+	assert.Equal(t, http.StatusOK, recorder.Code, "the status changes along with the leadership")
+	assert.NotEqual(t, etag, recorder.Header().Get("ETag"))
+}
+
+func TestHandleHealthJSON_score(t *testing.T) {
+	h := gosundheit.New()
+	assert.Equal(t, "100", execReq(h, true).Header.Get(HeaderHealthScore), "score with no checks")
+
+	err := h.RegisterCheck(createCheck("check1", true, 10*time.Millisecond))
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	defer h.DeregisterAll()
+	assert.Equal(t, "0", execReq(h, false).Header.Get(HeaderHealthScore), "score before first run")
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// HandlerOption configures the health handlers
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	configEcho            bool
+	groupedResults        bool
+	failOpen              bool
+	unhealthyStatus       int
+	classificationStatus  map[string]int
+	classificationsByRank []string
+	sync                  bool
+	syncTimeout           time.Duration
+	maxSyncRequests       int
+	syncRequests          chan struct{}
+}
+
+func newHandlerConfig(opts []HandlerOption) *handlerConfig {
+	cfg := &handlerConfig{
+		unhealthyStatus: http.StatusServiceUnavailable,
+		syncTimeout:     DefaultSyncTimeout,
+		maxSyncRequests: DefaultMaxSyncRequests,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.sync {
+		cfg.syncRequests = make(chan struct{}, cfg.maxSyncRequests)
+	}
+	return cfg
+}
+
+// WithConfigEcho embeds the effective configuration of each check (execution period, scheduling, classification, etc.)
+// next to its result in the long format JSON response, so remote debuggers can see both the results and how often they are refreshed.
+func WithConfigEcho() HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.configEcho = true
+	}
+}
+
+// WithGroupedResults nests the results by group in the long format JSON response (see ReportTypeGrouped),
+// with an aggregate healthy flag per group, so large health responses stay navigable.
+// The flat results are still served for requests with the `type=long` parameter.
+func WithGroupedResults() HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.groupedResults = true
+	}
+}
+
+// WithFailOpen responds with `200` and a warning, instead of the results, while there are no registered checks,
+// or while some of the checks didn't complete their first execution yet.
+// This avoids the bootstrapping chicken-and-egg, where external monitors consider an instance whose checks didn't run yet unhealthy.
+func WithFailOpen() HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.failOpen = true
+	}
+}
+
+// WithUnhealthyStatus sets the response status code when the system is unhealthy; defaults to `503`.
+func WithUnhealthyStatus(status int) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.unhealthyStatus = status
+	}
+}
+
+// WithClassificationStatus sets the response status code when checks of the given classification fail,
+// e.g. `503` for readiness failures, but `500` for liveness failures.
+// When checks of several classifications fail, the status of the classification that was configured first is used.
+// When only checks of classifications without a configured status fail, the unhealthy status is used (see WithUnhealthyStatus).
+func WithClassificationStatus(classification string, status int) HandlerOption {
+	return func(cfg *handlerConfig) {
+		if cfg.classificationStatus == nil {
+			cfg.classificationStatus = make(map[string]int)
+		}
+		if _, ok := cfg.classificationStatus[classification]; !ok {
+			cfg.classificationsByRank = append(cfg.classificationsByRank, classification)
+		}
+		cfg.classificationStatus[classification] = status
+	}
+}
+
+// WithSync enables requests with the QuerySync parameter to execute the selected checks right away, before responding.
+// Without it, the parameter is ignored, and the cached results are served, so clients can't multiply the load on the dependencies.
+func WithSync() HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.sync = true
+	}
+}
+
+// WithMaxSyncRequests sets the number of sync requests (see WithSync) that may execute checks at once;
+// defaults to DefaultMaxSyncRequests. Further sync requests are served the cached results,
+// and list all the selected checks in the HeaderSyncIncomplete response header.
+func WithMaxSyncRequests(max int) HandlerOption {
+	return func(cfg *handlerConfig) {
+		if max > 0 {
+			cfg.maxSyncRequests = max
+		}
+	}
+}
+
+// WithSyncTimeout bounds the fresh executions of the checks requested using QuerySync, when the request
+// has no earlier deadline; defaults to DefaultSyncTimeout.
+func WithSyncTimeout(timeout time.Duration) HandlerOption {
+	return func(cfg *handlerConfig) {
+		cfg.syncTimeout = timeout
+	}
+}
+
+// statusOf returns the response status code for the given results and health.
+func (cfg *handlerConfig) statusOf(results map[string]gosundheit.Result, healthy bool) int {
+	if healthy {
+		return http.StatusOK
+	}
+	if len(cfg.classificationStatus) == 0 {
+		return cfg.unhealthyStatus
+	}
+
+	failing := make(map[string]bool)
+	for _, result := range results {
+		if result.AffectsHealth() {
+			failing[result.Classification] = true
+		}
+	}
+	for _, classification := range cfg.classificationsByRank {
+		if failing[classification] {
+			return cfg.classificationStatus[classification]
+		}
+	}
+	return cfg.unhealthyStatus
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestStatusOf(t *testing.T) {
+	failed := errors.New("failed")
+	results := map[string]gosundheit.Result{
+		"db":       {Classification: "readiness", Error: failed},
+		"deadlock": {Classification: "liveness", Error: failed},
+		"cache":    {Error: failed},
+		"queue":    {Classification: "readiness"},
+	}
+
+	cfg := newHandlerConfig(nil)
+	assert.Equal(t, http.StatusOK, cfg.statusOf(results, true), "healthy status")
+	assert.Equal(t, http.StatusServiceUnavailable, cfg.statusOf(results, false), "default unhealthy status")
+
+	cfg = newHandlerConfig([]HandlerOption{WithUnhealthyStatus(http.StatusInternalServerError)})
+	assert.Equal(t, http.StatusInternalServerError, cfg.statusOf(results, false), "custom unhealthy status")
+
+	cfg = newHandlerConfig([]HandlerOption{
+		WithClassificationStatus("liveness", http.StatusInternalServerError),
+		WithClassificationStatus("readiness", http.StatusServiceUnavailable),
+	})
+	assert.Equal(t, http.StatusInternalServerError, cfg.statusOf(results, false), "first configured classification wins")
+	delete(results, "deadlock")
+	assert.Equal(t, http.StatusServiceUnavailable, cfg.statusOf(results, false), "readiness failure status")
+	delete(results, "db")
+	assert.Equal(t, http.StatusServiceUnavailable, cfg.statusOf(results, false), "unclassified failure status")
+
+	cfg = newHandlerConfig([]HandlerOption{
+		WithUnhealthyStatus(http.StatusTeapot),
+		WithClassificationStatus("readiness", http.StatusServiceUnavailable),
+	})
+	assert.Equal(t, http.StatusTeapot, cfg.statusOf(results, false), "unconfigured classification failure status")
+
+	results["silenced"] = gosundheit.Result{Classification: "readiness", Error: failed, Silenced: true}
+	assert.Equal(t, http.StatusTeapot, cfg.statusOf(results, false), "failures not affecting the health are ignored")
+}
+
+func TestHandleHealthJSON_failOpen(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	handler := HandleHealthJSON(h, WithFailOpen())
+	serve := func() (*http.Response, map[string]string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meh", nil))
+		body := make(map[string]string)
+		_ = json.NewDecoder(w.Result().Body).Decode(&body)
+		return w.Result(), body
+	}
+
+	resp, body := serve()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status with no registered checks")
+	assert.Equal(t, "no checks are registered", body["warning"])
+
+	err := h.RegisterCheck(createCheck("check1", false, 10*time.Millisecond))
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	resp, body = serve()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status before first run")
+	assert.Equal(t, "some checks did not complete their first execution yet", body["warning"])
+
+	// await first run
+	time.Sleep(15 * time.Millisecond)
+	resp, body = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "status after first run")
+	assert.Empty(t, body["warning"], "no warning after first run")
+}
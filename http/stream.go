@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	streamEventResults = "results"
+	streamKeepAlive    = 15 * time.Second
+)
+
+// ResultsStream pushes results snapshots to connected Server-Sent Events clients, whenever the results are updated.
+// ResultsStream is a gosundheit.HealthListener, and must be registered with the Health instance it streams, e.g.:
+//
+//	stream := healthhttp.NewResultsStream()
+//	h := gosundheit.New(gosundheit.WithHealthListeners(stream))
+//	http.Handle("/health/stream", stream.Handler(h))
+type ResultsStream struct {
+	lock    sync.RWMutex
+	clients map[chan []byte]struct{}
+}
+
+// NewResultsStream returns a new ResultsStream
+func NewResultsStream() *ResultsStream {
+	return &ResultsStream{
+		clients: make(map[chan []byte]struct{}),
+	}
+}
+
+// OnResultsUpdated pushes the results to all connected clients.
+// Clients that haven't consumed the previous update yet, only receive the latest one.
+func (s *ResultsStream) OnResultsUpdated(results map[string]gosundheit.Result) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.clients) == 0 {
+		return
+	}
+
+	event, err := resultsEvent(results)
+	if err != nil {
+		return
+	}
+	for client := range s.clients {
+		publishLatest(client, event)
+	}
+}
+
+// Handler returns an HandlerFunc that streams the results of the given Health instance as Server-Sent Events.
+// Each event is named "results", and its data is the JSON encoded results.
+// The current results are sent once a client connects, followed by an event for each results update.
+func (s *ResultsStream) Handler(h gosundheit.Health) http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		client := make(chan []byte, 1)
+		s.subscribe(client)
+		defer s.unsubscribe(client)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		results, _ := h.Results()
+		if event, err := resultsEvent(results); err == nil {
+			publishLatest(client, event)
+		}
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-request.Context().Done():
+				return
+			case event := <-client:
+				if _, err := w.Write(event); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *ResultsStream) subscribe(client chan []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.clients[client] = struct{}{}
+}
+
+func (s *ResultsStream) unsubscribe(client chan []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.clients, client)
+}
+
+func resultsEvent(results map[string]gosundheit.Result) ([]byte, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", streamEventResults, data)), nil
+}
+
+// publishLatest sends the event to the client without blocking, replacing any pending event.
+func publishLatest(client chan []byte, event []byte) {
+	for {
+		select {
+		case client <- event:
+			return
+		default:
+		}
+		select {
+		case <-client:
+		default:
+		}
+	}
+}
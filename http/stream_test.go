@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestResultsStream(t *testing.T) {
+	stream := NewResultsStream()
+	h := gosundheit.New(gosundheit.WithHealthListeners(stream))
+	server := httptest.NewServer(stream.Handler(h))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal("Failed to connect stream: ", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan map[string]checkResult, 10)
+	go readResultsEvents(resp, events)
+
+	initial := awaitEvent(t, events)
+	assert.Empty(t, initial, "initial results event before registration")
+
+	err = h.RegisterCheck(createCheck("check1", true, 10*time.Millisecond))
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	defer h.DeregisterAll()
+
+	updated := awaitEvent(t, events)
+	assert.Contains(t, updated, "check1", "results event after first run")
+	assert.Equal(t, "pass", updated["check1"].Message, "results event after first run")
+}
+
+func TestPublishLatest(t *testing.T) {
+	client := make(chan []byte, 1)
+	publishLatest(client, []byte("first"))
+	publishLatest(client, []byte("second"))
+	assert.Equal(t, "second", string(<-client), "only the latest event is kept")
+}
+
+func awaitEvent(t *testing.T, events chan map[string]checkResult) map[string]checkResult {
+	select {
+	case results := <-events:
+		return results
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for results event")
+		return nil
+	}
+}
+
+func readResultsEvents(resp *http.Response, events chan<- map[string]checkResult) {
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var results map[string]checkResult
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &results); err == nil {
+			events <- results
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	// QuerySync is the request parameter requesting fresh executions of the checks before responding, e.g. `?sync=1`.
+	QuerySync = "sync"
+	// HeaderSyncIncomplete is the response header holding the comma separated names of the checks
+	// whose fresh execution didn't complete within the request deadline, and whose previous results are reported.
+	HeaderSyncIncomplete = "X-Sync-Incomplete"
+	// DefaultSyncTimeout bounds the fresh executions of requests without an earlier deadline, see WithSyncTimeout.
+	DefaultSyncTimeout = 10 * time.Second
+	// DefaultMaxSyncRequests is the default number of sync requests executing checks at once, see WithMaxSyncRequests.
+	DefaultMaxSyncRequests = 1
+)
+
+// parseSync parses the sync request parameter
+func parseSync(query url.Values) (bool, error) {
+	value := query.Get(QuerySync)
+	if value == "" {
+		return false, nil
+	}
+	sync, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid %s", QuerySync)
+	}
+	return sync, nil
+}
+
+// syncChecks executes the checks selected by the filter (or all the checks) concurrently, bounded by the request deadline,
+// and returns the names of the checks whose execution didn't complete in time, sorted.
+// While the maximal number of sync requests is already executing checks, none of the checks are executed,
+// and all of them are returned.
+func syncChecks(request *http.Request, h gosundheit.Health, filter *resultsFilter, cfg *handlerConfig) []string {
+	names := syncCheckNames(h, filter)
+	select {
+	case cfg.syncRequests <- struct{}{}:
+		defer func() { <-cfg.syncRequests }()
+	default:
+		sort.Strings(names)
+		return names
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), cfg.syncTimeout)
+	defer cancel()
+
+	var (
+		wg         sync.WaitGroup
+		lock       sync.Mutex
+		incomplete []string
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			// checks deregistered meanwhile are omitted from the response
+			if _, err := h.TriggerContext(ctx, name); err != nil && ctx.Err() != nil {
+				lock.Lock()
+				incomplete = append(incomplete, name)
+				lock.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	sort.Strings(incomplete)
+	return incomplete
+}
+
+// syncCheckNames returns the names of the registered checks selected by the filter, or of all the registered checks
+func syncCheckNames(h gosundheit.Health, filter *resultsFilter) []string {
+	var names []string
+	for name := range h.Checks() {
+		// unknown checks of the filter are omitted from the response
+		if filter == nil || filter.checks == nil || filter.checks[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func setSyncHeaders(w http.ResponseWriter, incomplete []string) {
+	if len(incomplete) > 0 {
+		w.Header().Set(HeaderSyncIncomplete, strings.Join(incomplete, ","))
+	}
+}
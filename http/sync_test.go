@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestHandleHealthJSON_sync(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	release := make(chan struct{})
+	defer close(release)
+	assert.NoError(t, h.RegisterChecks(
+		&gosundheit.Config{
+			Check:           checks.NewScriptedCheck("db", checks.PassResult("ok"), checks.FailResult(errors.New("down"))),
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+		},
+		&gosundheit.Config{
+			Check: &checks.CustomCheck{CheckName: "cache", CheckFunc: func() (interface{}, error) {
+				<-release
+				return nil, nil
+			}},
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+		},
+	))
+	handler := HandleHealthJSON(h, WithSync(), WithSyncTimeout(20*time.Millisecond))
+
+	get := func(target string) (*httptest.ResponseRecorder, map[string]string) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		var results map[string]string
+		_ = json.Unmarshal(recorder.Body.Bytes(), &results)
+		return recorder, results
+	}
+
+	recorder, results := get("/health?type=short&sync=1&checks=db")
+	assert.Equal(t, map[string]string{"db": "PASS"}, results)
+	assert.Empty(t, recorder.Header().Get(HeaderSyncIncomplete))
+
+	recorder, results = get("/health?type=short&sync=true")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, map[string]string{"db": "FAIL", "cache": "FAIL"}, results, "the cache check didn't execute yet")
+	assert.Equal(t, "cache", recorder.Header().Get(HeaderSyncIncomplete))
+
+	recorder, _ = get("/health?type=short&sync=maybe")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestHandleHealthJSON_syncDisabled(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterCheck(&gosundheit.Config{
+		Check:           checks.NewScriptedCheck("db", checks.PassResult("ok")),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+
+	recorder := httptest.NewRecorder()
+	HandleHealthJSON(h)(recorder, httptest.NewRequest(http.MethodGet, "/health?type=short&sync=1", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "sync requests are served the cached results by default")
+	assert.JSONEq(t, `{"db": "FAIL"}`, recorder.Body.String())
+}
+
+func TestHandleHealthJSON_maxSyncRequests(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.NoError(t, h.RegisterChecks(
+		&gosundheit.Config{
+			Check: &checks.CustomCheck{CheckName: "slow", CheckFunc: func() (interface{}, error) {
+				started <- struct{}{}
+				<-release
+				return nil, nil
+			}},
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+		},
+		&gosundheit.Config{
+			Check:           checks.NewScriptedCheck("db", checks.PassResult("ok")),
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+		},
+	))
+	handler := HandleHealthJSON(h, WithSync(), WithMaxSyncRequests(1))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health?sync=1&checks=slow", nil))
+	}()
+	<-started
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health?type=short&sync=1", nil))
+	assert.Equal(t, "db,slow", recorder.Header().Get(HeaderSyncIncomplete), "checks are not executed beyond the limit")
+	assert.JSONEq(t, `{"db": "FAIL", "slow": "FAIL"}`, recorder.Body.String())
+
+	close(release)
+	<-done
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health?type=short&sync=1&checks=db", nil))
+	assert.Empty(t, recorder.Header().Get(HeaderSyncIncomplete), "checks are executed once the running request completed")
+	assert.JSONEq(t, `{"db": "PASS"}`, recorder.Body.String())
+}
+
+func TestParseSync(t *testing.T) {
+	for query, expected := range map[string]bool{"": false, "sync=1": true, "sync=true": true, "sync=0": false} {
+		request := httptest.NewRequest(http.MethodGet, "/health?"+query, nil)
+		sync, err := parseSync(request.URL.Query())
+		assert.NoError(t, err, query)
+		assert.Equal(t, expected, sync, query)
+	}
+}
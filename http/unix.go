@@ -0,0 +1,52 @@
+package http
+
+import (
+	stderrors "errors"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// ListenUnix listens on the given unix domain socket path, for serving the health endpoint to sidecars and node agents, e.g.:
+//
+//	listener, err := healthhttp.ListenUnix("/var/run/my-service/health.sock")
+//	...
+//	go http.Serve(listener, healthhttp.HandleHealthJSON(h))
+//
+// A stale socket file left behind by a previous process (i.e. which refuses connections) is removed first;
+// a socket another process is listening on (e.g. the previous instance during a rolling restart), or any other existing file,
+// fails the listen. The socket file is removed when the listener is closed.
+func ListenUnix(socket string) (net.Listener, error) {
+	if info, err := os.Lstat(socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.Errorf("%s exists and is not a socket", socket)
+		}
+		if err := checkStaleSocket(socket); err != nil {
+			return nil, err
+		}
+		if err := os.Remove(socket); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove stale socket %s", socket)
+		}
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen on %s", socket)
+	}
+	return listener, nil
+}
+
+// checkStaleSocket returns an error, unless connecting to the socket is refused, i.e. no process is listening on it
+func checkStaleSocket(socket string) error {
+	conn, err := net.Dial("unix", socket)
+	if err == nil {
+		_ = conn.Close()
+		return errors.Errorf("%s is in use by another process", socket)
+	}
+	if !stderrors.Is(err, syscall.ECONNREFUSED) {
+		return errors.Wrapf(err, "failed to check whether %s is stale", socket)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthhttp")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "health.sock")
+
+	stale, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	listener, err := ListenUnix(socket)
+	assert.NoError(t, err, "stale socket should be replaced")
+
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	server := &http.Server{Handler: HandleHealthJSON(h)}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://localhost/")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestListenUnixInUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthhttp")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "health.sock")
+
+	live, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	defer live.Close()
+
+	_, err = ListenUnix(socket)
+	assert.EqualError(t, err, socket+" is in use by another process")
+	conn, err := net.Dial("unix", socket)
+	assert.NoError(t, err, "the live socket must not be removed")
+	_ = conn.Close()
+}
+
+func TestListenUnixNotASocket(t *testing.T) {
+	file, err := ioutil.TempFile("", "healthhttp")
+	assert.NoError(t, err)
+	_ = file.Close()
+	defer os.Remove(file.Name())
+
+	_, err = ListenUnix(file.Name())
+	assert.Error(t, err, "regular files must not be removed")
+	_, err = os.Stat(file.Name())
+	assert.NoError(t, err)
+}
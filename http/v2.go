@@ -0,0 +1,217 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// SchemaVersionV2 is the version of the v2 response schema, as reported in ReportV2.Version.
+const SchemaVersionV2 = "2"
+
+// Status is the status of a check, or of the service, in the v2 response schema.
+type Status string
+
+const (
+	// StatusPass means the check passed, or that the service is healthy and all its checks passed.
+	StatusPass Status = "pass"
+	// StatusWarn means the check passed with a degraded status, or failed without affecting the health
+	// (a Warning check, or a failure that is silenced or within the grace period);
+	// or that the service is healthy, but some of its checks warn.
+	StatusWarn Status = "warn"
+	// StatusFail means the check failed, or that the service is unhealthy.
+	StatusFail Status = "fail"
+)
+
+// ReportV2 is the v2 health response. The v2 schema is stable: fields may be added, but are never removed or changed.
+type ReportV2 struct {
+	// Version is the schema version, i.e. SchemaVersionV2
+	Version string `json:"version"`
+	// Status is the status of the service
+	Status Status `json:"status"`
+	// Score is the weighted health score (0-100), see gosundheit.Health.Score()
+	Score float64 `json:"score"`
+	// Generation is the generation of the results, see gosundheit.Health.Generation()
+	Generation uint64 `json:"generation"`
+	// Checks are the results of the checks, sorted by name
+	Checks []CheckV2 `json:"checks"`
+}
+
+// CheckV2 is the result of a check in the v2 health response.
+type CheckV2 struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+	// Status is the status of the check
+	Status Status `json:"status"`
+	// Output is the error message of a failed check
+	Output string `json:"output,omitempty"`
+	// Details are the details reported by the check
+	Details interface{} `json:"details,omitempty"`
+	// Time is the time of the last execution, in RFC 3339 format
+	Time time.Time `json:"time"`
+	// Duration is the duration of the last execution, in ISO 8601 format, e.g. "PT0.25S"
+	Duration string `json:"duration"`
+	// Timings is the breakdown of the duration by phase (e.g. "dns", "connect"), in ISO 8601 format, when reported by the check
+	Timings map[string]string `json:"timings,omitempty"`
+	// ContiguousFailures is the number of failures that occurred in a row
+	ContiguousFailures int64 `json:"contiguousFailures"`
+	// FailingSince is the time of the first failure of a failing check
+	FailingSince *time.Time `json:"failingSince,omitempty"`
+	// Metadata is the metadata of the check, as configured in the check Config
+	Metadata CheckMetadataV2 `json:"metadata"`
+}
+
+// CheckMetadataV2 is the metadata of a check in the v2 health response.
+type CheckMetadataV2 struct {
+	Severity       string            `json:"severity"`
+	Classification string            `json:"classification,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	RunbookURL     string            `json:"runbookUrl,omitempty"`
+	Owner          string            `json:"owner,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	Capabilities   []string          `json:"capabilities,omitempty"`
+	// Period is the execution period of the check, in ISO 8601 format
+	Period string  `json:"period"`
+	Weight float64 `json:"weight"`
+}
+
+// NewReportV2 returns the v2 report of the current results of the given Health instance.
+func NewReportV2(h gosundheit.Health) ReportV2 {
+	// the generation is read first, so the results are at least as recent
+	generation := h.Generation()
+	results, healthy := h.Results()
+	return reportV2Of(h, generation, results, healthy)
+}
+
+func reportV2Of(h gosundheit.Health, generation uint64, results map[string]gosundheit.Result, healthy bool) ReportV2 {
+	configs := h.Checks()
+
+	report := ReportV2{
+		Version:    SchemaVersionV2,
+		Status:     StatusPass,
+		Score:      h.Score(),
+		Generation: generation,
+		Checks:     make([]CheckV2, 0, len(results)),
+	}
+	for name, result := range results {
+		cfg := configs[name]
+		check := CheckV2{
+			Name:               name,
+			Status:             statusOf(result, cfg),
+			Details:            result.Details,
+			Time:               result.Timestamp,
+			Duration:           isoDuration(result.Duration),
+			ContiguousFailures: result.ContiguousFailures,
+			FailingSince:       result.TimeOfFirstFailure,
+			Metadata: CheckMetadataV2{
+				Severity:       cfg.Severity.String(),
+				Classification: result.Classification,
+				Description:    result.Description,
+				RunbookURL:     result.RunbookURL,
+				Owner:          result.Owner,
+				Annotations:    result.Annotations,
+				Capabilities:   result.Capabilities,
+				Period:         isoDuration(cfg.ExecutionPeriod),
+				Weight:         result.Weight,
+			},
+		}
+		if len(result.Timings) > 0 {
+			check.Timings = make(map[string]string, len(result.Timings))
+			for phase, duration := range result.Timings {
+				check.Timings[phase] = isoDuration(duration)
+			}
+		}
+		if check.Metadata.Weight == 0 {
+			check.Metadata.Weight = 1
+		}
+		if result.Error != nil {
+			check.Output = result.Error.Error()
+		}
+		if check.Status == StatusWarn {
+			report.Status = StatusWarn
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	if !healthy {
+		report.Status = StatusFail
+	}
+	sort.Slice(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+	return report
+}
+
+func statusOf(result gosundheit.Result, cfg gosundheit.Config) Status {
+	switch {
+	case result.IsHealthy() && result.Degraded:
+		return StatusWarn
+	case result.IsHealthy():
+		return StatusPass
+	case cfg.Severity != gosundheit.Critical || !result.AffectsHealth():
+		return StatusWarn
+	default:
+		return StatusFail
+	}
+}
+
+// isoDuration formats the duration in ISO 8601 format, e.g. "PT1H2M3.5S".
+func isoDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteString("PT")
+	if hours := d / time.Hour; hours > 0 {
+		b.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		b.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// EncodeV2 writes the v2 report of the current results of the given Health instance to w.
+func EncodeV2(w io.Writer, h gosundheit.Health) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(NewReportV2(h))
+}
+
+// HandleHealthJSONv2 returns an HandlerFunc that exposes the service health in the v2 response schema (see ReportV2),
+// usually served under `/health/v2`, next to the legacy response of HandleHealthJSON.
+// The response status is customized using the WithUnhealthyStatus and WithClassificationStatus HandlerOptions;
+// other options don't apply to the v2 response.
+func HandleHealthJSONv2(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	cfg := newHandlerConfig(opts)
+	return func(w http.ResponseWriter, _ *http.Request) {
+		generation := h.Generation()
+		results, healthy := h.Results()
+		report := reportV2Of(h, generation, results, healthy)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderHealthScore, strconv.FormatFloat(report.Score, 'f', -1, 64))
+		w.WriteHeader(cfg.statusOf(results, healthy))
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		if err := encoder.Encode(report); err != nil {
+			_, _ = fmt.Fprintf(w, "Failed to render results JSON: %s", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestIsoDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                      "PT0S",
+		250 * time.Millisecond: "PT0.25S",
+		90 * time.Second:       "PT1M30S",
+		time.Hour + 2*time.Minute + 3500*time.Millisecond: "PT1H2M3.5S",
+		2 * time.Hour: "PT2H",
+		-time.Second:  "-PT1S",
+	} {
+		assert.Equal(t, expected, isoDuration(d), d.String())
+	}
+}
+
+func TestNewReportV2(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	assert.NoError(t, h.RegisterChecks(
+		&gosundheit.Config{Check: gosundheittest.NewManualCheck("db"), ExecutionPeriod: 10 * time.Second, Owner: "storage", Weight: 3},
+		&gosundheit.Config{Check: gosundheittest.NewManualCheck("cache"), ExecutionPeriod: time.Minute, Severity: gosundheit.Warning},
+		&gosundheit.Config{Check: gosundheittest.NewManualCheck("queue"), ExecutionPeriod: time.Minute},
+	))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.SetResult("db", gosundheit.Result{Details: "ok", Timestamp: now, Duration: 15 * time.Millisecond, Owner: "storage", Weight: 3,
+		Timings: map[string]time.Duration{"connect": 5 * time.Millisecond, "query": 10 * time.Millisecond}})
+	h.SetResult("cache", gosundheit.Result{Error: errors.New("down"), Timestamp: now, ContiguousFailures: 2, TimeOfFirstFailure: &now})
+	h.SetResult("queue", gosundheit.Result{Details: "slow", Degraded: true, Timestamp: now})
+
+	report := NewReportV2(h)
+	assert.Equal(t, SchemaVersionV2, report.Version)
+	assert.Equal(t, StatusWarn, report.Status, "healthy, with warning checks")
+	assert.Equal(t, h.Generation(), report.Generation)
+	assert.Equal(t, []CheckV2{
+		{
+			Name:               "cache",
+			Status:             StatusWarn,
+			Output:             "down",
+			Time:               now,
+			Duration:           "PT0S",
+			ContiguousFailures: 2,
+			FailingSince:       &now,
+			Metadata:           CheckMetadataV2{Severity: "Warning", Period: "PT1M", Weight: 1},
+		},
+		{
+			Name:     "db",
+			Status:   StatusPass,
+			Details:  "ok",
+			Time:     now,
+			Duration: "PT0.015S",
+			Timings:  map[string]string{"connect": "PT0.005S", "query": "PT0.01S"},
+			Metadata: CheckMetadataV2{Severity: "Critical", Owner: "storage", Period: "PT10S", Weight: 3},
+		},
+		{
+			Name:     "queue",
+			Status:   StatusWarn,
+			Details:  "slow",
+			Time:     now,
+			Duration: "PT0S",
+			Metadata: CheckMetadataV2{Severity: "Critical", Period: "PT1M", Weight: 1},
+		},
+	}, report.Checks)
+
+	h.SetFailing("queue", errors.New("down"))
+	report = NewReportV2(h)
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, StatusFail, report.Checks[2].Status)
+}
+
+func TestHandleHealthJSONv2(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+	h.SetResult("cache", gosundheit.Result{Error: errors.New("down"), Silenced: true})
+
+	handler := HandleHealthJSONv2(h, WithUnhealthyStatus(http.StatusInternalServerError))
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health/v2", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var report map[string]interface{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, "2", report["version"])
+	assert.Equal(t, "warn", report["status"])
+	checks := report["checks"].([]interface{})
+	if assert.Len(t, checks, 2) {
+		cache := checks[0].(map[string]interface{})
+		assert.Equal(t, "cache", cache["name"])
+		assert.Equal(t, "warn", cache["status"], "silenced failures warn")
+		assert.Equal(t, "down", cache["output"])
+		assert.Equal(t, "PT0S", cache["duration"])
+	}
+
+	h.SetFailing("db", nil)
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health/v2", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	// DefaultWaitTimeout is the time HandleWaitForHealthy waits for the system to become healthy,
+	// when the request parameter `timeout` is not specified.
+	DefaultWaitTimeout = 30 * time.Second
+)
+
+// HandleWaitForHealthy returns an HandlerFunc that blocks until the system is healthy, or until the timeout elapses,
+// and then responds as HandleHealthJSON does with the given options (i.e. `200` when healthy, and `503` otherwise).
+// The timeout is specified in the request parameter `timeout` (e.g. `/health/wait?timeout=30s`), and defaults to DefaultWaitTimeout.
+// This is useful for init containers and deployment gates that need to wait for a service to become ready.
+func HandleWaitForHealthy(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	handleHealth := HandleHealthJSON(h, opts...)
+	return func(w http.ResponseWriter, request *http.Request) {
+		timeout := DefaultWaitTimeout
+		if value := request.URL.Query().Get("timeout"); value != "" {
+			var err error
+			if timeout, err = time.ParseDuration(value); err != nil || timeout < 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprintf(w, "Invalid timeout: %s", value)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(request.Context(), timeout)
+		defer cancel()
+		_ = h.WaitForHealthy(ctx)
+
+		handleHealth(w, request)
+	}
+}
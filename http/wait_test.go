@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestHandleWaitForHealthy(t *testing.T) {
+	h := gosundheit.New()
+	err := h.RegisterCheck(createCheck("check1", true, 20*time.Millisecond))
+	if err != nil {
+		t.Error("Failed to register check: ", err)
+	}
+	defer h.DeregisterAll()
+
+	resp := execWaitReq(h, "?timeout=1ms")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "status when timing out before first run")
+
+	resp = execWaitReq(h, "?timeout=1s")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status after waiting for first run")
+	assert.Equal(t, "pass", unmarshalLongFormat(resp.Body).Check1.Message, "body after waiting for first run")
+
+	resp = execWaitReq(h, "?timeout=soon")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "status for an invalid timeout")
+}
+
+func execWaitReq(h gosundheit.Health, query string) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, "/health/wait"+query, nil)
+	w := httptest.NewRecorder()
+	HandleWaitForHealthy(h)(w, req)
+	return w.Result()
+}
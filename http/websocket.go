@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/internal/websocket"
+)
+
+// Change event types broadcast by EventsBroadcaster
+const (
+	EventRegistered   = "registered"
+	EventTransitioned = "transitioned"
+	EventDeregistered = "deregistered"
+)
+
+const clientEventsBuffer = 64
+
+// ChangeEvent is a check change event, as broadcast to WebSocket clients.
+type ChangeEvent struct {
+	// Type is one of EventRegistered, EventTransitioned or EventDeregistered
+	Type string `json:"type"`
+	// Check is the check name
+	Check string `json:"check"`
+	// Classification is the check classification, if any
+	Classification string `json:"classification,omitempty"`
+	// Result is the check result; it is nil for EventDeregistered events
+	Result *gosundheit.Result `json:"result,omitempty"`
+}
+
+// EventsBroadcaster broadcasts check change events (registration, pass/fail transitions and deregistration)
+// to connected WebSocket clients.
+// EventsBroadcaster is a gosundheit.CheckListener, and must be registered with the Health instance it broadcasts, e.g.:
+//
+//	events := healthhttp.NewEventsBroadcaster()
+//	h := gosundheit.New(gosundheit.WithCheckListeners(events))
+//	http.Handle("/health/events", events.Handler())
+//
+// Clients may filter the events using the `checks` and `classifications` (comma separated) request parameters.
+// Clients that don't keep up with the events are disconnected.
+type EventsBroadcaster struct {
+	lock    sync.Mutex
+	healthy map[string]bool
+	classes map[string]string
+	clients map[*eventsClient]struct{}
+}
+
+type eventsClient struct {
+	events          chan []byte
+	checks          map[string]bool
+	classifications map[string]bool
+}
+
+// NewEventsBroadcaster returns a new EventsBroadcaster
+func NewEventsBroadcaster() *EventsBroadcaster {
+	return &EventsBroadcaster{
+		healthy: make(map[string]bool),
+		classes: make(map[string]string),
+		clients: make(map[*eventsClient]struct{}),
+	}
+}
+
+// OnCheckRegistered broadcasts an EventRegistered event
+func (b *EventsBroadcaster) OnCheckRegistered(name string, result gosundheit.Result) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.healthy[name] = !result.AffectsHealth()
+	b.classes[name] = result.Classification
+	b.broadcast(ChangeEvent{Type: EventRegistered, Check: name, Classification: result.Classification, Result: &result})
+}
+
+// OnCheckStarted is a noop
+func (b *EventsBroadcaster) OnCheckStarted(_ string) {}
+
+// OnCheckCompleted broadcasts an EventTransitioned event when the check health has changed
+func (b *EventsBroadcaster) OnCheckCompleted(name string, result gosundheit.Result) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if healthy, ok := b.healthy[name]; ok && healthy == !result.AffectsHealth() {
+		return
+	}
+	b.healthy[name] = !result.AffectsHealth()
+	b.classes[name] = result.Classification
+	b.broadcast(ChangeEvent{Type: EventTransitioned, Check: name, Classification: result.Classification, Result: &result})
+}
+
+// OnCheckDeregistered broadcasts an EventDeregistered event
+func (b *EventsBroadcaster) OnCheckDeregistered(name string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	classification := b.classes[name]
+	delete(b.healthy, name)
+	delete(b.classes, name)
+	b.broadcast(ChangeEvent{Type: EventDeregistered, Check: name, Classification: classification})
+}
+
+// broadcast sends the event to all interested clients. Callers must hold the lock.
+func (b *EventsBroadcaster) broadcast(event ChangeEvent) {
+	if len(b.clients) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for client := range b.clients {
+		if !client.accepts(event) {
+			continue
+		}
+		select {
+		case client.events <- encoded:
+		default:
+			// the client doesn't keep up, and would miss events - disconnect it
+			close(client.events)
+			delete(b.clients, client)
+		}
+	}
+}
+
+// Handler returns an HandlerFunc that upgrades requests to WebSocket connections, and sends the change events
+// to the connected clients as JSON encoded text messages.
+func (b *EventsBroadcaster) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		conn, err := websocket.Upgrade(w, request)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		client := &eventsClient{
+			events:          make(chan []byte, clientEventsBuffer),
+			checks:          parseFilter(request.URL.Query().Get("checks")),
+			classifications: parseFilter(request.URL.Query().Get("classifications")),
+		}
+		b.subscribe(client)
+		defer b.unsubscribe(client)
+
+		closed := make(chan struct{})
+		go readUntilClosed(conn, closed)
+
+		for {
+			select {
+			case <-closed:
+				return
+			case event, ok := <-client.events:
+				if !ok {
+					return
+				}
+				if err := conn.WriteMessage(websocket.OpText, event); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *EventsBroadcaster) subscribe(client *eventsClient) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.clients[client] = struct{}{}
+}
+
+func (b *EventsBroadcaster) unsubscribe(client *eventsClient) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.clients[client]; ok {
+		close(client.events)
+		delete(b.clients, client)
+	}
+}
+
+func (c *eventsClient) accepts(event ChangeEvent) bool {
+	return (len(c.checks) == 0 || c.checks[event.Check]) &&
+		(len(c.classifications) == 0 || c.classifications[event.Classification])
+}
+
+// readUntilClosed handles the client control messages, and closes the closed channel once the connection is closed.
+func readUntilClosed(conn *websocket.Conn, closed chan<- struct{}) {
+	defer close(closed)
+	for {
+		opcode, payload, err := conn.ReadMessage()
+		if err != nil || opcode == websocket.OpClose {
+			return
+		}
+		if opcode == websocket.OpPing {
+			_ = conn.WriteMessage(websocket.OpPong, payload)
+		}
+	}
+}
+
+func parseFilter(value string) map[string]bool {
+	if value == "" {
+		return nil
+	}
+	filter := make(map[string]bool)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			filter[v] = true
+		}
+	}
+	return filter
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/internal/websocket"
+)
+
+type changeEvent struct {
+	Type           string       `json:"type"`
+	Check          string       `json:"check"`
+	Classification string       `json:"classification"`
+	Result         *checkResult `json:"result"`
+}
+
+func TestEventsBroadcaster(t *testing.T) {
+	events := NewEventsBroadcaster()
+	h := gosundheit.New(gosundheit.WithCheckListeners(events))
+	server := httptest.NewServer(events.Handler())
+	defer server.Close()
+
+	conn := dialEvents(t, server.URL+"?classifications=readiness")
+	defer func() { _ = conn.Close() }()
+	awaitSubscribers(t, events, 1)
+
+	liveness := createCheck("liveness.check", true, 10*time.Millisecond)
+	liveness.Classification = "liveness"
+	readiness := createCheck("readiness.check", true, 10*time.Millisecond)
+	readiness.Classification = "readiness"
+	assert.NoError(t, h.RegisterCheck(liveness))
+	assert.NoError(t, h.RegisterCheck(readiness))
+
+	event := readChangeEvent(t, conn)
+	assert.Equal(t, EventRegistered, event.Type)
+	assert.Equal(t, "readiness.check", event.Check, "liveness events should be filtered out")
+	assert.Equal(t, "readiness", event.Classification)
+	assert.Equal(t, "didn't run yet", event.Result.Message)
+
+	event = readChangeEvent(t, conn)
+	assert.Equal(t, EventTransitioned, event.Type)
+	assert.Equal(t, "readiness.check", event.Check)
+	assert.Equal(t, "pass", event.Result.Message)
+
+	h.Deregister("readiness.check")
+	event = readChangeEvent(t, conn)
+	assert.Equal(t, EventDeregistered, event.Type)
+	assert.Equal(t, "readiness.check", event.Check)
+	assert.Equal(t, "readiness", event.Classification)
+	assert.Nil(t, event.Result)
+
+	h.DeregisterAll()
+}
+
+func TestEventsBroadcaster_slowClient(t *testing.T) {
+	events := NewEventsBroadcaster()
+	client := &eventsClient{events: make(chan []byte, 1)}
+	events.subscribe(client)
+
+	events.OnCheckDeregistered("check1")
+	events.OnCheckDeregistered("check2")
+
+	<-client.events
+	_, open := <-client.events
+	assert.False(t, open, "slow client should be disconnected")
+	assert.Empty(t, events.clients)
+}
+
+func TestParseFilter(t *testing.T) {
+	assert.Nil(t, parseFilter(""))
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, parseFilter("a, b,,"))
+}
+
+func dialEvents(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := (&websocket.Dialer{}).Dial(context.Background(), strings.Replace(url, "http", "ws", 1), nil)
+	if err != nil {
+		t.Fatal("Failed to connect: ", err)
+	}
+	return conn
+}
+
+func awaitSubscribers(t *testing.T, events *EventsBroadcaster, count int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		events.lock.Lock()
+		subscribed := len(events.clients)
+		events.lock.Unlock()
+		if subscribed == count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out waiting for subscribers")
+}
+
+func readChangeEvent(t *testing.T, conn *websocket.Conn) changeEvent {
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+	opcode, payload, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal("Failed to read event: ", err)
+	}
+	assert.Equal(t, websocket.OpText, opcode)
+
+	var event changeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatal("Failed to decode event: ", err)
+	}
+	return event
+}
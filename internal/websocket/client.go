@@ -0,0 +1,125 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Dialer establishes client WebSocket connections.
+type Dialer struct {
+	// NetDialer is used for establishing the TCP connection, defaults to a zero value net.Dialer.
+	NetDialer *net.Dialer
+	// TLSConfig is used for `wss` URLs.
+	TLSConfig *tls.Config
+}
+
+// Dial connects to the given ws:// or wss:// URL, and performs the opening handshake.
+// The context bounds both the connection establishment and the handshake.
+func (d *Dialer) Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		secure = true
+	default:
+		return nil, nil, errors.Errorf("unsupported scheme: '%s'", u.Scheme)
+	}
+
+	hostPort := u.Host
+	if u.Port() == "" {
+		if secure {
+			hostPort = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			hostPort = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	netDialer := d.NetDialer
+	if netDialer == nil {
+		netDialer = &net.Dialer{}
+	}
+	conn, err := netDialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		return nil, nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if secure {
+		cfg := d.TLSConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{} // #nosec G402 - defaults are verified by the TLS client
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err = tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+		conn = tlsConn
+	}
+
+	ws, resp, err := handshake(conn, u, header)
+	if err != nil {
+		_ = conn.Close()
+		return nil, resp, err
+	}
+	return ws, resp, nil
+}
+
+func handshake(conn net.Conn, u *url.URL, header http.Header) (*Conn, *http.Response, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err = req.Write(conn); err != nil {
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp, errors.Errorf("unexpected handshake status code: '%v' expected: '%v'",
+			resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, resp, errors.New("invalid Sec-WebSocket-Accept handshake header")
+	}
+
+	return newConn(conn, reader, true), resp, nil
+}
@@ -0,0 +1,172 @@
+// Package websocket is a minimal RFC 6455 implementation, providing just enough of the protocol
+// for probing WebSocket endpoints and for pushing messages to WebSocket clients.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 - mandated by RFC 6455 for the handshake, not used for security
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Opcodes defined by RFC 6455
+const (
+	OpContinuation byte = 0x0
+	OpText         byte = 0x1
+	OpBinary       byte = 0x2
+	OpClose        byte = 0x8
+	OpPing         byte = 0x9
+	OpPong         byte = 0xA
+)
+
+const (
+	acceptGUID        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	maxControlPayload = 125
+	maxFramePayload   = 1 << 20
+)
+
+// Conn is a WebSocket connection.
+// Writes are safe for concurrent use, reads must be done by a single goroutine.
+type Conn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	maskWrites bool
+	writeLock  sync.Mutex
+}
+
+func newConn(conn net.Conn, reader *bufio.Reader, client bool) *Conn {
+	return &Conn{
+		conn:       conn,
+		reader:     reader,
+		maskWrites: client,
+	}
+}
+
+// SetDeadline sets the read and write deadlines of the underlying connection.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// WriteMessage writes a single (unfragmented) frame with the given opcode and payload.
+func (c *Conn) WriteMessage(opcode byte, payload []byte) error {
+	if opcode >= OpClose && len(payload) > maxControlPayload {
+		return errors.Errorf("control frame payload too large: %d", len(payload))
+	}
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+	var maskBit byte
+	if c.maskWrites {
+		maskBit = 0x80
+	}
+
+	switch l := len(payload); {
+	case l <= 125:
+		header[1] = maskBit | byte(l)
+	case l <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(l))
+	default:
+		header[1] = maskBit | 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(l))
+	}
+
+	if c.maskWrites {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		header = append(header, key[:]...)
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ key[i%4]
+		}
+		payload = masked
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+// ReadMessage reads a single frame, and returns its opcode and (unmasked) payload.
+// Fragmented messages are returned frame by frame.
+func (c *Conn) ReadMessage() (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxFramePayload {
+		return 0, nil, errors.Errorf("frame payload too large: %d", length)
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.reader, key[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+// Close sends a normal closure frame (best effort), and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.WriteMessage(OpClose, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}
+
+func newKey() (string, error) {
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key[:]), nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New() // #nosec G401
+	_, _ = h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWriteMessage(t *testing.T) {
+	for _, size := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		clientSide, serverSide := net.Pipe()
+		client := newConn(clientSide, bufio.NewReader(clientSide), true)
+		server := newConn(serverSide, bufio.NewReader(serverSide), false)
+
+		payload := []byte(strings.Repeat("x", size))
+		go func() {
+			_ = client.WriteMessage(OpText, payload)
+		}()
+		opcode, received, err := server.ReadMessage()
+		assert.NoError(t, err, "client to server message of size %d", size)
+		assert.Equal(t, OpText, opcode)
+		assert.Equal(t, payload, received)
+
+		go func() {
+			_ = server.WriteMessage(OpBinary, payload)
+		}()
+		opcode, received, err = client.ReadMessage()
+		assert.NoError(t, err, "server to client message of size %d", size)
+		assert.Equal(t, OpBinary, opcode)
+		assert.Equal(t, payload, received)
+
+		_ = clientSide.Close()
+		_ = serverSide.Close()
+	}
+}
+
+func TestWriteMessage_controlFrameTooLarge(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer func() { _ = serverSide.Close() }()
+	client := newConn(clientSide, bufio.NewReader(clientSide), true)
+
+	assert.Error(t, client.WriteMessage(OpPing, make([]byte, 126)))
+}
+
+func TestAcceptKey(t *testing.T) {
+	// example taken from RFC 6455 section 1.3
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// Kind is the kind of a Kubernetes object whose readiness is checked.
+type Kind string
+
+const (
+	// KindDeployment checks the ready replicas of a Deployment.
+	KindDeployment Kind = "Deployment"
+	// KindStatefulSet checks the ready replicas of a StatefulSet.
+	KindStatefulSet Kind = "StatefulSet"
+	// KindEndpoints checks the ready addresses of an Endpoints object (i.e. of a Service).
+	KindEndpoints Kind = "Endpoints"
+)
+
+// Status is the readiness status of a Kubernetes object.
+type Status struct {
+	// Replicas is the desired number of replicas; for Endpoints this is the number of addresses, ready or not.
+	Replicas int
+	// ReadyReplicas is the number of ready replicas; for Endpoints this is the number of ready addresses.
+	ReadyReplicas int
+}
+
+// Client is the minimal Kubernetes API required by the readiness check.
+// Implementations are usually thin adapters over a client-go `kubernetes.Interface` (or an informer lister),
+// reporting `status.replicas` and `status.readyReplicas` of workloads, and the addresses of endpoints subsets.
+type Client interface {
+	// Status fetches the readiness status of the given object.
+	Status(ctx context.Context, kind Kind, namespace, name string) (Status, error)
+}
+
+// CheckConfig configures a check that verifies an in-cluster workload has ready replicas.
+type CheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Client is required, and is used for querying the Kubernetes API.
+	Client Client
+	// Kind is required, and is the kind of the checked object.
+	Kind Kind
+	// Namespace is the namespace of the checked object, defaults to "default".
+	Namespace string
+	// Name is required, and is the name of the checked object.
+	Name string
+	// MinReadyReplicas is the minimal number of ready replicas for the check to pass, defaults to `1`.
+	MinReadyReplicas int
+	// Timeout is the timeout used for querying the Kubernetes API, defaults to "1s".
+	Timeout time.Duration
+}
+
+// Details are the details reported by the readiness check.
+type Details struct {
+	// Object identifies the checked object, as "<kind> <namespace>/<name>".
+	Object string `json:"object"`
+	// Replicas is the desired number of replicas (or addresses), when the object was fetched.
+	Replicas int `json:"replicas"`
+	// ReadyReplicas is the number of ready replicas (or addresses), when the object was fetched.
+	ReadyReplicas int `json:"readyReplicas"`
+}
+
+type readinessCheck struct {
+	config *CheckConfig
+}
+
+// NewReadinessCheck creates a new Kubernetes readiness check defined by the given config.
+// The check fails when the object can't be fetched, or has less than `MinReadyReplicas` ready replicas.
+func NewReadinessCheck(config CheckConfig) (checks.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Client == nil {
+		return nil, errors.Errorf("Client must not be nil")
+	}
+	switch config.Kind {
+	case KindDeployment, KindStatefulSet, KindEndpoints:
+	default:
+		return nil, errors.Errorf("unsupported Kind: '%s'", config.Kind)
+	}
+	if config.Name == "" {
+		return nil, errors.Errorf("Name must not be empty")
+	}
+	if config.Namespace == "" {
+		config.Namespace = "default"
+	}
+	if config.MinReadyReplicas <= 0 {
+		config.MinReadyReplicas = 1
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &readinessCheck{config: &config}, nil
+}
+
+func (check *readinessCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *readinessCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	result := Details{Object: string(check.config.Kind) + " " + check.config.Namespace + "/" + check.config.Name}
+	status, err := check.config.Client.Status(ctx, check.config.Kind, check.config.Namespace, check.config.Name)
+	if err != nil {
+		return result, errors.Errorf("failed to get %s: %v", result.Object, err)
+	}
+	result.Replicas = status.Replicas
+	result.ReadyReplicas = status.ReadyReplicas
+	if status.ReadyReplicas < check.config.MinReadyReplicas {
+		return result, errors.Errorf("%s has %d/%d ready replicas, expected at least %d",
+			result.Object, status.ReadyReplicas, status.Replicas, check.config.MinReadyReplicas)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReadinessCheckRequiredFields(t *testing.T) {
+	check, err := NewReadinessCheck(CheckConfig{Client: &clientStub{}, Kind: KindDeployment, Name: "orders"})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewReadinessCheck(CheckConfig{CheckName: "meh", Kind: KindDeployment, Name: "orders"})
+	assert.Nil(t, check, "nil Client should yield nil check")
+	assert.Error(t, err, "nil Client should yield error")
+
+	check, err = NewReadinessCheck(CheckConfig{CheckName: "meh", Client: &clientStub{}, Kind: "CronJob", Name: "orders"})
+	assert.Nil(t, check, "unsupported Kind should yield nil check")
+	assert.EqualError(t, err, "unsupported Kind: 'CronJob'")
+
+	check, err = NewReadinessCheck(CheckConfig{CheckName: "meh", Client: &clientStub{}, Kind: KindDeployment})
+	assert.Nil(t, check, "nil Name should yield nil check")
+	assert.Error(t, err, "nil Name should yield error")
+}
+
+func TestReadinessCheck(t *testing.T) {
+	client := &clientStub{status: Status{Replicas: 3, ReadyReplicas: 1}}
+	check, err := NewReadinessCheck(CheckConfig{CheckName: "orders.ready", Client: client, Kind: KindDeployment, Name: "orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, "orders.ready", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass")
+	assert.Equal(t, Details{Object: "Deployment default/orders", Replicas: 3, ReadyReplicas: 1}, details)
+	assert.Equal(t, "Deployment default/orders", client.requested, "default namespace")
+	assert.True(t, client.hasDeadline, "request should have a deadline")
+
+	client.status.ReadyReplicas = 0
+	details, err = check.Execute()
+	assert.EqualError(t, err, "Deployment default/orders has 0/3 ready replicas, expected at least 1")
+	assert.Equal(t, Details{Object: "Deployment default/orders", Replicas: 3}, details)
+
+	client.err = errors.New(`endpoints "orders" not found`)
+	check, err = NewReadinessCheck(CheckConfig{CheckName: "orders.ready", Client: client, Kind: KindEndpoints, Namespace: "shop", Name: "orders"})
+	assert.NoError(t, err)
+	details, err = check.Execute()
+	assert.EqualError(t, err, `failed to get Endpoints shop/orders: endpoints "orders" not found`)
+	assert.Equal(t, Details{Object: "Endpoints shop/orders"}, details)
+}
+
+func TestReadinessCheckMinReadyReplicas(t *testing.T) {
+	client := &clientStub{status: Status{Replicas: 3, ReadyReplicas: 2}}
+	check, err := NewReadinessCheck(CheckConfig{CheckName: "db.ready", Client: client, Kind: KindStatefulSet, Name: "db", MinReadyReplicas: 2})
+	assert.NoError(t, err)
+
+	_, err = check.Execute()
+	assert.NoError(t, err, "check should pass with enough ready replicas")
+
+	client.status.ReadyReplicas = 1
+	_, err = check.Execute()
+	assert.EqualError(t, err, "StatefulSet default/db has 1/3 ready replicas, expected at least 2")
+}
+
+type clientStub struct {
+	status      Status
+	err         error
+	requested   string
+	hasDeadline bool
+}
+
+func (c *clientStub) Status(ctx context.Context, kind Kind, namespace, name string) (Status, error) {
+	c.requested = string(kind) + " " + namespace + "/" + name
+	_, c.hasDeadline = ctx.Deadline()
+	return c.status, c.err
+}
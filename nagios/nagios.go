@@ -0,0 +1,126 @@
+// Package nagios encodes results as Nagios/Icinga plugin output, so the checks can be surfaced through
+// existing NRPE based monitoring (see the `-format nagios` mode of the healthclient package).
+package nagios
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// Status is the plugin status, which is also the plugin exit code
+type Status int
+
+// Plugin statuses, see the Nagios plugin guidelines
+const (
+	StatusOK Status = iota
+	StatusWarning
+	StatusCritical
+	StatusUnknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusWarning:
+		return "WARNING"
+	case StatusCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Option configures the encoding
+type Option func(*config)
+
+type config struct {
+	serviceName string
+}
+
+// WithServiceName sets the service name the status line starts with; defaults to "HEALTH".
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// Encode returns the plugin output of the given results, and the plugin status:
+//   - CRITICAL when any check is failing, and affects the health (see gosundheit.Result.AffectsHealth())
+//   - WARNING when any check is degraded, or is failing without affecting the health (e.g. within a silence window)
+//   - OK otherwise
+//
+// The first line is the status line, followed by the performance data (the number of checks, of failing checks,
+// and the duration of each check). Each subsequent line describes a check which isn't passing, e.g.:
+//
+//	HEALTH CRITICAL - 1 of 2 checks failing: db | checks=2;;;0 failing=1;;;0 cache=0.001000s;;;0 db=0.012000s;;;0
+//	db: connection refused
+func Encode(results map[string]gosundheit.Result, opts ...Option) (string, Status) {
+	cfg := &config{serviceName: "HEALTH"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failing, warning, details []string
+	perfData := []string{
+		fmt.Sprintf("checks=%d;;;0", len(results)),
+	}
+	for _, name := range names {
+		result := results[name]
+		switch {
+		case result.Silenced:
+			warning = append(warning, name)
+			details = append(details, fmt.Sprintf("%s: %v (silenced)", name, result.Error))
+		case result.AffectsHealth():
+			failing = append(failing, name)
+			details = append(details, fmt.Sprintf("%s: %v", name, result.Error))
+		case !result.IsHealthy():
+			warning = append(warning, name)
+			details = append(details, fmt.Sprintf("%s: %v (not affecting the health)", name, result.Error))
+		case result.Degraded:
+			warning = append(warning, name)
+			details = append(details, fmt.Sprintf("%s: degraded", name))
+		}
+	}
+	perfData = append(perfData, fmt.Sprintf("failing=%d;;;0", len(failing)))
+	for _, name := range names {
+		perfData = append(perfData, fmt.Sprintf("%s=%fs;;;0", perfLabel(name), results[name].Duration.Seconds()))
+	}
+
+	var status Status
+	var summary string
+	switch {
+	case len(failing) > 0:
+		status = StatusCritical
+		summary = fmt.Sprintf("%d of %d checks failing: %s", len(failing), len(results), strings.Join(failing, ", "))
+	case len(warning) > 0:
+		status = StatusWarning
+		summary = fmt.Sprintf("%d of %d checks degraded or silenced: %s", len(warning), len(results), strings.Join(warning, ", "))
+	default:
+		status = StatusOK
+		summary = fmt.Sprintf("%d checks passing", len(results))
+	}
+
+	lines := append([]string{
+		fmt.Sprintf("%s %s - %s | %s", cfg.serviceName, status, summary, strings.Join(perfData, " ")),
+	}, details...)
+	return strings.Join(lines, "\n") + "\n", status
+}
+
+// perfLabel quotes the label when required, and replaces the characters which are not allowed in labels
+func perfLabel(name string) string {
+	label := strings.NewReplacer("'", "_", "=", "_", "|", "_").Replace(name)
+	if strings.ContainsAny(label, " ") {
+		return "'" + label + "'"
+	}
+	return label
+}
@@ -0,0 +1,47 @@
+package nagios
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestEncode(t *testing.T) {
+	results := map[string]gosundheit.Result{
+		"db":    {Error: errors.New("connection refused"), Duration: 12 * time.Millisecond},
+		"cache": {Details: "ok", Duration: time.Millisecond},
+	}
+	output, status := Encode(results)
+	assert.Equal(t, StatusCritical, status)
+	assert.Equal(t, "HEALTH CRITICAL - 1 of 2 checks failing: db | checks=2;;;0 failing=1;;;0 cache=0.001000s;;;0 db=0.012000s;;;0\n"+
+		"db: connection refused\n", output)
+
+	results = map[string]gosundheit.Result{
+		"search": {Details: "slow", Degraded: true},
+		"backup": {Error: errors.New("maintenance"), Silenced: true},
+	}
+	output, status = Encode(results, WithServiceName("ORDERS"))
+	assert.Equal(t, StatusWarning, status)
+	assert.Equal(t, "ORDERS WARNING - 2 of 2 checks degraded or silenced: backup, search | checks=2;;;0 failing=0;;;0 backup=0.000000s;;;0 search=0.000000s;;;0\n"+
+		"backup: maintenance (silenced)\n"+
+		"search: degraded\n", output)
+
+	output, status = Encode(map[string]gosundheit.Result{"replica": {Error: errors.New("lagging"), InGracePeriod: true}})
+	assert.Equal(t, StatusWarning, status, "failures not affecting the health are warnings")
+	assert.Contains(t, output, "replica: lagging (not affecting the health)\n")
+
+	output, status = Encode(map[string]gosundheit.Result{"my check": {}})
+	assert.Equal(t, StatusOK, status)
+	assert.Equal(t, "HEALTH OK - 1 checks passing | checks=1;;;0 failing=0;;;0 'my check'=0.000000s;;;0\n", output)
+}
+
+func TestStatusString(t *testing.T) {
+	assert.Equal(t, "OK", StatusOK.String())
+	assert.Equal(t, "WARNING", StatusWarning.String())
+	assert.Equal(t, "CRITICAL", StatusCritical.String())
+	assert.Equal(t, "UNKNOWN", StatusUnknown.String())
+}
@@ -0,0 +1,88 @@
+package gosundheit
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxNameLength is the maximal length of a check name, according to the naming policy (see ValidateName)
+	MaxNameLength = 128
+	// NameSeparator separates the namespaces of a check name, e.g. the `databases` namespace of `databases.primary`
+	NameSeparator = "."
+)
+
+func isNameChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// ValidateName returns an error when the check name doesn't follow the naming policy, which keeps the names safe
+// to use as metric names and labels: the name consists of ASCII letters, digits, '_' and '-', in namespaces separated
+// by NameSeparator, none of which is empty, and it is at most MaxNameLength long.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("check name must not be empty")
+	}
+	if len(name) > MaxNameLength {
+		return errors.Errorf("check name %s is longer than %d characters", name, MaxNameLength)
+	}
+	for _, namespace := range strings.Split(name, NameSeparator) {
+		if namespace == "" {
+			return errors.Errorf("check name %s has an empty namespace", name)
+		}
+		for _, c := range namespace {
+			if !isNameChar(c) {
+				return errors.Errorf("check name %s has an invalid character %q", name, c)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeName returns the normalized form of the check name, which follows the naming policy (see ValidateName):
+// the name is lower cased, '-' and invalid characters are replaced by '_', empty namespaces are removed,
+// and the name is truncated to MaxNameLength. Names that differ only by these rules share the same normalized form.
+func NormalizeName(name string) string {
+	namespaces := strings.Split(strings.ToLower(name), NameSeparator)
+	normalized := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace == "" {
+			continue
+		}
+		normalized = append(normalized, strings.Map(func(c rune) rune {
+			if c == '-' || !isNameChar(c) {
+				return '_'
+			}
+			return c
+		}, namespace))
+	}
+
+	result := strings.Join(normalized, NameSeparator)
+	if len(result) > MaxNameLength {
+		result = strings.TrimRight(result[:MaxNameLength], NameSeparator)
+	}
+	return result
+}
+
+// nameCollisions returns the errors of the checks whose names collide after normalization, with registered checks,
+// or with each other. Checks replacing registered checks of the same name don't collide with them.
+// Callers must hold the lock.
+func (h *health) nameCollisions(cfgs []*Config) MultiError {
+	names := make(map[string]string, len(h.checkTasks)+len(cfgs))
+	for name := range h.checkTasks {
+		names[NormalizeName(name)] = name
+	}
+
+	var errs MultiError
+	for _, cfg := range cfgs {
+		name := cfg.Check.Name()
+		normalized := NormalizeName(name)
+		if other, ok := names[normalized]; ok && other != name {
+			errs = append(errs, errors.Errorf("check %s collides with check %s, as both are normalized to %s", name, other, normalized))
+			continue
+		}
+		names[normalized] = name
+	}
+	return errs
+}
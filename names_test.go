@@ -0,0 +1,83 @@
+package gosundheit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"db", "orders-db", "databases.primary_1", strings.Repeat("a", MaxNameLength)} {
+		assert.NoError(t, ValidateName(name), name)
+	}
+	for _, name := range []string{"", "orders db", "databases..primary", ".db", "db.", "db/1", "מסד", strings.Repeat("a", MaxNameLength+1)} {
+		assert.Error(t, ValidateName(name), name)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "orders_db", NormalizeName("Orders-DB"))
+	assert.Equal(t, "databases.primary_1", NormalizeName("databases..primary 1"))
+	assert.Equal(t, "db", NormalizeName(".db."))
+	assert.Equal(t, "db_1", NormalizeName("db/1"))
+	assert.Equal(t, strings.Repeat("a", MaxNameLength), NormalizeName(strings.Repeat("a", MaxNameLength+10)))
+
+	for _, name := range []string{"Orders-DB", "databases..primary 1", "db/1", "מסד"} {
+		normalized := NormalizeName(name)
+		assert.NoError(t, ValidateName(normalized), name)
+		assert.Equal(t, normalized, NormalizeName(normalized), name)
+	}
+}
+
+func TestStrictNames(t *testing.T) {
+	cfg := func(name string) *Config {
+		return &Config{Check: checks.NewScriptedCheck(name), ExecutionPeriod: time.Hour, InitialDelay: time.Hour}
+	}
+
+	t.Run("names are validated", func(t *testing.T) {
+		h := New(WithStrictNames())
+		defer h.DeregisterAll()
+
+		assert.Error(t, h.RegisterCheck(cfg("orders db")))
+		err := h.RegisterChecks(cfg("db"), cfg("cache/1"), cfg("queue..orders"))
+		assert.Len(t, err, 2)
+		assert.Empty(t, h.Checks())
+	})
+
+	t.Run("collisions are rejected", func(t *testing.T) {
+		h := New(WithStrictNames())
+		defer h.DeregisterAll()
+
+		assert.NoError(t, h.RegisterCheck(cfg("orders-db")))
+		assert.EqualError(t, h.RegisterCheck(cfg("orders_db")),
+			"check orders_db collides with check orders-db, as both are normalized to orders_db")
+		// re-registering the same check is allowed
+		assert.NoError(t, h.RegisterCheck(cfg("orders-db")))
+
+		err := h.RegisterChecks(cfg("cache"), cfg("Orders_DB"), cfg("queue"), cfg("QUEUE"))
+		assert.Len(t, err, 2)
+		assert.Equal(t, []string{"orders-db"}, checkNames(h))
+	})
+
+	t.Run("names are not enforced by default", func(t *testing.T) {
+		h := New()
+		defer h.DeregisterAll()
+
+		assert.NoError(t, h.RegisterCheck(cfg("orders db")))
+		assert.NoError(t, h.RegisterCheck(cfg("Orders-DB")))
+		assert.NoError(t, h.RegisterCheck(cfg("orders_db")))
+		assert.Len(t, h.Checks(), 3)
+	})
+}
+
+func checkNames(h Health) []string {
+	var names []string
+	for name := range h.Checks() {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// Client is the minimal object storage API required by the bucket access check.
+// Implementations are usually thin adapters over an S3 / GCS / Azure Blob / MinIO SDK client.
+type Client interface {
+	// HeadObject fetches the metadata of the given object, and returns an error when it is not accessible.
+	HeadObject(ctx context.Context, bucket, key string) error
+	// ListObjects lists up to maxKeys objects in the given bucket, and returns the number of listed objects.
+	ListObjects(ctx context.Context, bucket string, maxKeys int) (count int, err error)
+}
+
+// CheckConfig configures a check that verifies the credentials and connectivity to an object storage bucket.
+type CheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Client is required, and is used for accessing the object storage.
+	Client Client
+	// Bucket is required, and is the bucket to access.
+	Bucket string
+	// Key is optional; when defined the check fetches the metadata of this object,
+	// otherwise the check lists the bucket.
+	Key string
+	// ListPageSize is the maximal number of objects to list when `Key` is undefined, defaults to `1`.
+	ListPageSize int
+	// Timeout is the timeout used for accessing the object storage, defaults to "1s".
+	Timeout time.Duration
+}
+
+type bucketCheck struct {
+	config *CheckConfig
+}
+
+// NewBucketCheck creates a new object storage check defined by the given config.
+func NewBucketCheck(config CheckConfig) (checks.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Client == nil {
+		return nil, errors.Errorf("Client must not be nil")
+	}
+	if config.Bucket == "" {
+		return nil, errors.Errorf("Bucket must not be empty")
+	}
+	if config.ListPageSize <= 0 {
+		config.ListPageSize = 1
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &bucketCheck{config: &config}, nil
+}
+
+func (check *bucketCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *bucketCheck) Execute() (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.config.Timeout)
+	defer cancel()
+
+	if check.config.Key != "" {
+		details = fmt.Sprintf("object [%s/%s]", check.config.Bucket, check.config.Key)
+		if err = check.config.Client.HeadObject(ctx, check.config.Bucket, check.config.Key); err != nil {
+			return details, errors.Errorf("failed to access object: %v", err)
+		}
+		return fmt.Sprintf("object [%s/%s] is accessible", check.config.Bucket, check.config.Key), nil
+	}
+
+	details = fmt.Sprintf("bucket [%s]", check.config.Bucket)
+	count, err := check.config.Client.ListObjects(ctx, check.config.Bucket, check.config.ListPageSize)
+	if err != nil {
+		return details, errors.Errorf("failed to list bucket: %v", err)
+	}
+	return fmt.Sprintf("bucket [%s] is accessible, listed %d objects", check.config.Bucket, count), nil
+}
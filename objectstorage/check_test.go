@@ -0,0 +1,73 @@
+package objectstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBucketCheckRequiredFields(t *testing.T) {
+	check, err := NewBucketCheck(CheckConfig{Client: &clientStub{}, Bucket: "b"})
+	assert.Nil(t, check, "nil CheckName should yield nil check")
+	assert.Error(t, err, "nil CheckName should yield error")
+
+	check, err = NewBucketCheck(CheckConfig{CheckName: "meh", Bucket: "b"})
+	assert.Nil(t, check, "nil Client should yield nil check")
+	assert.Error(t, err, "nil Client should yield error")
+
+	check, err = NewBucketCheck(CheckConfig{CheckName: "meh", Client: &clientStub{}})
+	assert.Nil(t, check, "nil Bucket should yield nil check")
+	assert.Error(t, err, "nil Bucket should yield error")
+}
+
+func TestBucketCheck_list(t *testing.T) {
+	client := &clientStub{count: 1}
+	check, err := NewBucketCheck(CheckConfig{CheckName: "s3.check", Client: client, Bucket: "assets"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3.check", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass")
+	assert.Equal(t, "bucket [assets] is accessible, listed 1 objects", details)
+	assert.Equal(t, 1, client.maxKeys, "default list page size")
+
+	client.err = errors.New("AccessDenied")
+	details, err = check.Execute()
+	assert.EqualError(t, err, "failed to list bucket: AccessDenied")
+	assert.Equal(t, "bucket [assets]", details)
+}
+
+func TestBucketCheck_head(t *testing.T) {
+	client := &clientStub{}
+	check, err := NewBucketCheck(CheckConfig{CheckName: "s3.check", Client: client, Bucket: "assets", Key: "health/probe"})
+	assert.NoError(t, err)
+
+	details, err := check.Execute()
+	assert.NoError(t, err, "check should pass")
+	assert.Equal(t, "object [assets/health/probe] is accessible", details)
+	assert.Equal(t, "health/probe", client.key)
+
+	client.err = errors.New("NoSuchKey")
+	details, err = check.Execute()
+	assert.EqualError(t, err, "failed to access object: NoSuchKey")
+	assert.Equal(t, "object [assets/health/probe]", details)
+}
+
+type clientStub struct {
+	count   int
+	err     error
+	key     string
+	maxKeys int
+}
+
+func (c *clientStub) HeadObject(_ context.Context, _, key string) error {
+	c.key = key
+	return c.err
+}
+
+func (c *clientStub) ListObjects(_ context.Context, _ string, maxKeys int) (int, error) {
+	c.maxKeys = maxKeys
+	return c.count, c.err
+}
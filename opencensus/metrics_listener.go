@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 
 	gosundheit "github.com/AppsFlyer/go-sundheit"
 )
@@ -39,10 +40,18 @@ func (c *MetricsListener) OnResultsUpdated(results map[string]gosundheit.Result)
 	allHealthy := allHealthy(results)
 	allChecksCtx := createMonitoringCtx(c.classification, ValAllChecks, allHealthy)
 	stats.Record(allChecksCtx, mCheckStatus.M(status(allHealthy).asInt64()))
+	stats.Record(allChecksCtx, mHealthScore.M(gosundheit.ScoreOf(results)))
 }
 
 func (c *MetricsListener) recordCheck(name string, result gosundheit.Result) {
 	thisCheckCtx := createMonitoringCtx(c.classification, name, result.IsHealthy())
 	stats.Record(thisCheckCtx, mCheckDuration.M(float64(result.Duration)/float64(time.Millisecond)))
 	stats.Record(thisCheckCtx, mCheckStatus.M(status(result.IsHealthy()).asInt64()))
+	for phase, duration := range result.Timings {
+		phaseCtx, err := tag.New(thisCheckCtx, tag.Insert(keyPhase, phase))
+		if err != nil {
+			continue
+		}
+		stats.Record(phaseCtx, mPhaseDuration.M(float64(duration)/float64(time.Millisecond)))
+	}
 }
@@ -1,6 +1,7 @@
 package opencensus
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -54,6 +55,11 @@ func TestHealthMetrics(t *testing.T) {
 	assert.Equal(t, int64(2), checksTimeData[passingCheckName].(*view.DistributionData).Count, "passing check timing measurement count")
 	assert.Equal(t, int64(2), checksTimeData[failingCheckName].(*view.DistributionData).Count, "failing check timing measurement count")
 
+	scoreRows, err := view.RetrieveData(ViewHealthScore.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(scoreRows), "num score rows")
+	assert.Equal(t, &view.LastValueData{Value: 50}, scoreRows[0].Data, "health score")
+
 	view.Unregister(DefaultHealthViews...)
 }
 
@@ -160,3 +166,41 @@ func (c *checkStub) run() (details interface{}, err error) {
 
 	return fmt.Sprintf("%s; i=%d", failedMsg, c.counts), errors.New(failedMsg)
 }
+
+func TestHealthMetricsPhases(t *testing.T) {
+	_ = view.Register(DefaultHealthViews...)
+
+	listener := NewMetricsListener()
+	h := gosundheit.New(gosundheit.WithCheckListeners(listener))
+	_ = h.RegisterCheck(&gosundheit.Config{
+		Check:           &timedCheck{timings: map[string]time.Duration{"connect": 2 * time.Millisecond, "query": 5 * time.Millisecond}},
+		InitialDelay:    time.Hour,
+		ExecutionPeriod: time.Hour,
+	})
+	defer h.DeregisterAll()
+	_, err := h.Trigger("timed.check")
+	assert.NoError(t, err)
+
+	phasesData := simplifyRows(ViewCheckPhaseExecutionTime.Name)
+	assert.Equal(t, 2, len(phasesData), "num phase rows")
+	assert.Equal(t, 2.0, phasesData["timed.check.connect"].(*view.DistributionData).Mean, "connect phase duration")
+	assert.Equal(t, 5.0, phasesData["timed.check.query"].(*view.DistributionData).Mean, "query phase duration")
+
+	view.Unregister(DefaultHealthViews...)
+}
+
+type timedCheck struct {
+	timings map[string]time.Duration
+}
+
+func (c *timedCheck) Name() string {
+	return "timed.check"
+}
+
+func (c *timedCheck) Execute() (details interface{}, err error) {
+	return nil, nil
+}
+
+func (c *timedCheck) ExecuteWithContext(context.Context) checks.ExecutionResult {
+	return checks.ExecutionResult{Timings: c.timings}
+}
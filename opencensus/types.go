@@ -19,9 +19,12 @@ var (
 	keyCheck, _          = tag.NewKey("check")
 	keyCheckPassing, _   = tag.NewKey("check_passing")
 	keyClassification, _ = tag.NewKey("classification")
+	keyPhase, _          = tag.NewKey("phase")
 
 	mCheckStatus   = stats.Int64("health/status", "An health status (0/1 for fail/pass)", "pass/fail")
 	mCheckDuration = stats.Float64("health/execute_time", "The time it took to execute a checks in ms", "ms")
+	mHealthScore   = stats.Float64("health/score", "The weighted health score (0-100)", "%")
+	mPhaseDuration = stats.Float64("health/phase_execute_time", "The time it took to execute a phase of a check in ms", "ms")
 
 	// ViewCheckExecutionTime is the checks execution time aggregation tagged by check name
 	ViewCheckExecutionTime = &view.View{
@@ -30,6 +33,14 @@ var (
 		Aggregation: view.Distribution(0, 1, 2, 3, 4, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 120, 160, 200, 250, 300, 500),
 	}
 
+	// ViewCheckPhaseExecutionTime is the checks execution time aggregation tagged by check name and phase,
+	// for checks that report the breakdown of their execution duration (see gosundheit.Result.Timings)
+	ViewCheckPhaseExecutionTime = &view.View{
+		Measure:     mPhaseDuration,
+		TagKeys:     []tag.Key{keyCheck, keyPhase, keyClassification},
+		Aggregation: view.Distribution(0, 1, 2, 3, 4, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 120, 160, 200, 250, 300, 500),
+	}
+
 	// ViewCheckCountByNameAndStatus is the checks execution count aggregation grouped by check name, and check status
 	ViewCheckCountByNameAndStatus = &view.View{
 		Name:        "health/check_count_by_name_and_status",
@@ -46,11 +57,21 @@ var (
 		Aggregation: view.LastValue(),
 	}
 
+	// ViewHealthScore is the weighted health score (see gosundheit.Health.Score()) at the time of sampling
+	ViewHealthScore = &view.View{
+		Name:        "health/score",
+		Measure:     mHealthScore,
+		TagKeys:     []tag.Key{keyClassification},
+		Aggregation: view.LastValue(),
+	}
+
 	// DefaultHealthViews are the default health check views provided by this package.
 	DefaultHealthViews = []*view.View{
 		ViewCheckCountByNameAndStatus,
 		ViewCheckStatusByName,
 		ViewCheckExecutionTime,
+		ViewCheckPhaseExecutionTime,
+		ViewHealthScore,
 	}
 )
 
@@ -0,0 +1,158 @@
+// Package openmetrics exposes the health results in the OpenMetrics text format, so `/metrics` can be served
+// from the same results snapshot as the health endpoint, without reporting metrics on each check execution.
+package openmetrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// ContentType is the content type of the OpenMetrics text exposition.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Option configures the Gatherer
+type Option func(*Gatherer)
+
+// WithNamespace sets the prefix of the metric names; defaults to "health".
+func WithNamespace(namespace string) Option {
+	return func(g *Gatherer) {
+		g.namespace = namespace
+	}
+}
+
+// Gatherer converts the current results snapshot of a Health instance into the OpenMetrics text exposition:
+//   - <namespace>_healthy: 1 when the service is healthy, 0 otherwise
+//   - <namespace>_score: the weighted health score (see gosundheit.ScoreOf)
+//   - <namespace>_check_status: 1 when the check passed, 0 otherwise
+//   - <namespace>_check_degraded: 1 when the check passed, but reported a degraded status, 0 otherwise
+//   - <namespace>_check_contiguous_failures: the number of failures that occurred in a row
+//   - <namespace>_check_duration_seconds: the duration of the last execution, once the check was executed
+//   - <namespace>_check_timing_seconds: the duration of each phase of the last execution (labeled by the phase),
+//     when reported by the check (see gosundheit.Result.Timings)
+//   - <namespace>_check_timestamp_seconds: the time of the last execution, once the check was executed
+//
+// The check metrics are labeled by the check name, and by its classification when configured.
+type Gatherer struct {
+	h         gosundheit.Health
+	namespace string
+}
+
+// NewGatherer returns a Gatherer of the given Health instance.
+func NewGatherer(h gosundheit.Health, opts ...Option) *Gatherer {
+	g := &Gatherer{h: h, namespace: "health"}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Gather writes the exposition of the current results to w.
+func (g *Gatherer) Gather(w io.Writer) error {
+	results, healthy := g.h.Results()
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := bufio.NewWriter(w)
+	e := &encoder{w: buf, namespace: g.namespace}
+
+	e.family("healthy", "", "Whether the service is healthy (1) or not (0).")
+	e.sample("healthy", "", boolValue(healthy))
+	e.family("score", "", "The weighted health score (0-100).")
+	e.sample("score", "", gosundheit.ScoreOf(results))
+
+	e.family("check_status", "", "The status of the last execution of the check (1 for pass, 0 for fail).")
+	for _, name := range names {
+		e.sample("check_status", checkLabels(name, results[name]), boolValue(results[name].IsHealthy()))
+	}
+	e.family("check_degraded", "", "Whether the last execution of the check passed, but reported a degraded status.")
+	for _, name := range names {
+		e.sample("check_degraded", checkLabels(name, results[name]), boolValue(results[name].Degraded))
+	}
+	e.family("check_contiguous_failures", "", "The number of failures of the check that occurred in a row.")
+	for _, name := range names {
+		e.sample("check_contiguous_failures", checkLabels(name, results[name]), float64(results[name].ContiguousFailures))
+	}
+	e.family("check_duration_seconds", "seconds", "The duration of the last execution of the check.")
+	for _, name := range names {
+		if results[name].Executed() {
+			e.sample("check_duration_seconds", checkLabels(name, results[name]), results[name].Duration.Seconds())
+		}
+	}
+	e.family("check_timing_seconds", "seconds", "The duration of each phase of the last execution of the check.")
+	for _, name := range names {
+		timings := results[name].Timings
+		phases := make([]string, 0, len(timings))
+		for phase := range timings {
+			phases = append(phases, phase)
+		}
+		sort.Strings(phases)
+		for _, phase := range phases {
+			labels := strings.TrimSuffix(checkLabels(name, results[name]), "}") + `,phase="` + escapeLabel(phase) + `"}`
+			e.sample("check_timing_seconds", labels, timings[phase].Seconds())
+		}
+	}
+	e.family("check_timestamp_seconds", "seconds", "The time of the last execution of the check.")
+	for _, name := range names {
+		if results[name].Executed() {
+			timestamp := float64(results[name].Timestamp.UnixNano()) / 1e9
+			e.sample("check_timestamp_seconds", checkLabels(name, results[name]), timestamp)
+		}
+	}
+
+	_, _ = buf.WriteString("# EOF\n")
+	return buf.Flush()
+}
+
+// ServeHTTP serves the exposition of the current results.
+func (g *Gatherer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = g.Gather(w)
+}
+
+type encoder struct {
+	w         *bufio.Writer
+	namespace string
+}
+
+func (e *encoder) family(name, unit, help string) {
+	name = e.namespace + "_" + name
+	_, _ = e.w.WriteString("# TYPE " + name + " gauge\n")
+	if unit != "" {
+		_, _ = e.w.WriteString("# UNIT " + name + " " + unit + "\n")
+	}
+	_, _ = e.w.WriteString("# HELP " + name + " " + help + "\n")
+}
+
+func (e *encoder) sample(name, labels string, value float64) {
+	_, _ = e.w.WriteString(e.namespace + "_" + name + labels + " " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+func checkLabels(name string, result gosundheit.Result) string {
+	labels := `{check="` + escapeLabel(name) + `"`
+	if result.Classification != "" {
+		labels += `,classification="` + escapeLabel(result.Classification) + `"`
+	}
+	return labels + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package openmetrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+func TestGatherer(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetResult("db", gosundheit.Result{
+		Details:            "connection refused",
+		Error:              errors.New("connection refused"),
+		Timestamp:          time.Unix(1600000000, 500000000),
+		Duration:           12 * time.Millisecond,
+		Timings:            map[string]time.Duration{"query": 10 * time.Millisecond, "connect": 2 * time.Millisecond},
+		ContiguousFailures: 3,
+		Classification:     "storage",
+	})
+	h.SetResult(`cache "main"`, gosundheit.Result{
+		Details:   "slow",
+		Timestamp: time.Unix(1600000001, 0),
+		Duration:  time.Second,
+		Degraded:  true,
+	})
+
+	var buf bytes.Buffer
+	assert.NoError(t, NewGatherer(h).Gather(&buf))
+	assert.Equal(t, `# TYPE health_healthy gauge
+# HELP health_healthy Whether the service is healthy (1) or not (0).
+health_healthy 0
+# TYPE health_score gauge
+# HELP health_score The weighted health score (0-100).
+health_score 50
+# TYPE health_check_status gauge
+# HELP health_check_status The status of the last execution of the check (1 for pass, 0 for fail).
+health_check_status{check="cache \"main\""} 1
+health_check_status{check="db",classification="storage"} 0
+# TYPE health_check_degraded gauge
+# HELP health_check_degraded Whether the last execution of the check passed, but reported a degraded status.
+health_check_degraded{check="cache \"main\""} 1
+health_check_degraded{check="db",classification="storage"} 0
+# TYPE health_check_contiguous_failures gauge
+# HELP health_check_contiguous_failures The number of failures of the check that occurred in a row.
+health_check_contiguous_failures{check="cache \"main\""} 0
+health_check_contiguous_failures{check="db",classification="storage"} 3
+# TYPE health_check_duration_seconds gauge
+# UNIT health_check_duration_seconds seconds
+# HELP health_check_duration_seconds The duration of the last execution of the check.
+health_check_duration_seconds{check="cache \"main\""} 1
+health_check_duration_seconds{check="db",classification="storage"} 0.012
+# TYPE health_check_timing_seconds gauge
+# UNIT health_check_timing_seconds seconds
+# HELP health_check_timing_seconds The duration of each phase of the last execution of the check.
+health_check_timing_seconds{check="db",classification="storage",phase="connect"} 0.002
+health_check_timing_seconds{check="db",classification="storage",phase="query"} 0.01
+# TYPE health_check_timestamp_seconds gauge
+# UNIT health_check_timestamp_seconds seconds
+# HELP health_check_timestamp_seconds The time of the last execution of the check.
+health_check_timestamp_seconds{check="cache \"main\""} 1.600000001e+09
+health_check_timestamp_seconds{check="db",classification="storage"} 1.6000000005e+09
+# EOF
+`, buf.String())
+}
+
+func TestGatherer_ServeHTTP(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+
+	recorder := httptest.NewRecorder()
+	NewGatherer(h, WithNamespace("orders_health")).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "orders_health_healthy 1\n")
+	assert.Contains(t, recorder.Body.String(), `orders_health_check_status{check="db"} 1`+"\n")
+}
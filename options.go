@@ -1,5 +1,9 @@
 package gosundheit
 
+import (
+	"time"
+)
+
 type Option func(*health)
 
 // WithCheckListeners allows you to listen to check start/end events
@@ -16,8 +20,110 @@ func WithHealthListeners(listener ...HealthListener) Option {
 	}
 }
 
+// WithResultEnrichers sets a chain of enrichers, which add environment context to the check results before they are stored,
+// e.g. the deployment version and zone (see AnnotationsEnricher). The enrichers are called in order.
+func WithResultEnrichers(enrichers ...ResultEnricher) Option {
+	return func(h *health) {
+		h.resultEnrichers = enrichers
+	}
+}
+
+// WithLeaderElection allows you to scope checks to the leader instance, using the check Config.LeaderPolicy.
+// isLeader is called on each check execution and health evaluation, so it must be fast and must not block.
+func WithLeaderElection(isLeader func() bool) Option {
+	return func(h *health) {
+		h.isLeader = isLeader
+	}
+}
+
+// WithSharedScheduler executes all the checks from a single scheduling goroutine, instead of a goroutine (and ticker)
+// per check, with at most `workers` concurrent check executions.
+// This reduces the scheduling overhead when registering thousands of checks.
+// When all workers are busy, due checks wait for the next available worker.
+func WithSharedScheduler(workers int) Option {
+	return func(h *health) {
+		h.sharedSchedulerWorkers = workers
+	}
+}
+
+// WithProbeShedding sheds the executions of the non Critical (e.g. Warning) checks while the shared scheduler is saturated,
+// i.e. when all its workers are busy, keeping the Critical checks on schedule under CPU pressure.
+// A shed execution keeps the previous result of the check, which is only marked as shed (see Result.Shed),
+// and doesn't count in the health, the history or the failure streak; the check is rescheduled as usual.
+// Critical checks always execute, waiting for the next available worker. Applies only along with WithSharedScheduler.
+func WithProbeShedding() Option {
+	return func(h *health) {
+		h.probeShedding = true
+	}
+}
+
+// WithPanicQuarantine quarantines the checks that panic the given number of times in a row:
+// a quarantined check is no longer executed, and its result fails with the last panic (see Result.Quarantined),
+// until the check is registered again. A quarantine is notified to the check listeners implementing CheckQuarantinedListener,
+// and emitted as an EventQuarantined event. Panics are always recovered, and fail the execution, either way.
+func WithPanicQuarantine(panics int) Option {
+	return func(h *health) {
+		h.panicQuarantine = int32(panics)
+	}
+}
+
+// WithMaxDetailsSize caps the size of the results details: details whose JSON encoding is longer than maxSize bytes
+// are replaced by TruncatedDetails, holding a prefix of their encoding, so a check returning a huge payload
+// doesn't bloat every copy of the results, and every response. Zero (the default) doesn't cap the details.
+// Capping the details encodes them once per execution.
+func WithMaxDetailsSize(maxSize int) Option {
+	return func(h *health) {
+		h.maxDetailsSize = maxSize
+	}
+}
+
+// WithStrictNames enforces the naming policy on the registered checks (see ValidateName),
+// and rejects checks whose names collide with the names of other checks after normalization (see NormalizeName),
+// e.g. `orders-db` and `Orders_DB`, as metric backends would merge them.
+func WithStrictNames() Option {
+	return func(h *health) {
+		h.strictNames = true
+	}
+}
+
+// WithHistoryEvaluation evaluates the health of each check by its recent results, instead of its last result only:
+// a check affects the health when less than minPassRatio of its last `window` executions passed
+// (e.g. healthy if at least 80% of the last 10 executions passed), which smooths out single blips.
+// The window is capped at 64 executions, and the ratio of each check is reported in Result.RecentPassRatio.
+// Note that a passing check may still affect the health, until enough of its recent executions pass.
+// A window of less than 1, or a ratio that is not in the range (0, 1], disables the history evaluation.
+func WithHistoryEvaluation(window int, minPassRatio float64) Option {
+	return func(h *health) {
+		if window < 1 || minPassRatio <= 0 || minPassRatio > 1 {
+			h.historyEvaluation = nil
+			return
+		}
+		if window > maxHistoryWindow {
+			window = maxHistoryWindow
+		}
+		h.historyEvaluation = &historyEvaluation{window: uint(window), minPassRatio: minPassRatio}
+	}
+}
+
+// WithTimestampSource sets the time source used for the results timestamps and the execution durations;
+// defaults to time.Now.
+// It is independent of the scheduling of the checks, and is mostly useful for making the results deterministic in tests,
+// e.g. when comparing the JSON output to golden files.
+func WithTimestampSource(now func() time.Time) Option {
+	return func(h *health) {
+		h.now = now
+	}
+}
+
 // WithDefaults sets all the Health object settings. It's not required to use this as no options is always default
 // This is a simple placeholder for any future defaults
 func WithDefaults() Option {
-	return func(h *health) {}
+	return func(h *health) {
+		if h.isLeader == nil {
+			h.isLeader = func() bool { return true }
+		}
+		if h.now == nil {
+			h.now = time.Now
+		}
+	}
 }
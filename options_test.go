@@ -0,0 +1,40 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestWithTimestampSource(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	h := New(WithTimestampSource(func() time.Time { return now }))
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: passingCheckName,
+			CheckFunc: func() (details interface{}, err error) { return successMsg, nil },
+		},
+		InitialDelay:    time.Hour,
+		ExecutionPeriod: time.Hour,
+	})
+
+	expected := `{
+	"passing.check": {
+		"message": "didn't run yet",
+		"error": {
+			"message": "didn't run yet"
+		},
+		"timestamp": "2020-01-01T00:00:00Z",
+		"contiguousFailures": 1,
+		"timeOfFirstFailure": "2020-01-01T00:00:00Z",
+		"generation": 1
+	}
+}
+`
+	assert.Equal(t, expected, string(h.ResultsJSON()), "results JSON should be deterministic")
+}
@@ -0,0 +1,32 @@
+package gosundheit
+
+import (
+	"github.com/pkg/errors"
+)
+
+// CheckProvider is implemented by libraries (e.g. database drivers and SDKs) that contribute their own health checks.
+type CheckProvider interface {
+	// HealthChecks returns the configurations of the checks to be registered.
+	HealthChecks() []*Config
+}
+
+// CheckProviderFunc is an adapter to allow the use of ordinary functions as a CheckProvider.
+type CheckProviderFunc func() []*Config
+
+// HealthChecks calls f()
+func (f CheckProviderFunc) HealthChecks() []*Config {
+	return f()
+}
+
+// RegisterAll registers the checks of all the given providers with the given Health instance.
+// Registration stops at the first check that fails to register, and the error is returned.
+func RegisterAll(h Health, providers ...CheckProvider) error {
+	for _, provider := range providers {
+		for _, cfg := range provider.HealthChecks() {
+			if err := h.RegisterCheck(cfg); err != nil {
+				return errors.Wrapf(err, "failed to register check provided by %T", provider)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestRegisterAll(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	provider := CheckProviderFunc(func() []*Config {
+		return []*Config{
+			{
+				Check:           &checks.CustomCheck{CheckName: passingCheckName},
+				ExecutionPeriod: time.Minute,
+			},
+			{
+				Check:           &checks.CustomCheck{CheckName: failingCheckName},
+				ExecutionPeriod: time.Minute,
+			},
+		}
+	})
+	assert.NoError(t, RegisterAll(h, provider))
+	assert.Len(t, h.Checks(), 2, "all provided checks should be registered")
+
+	bogus := CheckProviderFunc(func() []*Config {
+		return []*Config{{Check: &checks.CustomCheck{}}}
+	})
+	err := RegisterAll(h, bogus)
+	assert.Error(t, err, "bogus provided check should fail the registration")
+	assert.Contains(t, err.Error(), "failed to register check provided by gosundheit.CheckProviderFunc")
+}
@@ -0,0 +1,67 @@
+package gosundheit
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// CheckQuarantinedListener is an optional interface, which a CheckListener may implement
+// in order to be notified when checks are quarantined (see WithPanicQuarantine).
+type CheckQuarantinedListener interface {
+	// OnCheckQuarantined is called once the check with the specified name is quarantined, with its quarantined result.
+	OnCheckQuarantined(name string, result Result)
+}
+
+func (c CheckListeners) OnCheckQuarantined(name string, result Result) {
+	for _, listener := range c {
+		if l, ok := listener.(CheckQuarantinedListener); ok {
+			l.OnCheckQuarantined(name, result)
+		}
+	}
+}
+
+// panicError is the error of a check execution that panicked
+type panicError struct {
+	value interface{}
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("check panicked: %v", e.value)
+}
+
+func panicked(outcome checks.ExecutionResult) bool {
+	_, ok := outcome.Err.(*panicError)
+	return ok
+}
+
+// recordPanics counts the consecutive executions that panicked, and returns their number
+func (t *checkTask) recordPanics(outcome checks.ExecutionResult) int32 {
+	if !panicked(outcome) {
+		atomic.StoreInt32(&t.panics, 0)
+		return 0
+	}
+	return atomic.AddInt32(&t.panics, 1)
+}
+
+func (t *checkTask) isQuarantined() bool {
+	return atomic.LoadInt32(&t.quarantined) == 1
+}
+
+// quarantine stops scheduling the task, and records its quarantined result, once it panicked too many times in a row.
+// The task stays registered, until it is deregistered or replaced.
+func (h *health) quarantine(
+	task *checkTask, outcome checks.ExecutionResult, panics int32, duration time.Duration, checkTime time.Time) {
+
+	atomic.StoreInt32(&task.quarantined, 1)
+	outcome.Err = errors.Errorf("check quarantined after %d consecutive panics: %v", panics, outcome.Err)
+	if result, ok := h.updateResult(task, outcome, duration, checkTime); ok {
+		h.scheduler.unschedule(task)
+		h.checksListener.OnCheckCompleted(task.check.Name(), result)
+		h.checksListener.OnCheckQuarantined(task.check.Name(), result)
+	}
+}
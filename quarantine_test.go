@@ -0,0 +1,136 @@
+package gosundheit
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func panickingCheck(name string, executions *int32, panics func(execution int32) bool) checks.Check {
+	return &checks.CustomCheck{
+		CheckName: name,
+		CheckFunc: func() (details interface{}, err error) {
+			if execution := atomic.AddInt32(executions, 1); panics(execution) {
+				panic("boom")
+			}
+			return "ok", nil
+		},
+	}
+}
+
+func TestPanicsAreRecovered(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	var executions int32
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           panickingCheck("db", &executions, func(int32) bool { return true }),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+
+	for i := 0; i < 3; i++ {
+		result, err := h.Trigger("db")
+		assert.NoError(t, err)
+		assert.EqualError(t, result.Error, "check panicked: boom")
+		assert.False(t, result.Quarantined, "checks aren't quarantined by default")
+	}
+}
+
+func TestPanicQuarantine(t *testing.T) {
+	h := New(WithPanicQuarantine(2))
+	defer h.DeregisterAll()
+	events := h.Events()
+
+	var executions int32
+	cfg := &Config{
+		// the second execution passes, so the third doesn't quarantine the check
+		Check:           panickingCheck("db", &executions, func(execution int32) bool { return execution != 2 }),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}
+	assert.NoError(t, h.RegisterCheck(cfg))
+
+	for _, expected := range []string{"check panicked: boom", "", "check panicked: boom"} {
+		result, err := h.Trigger("db")
+		assert.NoError(t, err)
+		assert.False(t, result.Quarantined)
+		if expected == "" {
+			assert.NoError(t, result.Error)
+		} else {
+			assert.EqualError(t, result.Error, expected)
+		}
+	}
+
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.True(t, result.Quarantined)
+	assert.EqualError(t, result.Error, "check quarantined after 2 consecutive panics: check panicked: boom")
+	assert.False(t, h.IsHealthy())
+
+	var quarantined []Event
+	for len(events) > 0 {
+		if event := <-events; event.Type == EventQuarantined {
+			quarantined = append(quarantined, event)
+		}
+	}
+	if assert.Len(t, quarantined, 1) {
+		assert.Equal(t, "db", quarantined[0].Check)
+		assert.True(t, quarantined[0].Result.Quarantined)
+	}
+
+	_, err = h.Trigger("db")
+	assert.EqualError(t, err, "check db is quarantined")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&executions))
+
+	// registering the check again releases it
+	assert.NoError(t, h.RegisterCheck(cfg))
+	result, err = h.Trigger("db")
+	assert.NoError(t, err)
+	assert.False(t, result.Quarantined)
+}
+
+func TestPanicQuarantine_stopsScheduling(t *testing.T) {
+	for name, opt := range map[string]Option{"goroutine scheduler": func(*health) {}, "shared scheduler": WithSharedScheduler(2)} {
+		t.Run(name, func(t *testing.T) {
+			quarantined := make(chan Result, 1)
+			h := New(opt, WithPanicQuarantine(3), WithCheckListeners(&quarantineListener{quarantined: quarantined}))
+
+			var executions int32
+			assert.NoError(t, h.RegisterCheck(&Config{
+				Check:           panickingCheck("db", &executions, func(int32) bool { return true }),
+				ExecutionPeriod: time.Millisecond,
+			}))
+
+			select {
+			case result := <-quarantined:
+				assert.True(t, result.Quarantined)
+			case <-time.After(time.Second):
+				t.Fatal("the check wasn't quarantined")
+			}
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&executions), "quarantined checks aren't executed")
+
+			// deregistering the quarantined check doesn't unschedule it again
+			h.DeregisterAll()
+		})
+	}
+}
+
+type quarantineListener struct {
+	quarantined chan Result
+}
+
+func (l *quarantineListener) OnCheckRegistered(string, Result) {}
+
+func (l *quarantineListener) OnCheckStarted(string) {}
+
+func (l *quarantineListener) OnCheckCompleted(string, Result) {}
+
+func (l *quarantineListener) OnCheckQuarantined(_ string, result Result) {
+	l.quarantined <- result
+}
@@ -0,0 +1,58 @@
+package gosundheit
+
+import (
+	"sync"
+)
+
+// DefaultName is the name of the default Health instance, as returned by Default()
+const DefaultName = "default"
+
+var registry = struct {
+	lock      sync.Mutex
+	instances map[string]Health
+}{instances: make(map[string]Health)}
+
+// Named returns the package level Health instance with the given name, and creates it on first use.
+// Named instances allow libraries to register checks without plumbing a Health instance through every constructor,
+// while keeping the checks of different subsystems (e.g. "ingest" and "query") apart.
+func Named(name string) Health {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	h, ok := registry.instances[name]
+	if !ok {
+		h = New()
+		registry.instances[name] = h
+	}
+	return h
+}
+
+// Default returns the default package level Health instance, which is used by the package level helpers.
+func Default() Health {
+	return Named(DefaultName)
+}
+
+// RegisterCheck registers a health check with the default Health instance.
+func RegisterCheck(cfg *Config) error {
+	return Default().RegisterCheck(cfg)
+}
+
+// RegisterChecks registers health checks with the default Health instance, all or nothing.
+func RegisterChecks(cfgs ...*Config) error {
+	return Default().RegisterChecks(cfgs...)
+}
+
+// Deregister removes a health check from the default Health instance.
+func Deregister(name string) {
+	Default().Deregister(name)
+}
+
+// Results returns the results of the default Health instance, and its current health.
+func Results() (results map[string]Result, healthy bool) {
+	return Default().Results()
+}
+
+// IsHealthy returns the current health of the default Health instance.
+func IsHealthy() bool {
+	return Default().IsHealthy()
+}
@@ -0,0 +1,35 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	ingest := Named("ingest")
+	assert.True(t, ingest == Named("ingest"), "the same named instance should be returned")
+	assert.False(t, ingest == Named("query"), "different names should yield different instances")
+	assert.True(t, Default() == Named(DefaultName), "the default instance is named")
+}
+
+func TestDefault(t *testing.T) {
+	defer Default().DeregisterAll()
+
+	registerCheck(Default(), passingCheckName, true, false)
+	results, healthy := Results()
+	assert.Contains(t, results, passingCheckName, "results of the default instance")
+	assert.False(t, healthy, "health before first execution")
+	assert.False(t, IsHealthy(), "health before first execution")
+
+	// await first execution
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, IsHealthy(), "health after first execution")
+
+	Deregister(passingCheckName)
+	// await stop
+	time.Sleep(10 * time.Millisecond)
+	results, _ = Results()
+	assert.Empty(t, results, "results after deregistration")
+}
@@ -0,0 +1,49 @@
+package gosundheit
+
+// ResultEnricher adds environment context to the check results before they are stored (e.g. the deployment version,
+// zone or correlation IDs), so the JSON output, listeners and exporters carry it.
+type ResultEnricher interface {
+	// Enrich is called with each result of the named check, including its initial result, before the result is stored.
+	// The result annotations are a copy, which the enricher may modify; other fields that affect the health
+	// (e.g. the error) should be left unmodified.
+	// Enrich is called while the result is locked, so it must be fast and must not block.
+	Enrich(check string, result *Result)
+}
+
+// ResultEnricherFunc type is an adapter to allow the use of ordinary functions as ResultEnrichers.
+type ResultEnricherFunc func(check string, result *Result)
+
+// Enrich calls f(check, result).
+func (f ResultEnricherFunc) Enrich(check string, result *Result) {
+	f(check, result)
+}
+
+// AnnotationsEnricher returns a ResultEnricher that adds the given annotations to all the results,
+// without overriding the annotations configured in the check Config.
+func AnnotationsEnricher(annotations map[string]string) ResultEnricher {
+	return ResultEnricherFunc(func(_ string, result *Result) {
+		for key, value := range annotations {
+			if _, ok := result.Annotations[key]; !ok {
+				result.Annotations[key] = value
+			}
+		}
+	})
+}
+
+type resultEnrichers []ResultEnricher
+
+// enrich runs the enrichers chain, in order, on a result whose annotations are copied first.
+func (e resultEnrichers) enrich(check string, result *Result) {
+	if len(e) == 0 {
+		return
+	}
+
+	annotations := make(map[string]string, len(result.Annotations))
+	for key, value := range result.Annotations {
+		annotations[key] = value
+	}
+	result.Annotations = annotations
+	for _, enricher := range e {
+		enricher.Enrich(check, result)
+	}
+}
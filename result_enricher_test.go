@@ -0,0 +1,55 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestWithResultEnrichers(t *testing.T) {
+	var enriched []string
+	h := New(WithResultEnrichers(
+		AnnotationsEnricher(map[string]string{"version": "1.2.3", "zone": "us-east-1a"}),
+		ResultEnricherFunc(func(check string, result *Result) {
+			enriched = append(enriched, check)
+			result.Annotations["correlation"] = "abc"
+		}),
+	))
+	defer h.DeregisterAll()
+
+	annotations := map[string]string{"zone": "configured"}
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "enriched.check"},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+		Annotations:     annotations,
+	}))
+
+	results, _ := h.Results()
+	assert.Equal(t, map[string]string{
+		"version":     "1.2.3",
+		"zone":        "configured",
+		"correlation": "abc",
+	}, results["enriched.check"].Annotations, "configured annotations are not overridden")
+	assert.Equal(t, map[string]string{"zone": "configured"}, annotations, "the config annotations are not modified")
+	assert.Equal(t, []string{"enriched.check"}, enriched, "the initial result is enriched")
+
+	var decoded map[string]struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	assert.NoError(t, json.Unmarshal(h.ResultsJSON(), &decoded))
+	assert.Equal(t, "1.2.3", decoded["enriched.check"].Annotations["version"], "the JSON output carries the enriched fields")
+}
+
+func TestResultEnrichers_none(t *testing.T) {
+	annotations := map[string]string{"zone": "configured"}
+	result := Result{Annotations: annotations}
+	resultEnrichers(nil).enrich("check", &result)
+	assert.Equal(t, map[string]string{"zone": "configured"}, result.Annotations)
+	result.Annotations["zone"] = "shared"
+	assert.Equal(t, "shared", annotations["zone"], "annotations are not copied without enrichers")
+}
@@ -0,0 +1,83 @@
+package gosundheit
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+const resultsShards = 32
+
+// resultsStore is a results map sharded by check name, so concurrent result updates of different checks
+// don't contend on a single lock with each other, nor with the readers.
+type resultsStore struct {
+	shards [resultsShards]resultsShard
+}
+
+type resultsShard struct {
+	lock    sync.RWMutex
+	results map[string]Result
+}
+
+func newResultsStore() *resultsStore {
+	s := &resultsStore{}
+	for i := range s.shards {
+		s.shards[i].results = make(map[string]Result, maxExpectedChecks/resultsShards+1)
+	}
+	return s
+}
+
+func (s *resultsStore) shard(name string) *resultsShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return &s.shards[h.Sum32()%resultsShards]
+}
+
+// update atomically replaces the named result with the one returned by the update function,
+// which is given the previous result, if any.
+func (s *resultsStore) update(name string, updateFn func(prev Result, exists bool) Result) Result {
+	shard := s.shard(name)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	prev, exists := shard.results[name]
+	result := updateFn(prev, exists)
+	shard.results[name] = result
+	return result
+}
+
+// delete removes the named result, and returns the removed result, if any.
+func (s *resultsStore) delete(name string) (removed Result, existed bool) {
+	shard := s.shard(name)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	removed, existed = shard.results[name]
+	delete(shard.results, name)
+	return removed, existed
+}
+
+// forEach calls fn for each result, until fn returns false.
+// Each shard is read locked while it is iterated, so fn must not update the store.
+func (s *resultsStore) forEach(fn func(name string, result Result) bool) {
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.lock.RLock()
+		for name, result := range shard.results {
+			if !fn(name, result) {
+				shard.lock.RUnlock()
+				return
+			}
+		}
+		shard.lock.RUnlock()
+	}
+}
+
+// copy returns a copy of all the results in the store.
+func (s *resultsStore) copy() map[string]Result {
+	results := make(map[string]Result, maxExpectedChecks)
+	s.forEach(func(name string, result Result) bool {
+		results[name] = result
+		return true
+	})
+	return results
+}
@@ -0,0 +1,59 @@
+package gosundheit
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultsStore(t *testing.T) {
+	s := newResultsStore()
+
+	result := s.update("check", func(prev Result, exists bool) Result {
+		assert.False(t, exists, "first update has no previous result")
+		return Result{ContiguousFailures: 1}
+	})
+	assert.Equal(t, int64(1), result.ContiguousFailures)
+
+	result = s.update("check", func(prev Result, exists bool) Result {
+		assert.True(t, exists, "second update has a previous result")
+		prev.ContiguousFailures++
+		return prev
+	})
+	assert.Equal(t, int64(2), result.ContiguousFailures)
+	assert.Equal(t, map[string]Result{"check": {ContiguousFailures: 2}}, s.copy())
+
+	s.delete("check")
+	assert.Empty(t, s.copy(), "results after delete")
+}
+
+func TestResultsStore_concurrentAccess(t *testing.T) {
+	s := newResultsStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				s.update(fmt.Sprintf("check.%d", i), func(prev Result, _ bool) Result {
+					prev.ContiguousFailures++
+					return prev
+				})
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			s.forEach(func(string, Result) bool { return true })
+		}()
+	}
+	wg.Wait()
+
+	results := s.copy()
+	assert.Equal(t, 100, len(results), "num results")
+	for name, r := range results {
+		assert.Equal(t, int64(10), r.ContiguousFailures, "updates of %s", name)
+	}
+}
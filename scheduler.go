@@ -0,0 +1,79 @@
+package gosundheit
+
+import (
+	"time"
+)
+
+// scheduler schedules the recurring execution of check tasks.
+type scheduler interface {
+	// schedule starts the recurring execution of the task, according to the task's config.
+	schedule(task *checkTask)
+	// unschedule stops the next executions of the task; the task and its results are removed by the health beforehand.
+	// unschedule is called at most once per task, and marks the task.active done once the task is no longer executing.
+	unschedule(task *checkTask)
+}
+
+// nextRun returns the time of the next execution, given the current period of the task (see checkTask.period),
+// the scheduled time of the previous execution, and its completion time.
+func nextRun(cfg *Config, period time.Duration, scheduled, completed time.Time) time.Time {
+	if cfg.SchedulingMode == FixedDelay {
+		return completed.Add(period)
+	}
+
+	next := scheduled.Add(period)
+	if next.Before(completed) {
+		// skip the missed executions, while keeping the original schedule
+		missed := completed.Sub(next)/period + 1
+		next = next.Add(missed * period)
+	}
+	return next
+}
+
+// goroutineScheduler executes each check task in its own goroutine, using a dedicated timer.
+type goroutineScheduler struct {
+	h *health
+}
+
+func (s *goroutineScheduler) schedule(task *checkTask) {
+	go func() {
+		defer task.active.Done()
+
+		scheduled := time.Now().Add(task.cfg.InitialDelay)
+		timer := time.NewTimer(task.cfg.InitialDelay)
+		defer stopTimer(timer)
+
+		for {
+			select {
+			case <-task.stopChan:
+				return
+			case <-timer.C:
+				// the task may have been unscheduled while the timer fired
+				select {
+				case <-task.stopChan:
+					return
+				default:
+				}
+
+				s.h.schedulerLag.record(time.Since(scheduled))
+				s.h.runTask(task)
+				scheduled = nextRun(task.cfg, task.period(), scheduled, time.Now())
+				timer.Reset(time.Until(scheduled))
+			}
+		}
+	}()
+}
+
+func (s *goroutineScheduler) unschedule(task *checkTask) {
+	// the task go routine stops once it is done executing
+	task.stop()
+}
+
+// stopTimer stops the timer, and drains its channel when it has already fired.
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gosundheit
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// lagTracker tracks the maximal lag of the scheduler ticks, i.e. how late check executions start compared to their schedule.
+type lagTracker struct {
+	max int64
+}
+
+// record accounts for the lag of a single tick.
+func (l *lagTracker) record(lag time.Duration) {
+	for {
+		current := atomic.LoadInt64(&l.max)
+		if int64(lag) <= current || atomic.CompareAndSwapInt64(&l.max, current, int64(lag)) {
+			return
+		}
+	}
+}
+
+// reset returns the maximal lag recorded since the previous reset.
+func (l *lagTracker) reset() time.Duration {
+	return time.Duration(atomic.SwapInt64(&l.max, 0))
+}
+
+// SchedulerLagDetails are the details reported by the scheduler lag check.
+type SchedulerLagDetails struct {
+	// MaxLag is the maximal lag of the scheduler ticks since the previous execution of the check.
+	MaxLag string `json:"max_lag"`
+}
+
+// NewSchedulerLagCheck returns a check that measures how late the scheduler ticks of the given health instance fire,
+// as a proxy for CPU starvation (e.g. CPU throttling or GC pauses), and fails when the maximal lag since its previous
+// execution exceeds maxLag. The check measures the ticks of all the checks registered in the instance, including its own.
+// h must be created by New.
+func NewSchedulerLagCheck(name string, h Health, maxLag time.Duration) (checks.Check, error) {
+	impl, ok := h.(*health)
+	if !ok {
+		return nil, errors.Errorf("scheduler lag is measured only for health instances created by New")
+	}
+	if maxLag <= 0 {
+		return nil, errors.Errorf("maxLag must be positive")
+	}
+
+	return &checks.CustomCheck{
+		CheckName: name,
+		CheckFunc: func() (details interface{}, err error) {
+			lag := impl.schedulerLag.reset()
+			details = SchedulerLagDetails{MaxLag: lag.String()}
+			if lag > maxLag {
+				return details, errors.Errorf("scheduler ticks fired up to %v late, exceeding %v", lag, maxLag)
+			}
+			return details, nil
+		},
+	}, nil
+}
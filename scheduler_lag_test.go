@@ -0,0 +1,70 @@
+package gosundheit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLagTracker(t *testing.T) {
+	var tracker lagTracker
+	tracker.record(time.Millisecond)
+	tracker.record(5 * time.Millisecond)
+	tracker.record(2 * time.Millisecond)
+	assert.Equal(t, 5*time.Millisecond, tracker.reset(), "max lag since the previous reset")
+	assert.Equal(t, time.Duration(0), tracker.reset(), "lag is reset")
+}
+
+func TestNewSchedulerLagCheck(t *testing.T) {
+	_, err := NewSchedulerLagCheck("scheduler.lag", New(), 0)
+	assert.Error(t, err, "non positive max lag should yield error")
+
+	h := New()
+	defer h.DeregisterAll()
+	check, err := NewSchedulerLagCheck("scheduler.lag", h, 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "scheduler.lag", check.Name(), "check name")
+
+	details, err := check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, SchedulerLagDetails{MaxLag: "0s"}, details)
+
+	h.(*health).schedulerLag.record(80 * time.Millisecond)
+	details, err = check.Execute()
+	assert.EqualError(t, err, "scheduler ticks fired up to 80ms late, exceeding 50ms")
+	assert.Equal(t, SchedulerLagDetails{MaxLag: "80ms"}, details)
+
+	_, err = check.Execute()
+	assert.NoError(t, err, "lag is measured since the previous execution")
+}
+
+func TestSchedulerLag(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"goroutine scheduler": nil,
+		"shared scheduler":    {WithSharedScheduler(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := New(opts...)
+			defer h.DeregisterAll()
+
+			check, err := NewSchedulerLagCheck("scheduler.lag", h, time.Second)
+			assert.NoError(t, err)
+			assert.NoError(t, h.RegisterCheck(&Config{
+				Check:           check,
+				ExecutionPeriod: 10 * time.Millisecond,
+			}))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			assert.NoError(t, h.WaitForHealthy(ctx))
+
+			time.Sleep(50 * time.Millisecond)
+			results, _ := h.Results()
+			assert.True(t, results["scheduler.lag"].IsHealthy())
+			assert.NotEqual(t, "0s", results["scheduler.lag"].Details.(SchedulerLagDetails).MaxLag,
+				"scheduler ticks lag should be measured")
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package gosundheit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRun(t *testing.T) {
+	scheduled := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := 10 * time.Second
+	fixedRate := &Config{ExecutionPeriod: period}
+	fixedDelay := &Config{ExecutionPeriod: period, SchedulingMode: FixedDelay}
+
+	assert.Equal(t, scheduled.Add(period), nextRun(fixedRate, period, scheduled, scheduled.Add(time.Second)),
+		"fixed rate is measured from the scheduled time")
+	assert.Equal(t, scheduled.Add(3*period), nextRun(fixedRate, period, scheduled, scheduled.Add(25*time.Second)),
+		"fixed rate skips missed executions")
+	assert.Equal(t, scheduled.Add(period), nextRun(fixedRate, period, scheduled, scheduled.Add(period)),
+		"fixed rate does not skip an execution scheduled on completion time")
+
+	assert.Equal(t, scheduled.Add(11*time.Second), nextRun(fixedDelay, period, scheduled, scheduled.Add(time.Second)),
+		"fixed delay is measured from the completion time")
+	assert.Equal(t, scheduled.Add(35*time.Second), nextRun(fixedDelay, period, scheduled, scheduled.Add(25*time.Second)),
+		"fixed delay is measured from the completion time")
+
+	assert.Equal(t, scheduled.Add(2*period), nextRun(fixedRate, 2*period, scheduled, scheduled.Add(time.Second)),
+		"the given period overrides the configured period")
+}
@@ -0,0 +1,68 @@
+package gosundheit
+
+const maxScore = 100
+
+// ScoreOf returns the weighted health score (0-100) of the given results,
+// i.e. the percentage of the total weight of the results which don't affect the health (see Result.AffectsHealth()).
+// Results with no weight count with a weight of 1. The score of no results is 100.
+func ScoreOf(results map[string]Result) float64 {
+	var total, passing float64
+	for _, result := range results {
+		weight := effectiveWeight(result.Weight)
+		total += weight
+		if !result.AffectsHealth() {
+			passing += weight
+		}
+	}
+	return score(passing, total)
+}
+
+func effectiveWeight(weight float64) float64 {
+	if weight == 0 {
+		return 1
+	}
+	return weight
+}
+
+func score(passing, total float64) float64 {
+	if total == 0 {
+		return maxScore
+	}
+	return maxScore * passing / total
+}
+
+// weights holds the total weight of the results, and of the results which don't affect the health, split by leader only checks.
+type weights struct {
+	total             float64
+	passing           float64
+	leaderOnlyTotal   float64
+	leaderOnlyPassing float64
+}
+
+func (w *weights) add(result Result, leaderOnly bool) {
+	weight := effectiveWeight(result.Weight)
+	if leaderOnly {
+		w.leaderOnlyTotal += weight
+		if !result.AffectsHealth() {
+			w.leaderOnlyPassing += weight
+		}
+		return
+	}
+
+	w.total += weight
+	if !result.AffectsHealth() {
+		w.passing += weight
+	}
+}
+
+// score returns the health score, ignoring leader only checks on non leader instances.
+func (w *weights) score(leader bool) float64 {
+	if leader {
+		return score(w.passing+w.leaderOnlyPassing, w.total+w.leaderOnlyTotal)
+	}
+	return score(w.passing, w.total)
+}
+
+func (h *health) Score() float64 {
+	return h.currentSnapshot().weights.score(h.isLeader())
+}
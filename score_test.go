@@ -0,0 +1,96 @@
+package gosundheit
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestScoreOf(t *testing.T) {
+	assert.Equal(t, float64(100), ScoreOf(nil), "score of no results")
+
+	failed := errors.New("failed")
+	assert.Equal(t, float64(50), ScoreOf(map[string]Result{
+		"passing": {},
+		"failing": {Error: failed},
+	}), "default weights")
+	assert.Equal(t, float64(75), ScoreOf(map[string]Result{
+		"passing": {Weight: 3},
+		"failing": {Error: failed},
+	}), "custom weights")
+	assert.Equal(t, float64(100), ScoreOf(map[string]Result{
+		"silenced":   {Error: failed, Silenced: true},
+		"in.grace":   {Error: failed, InGracePeriod: true},
+		"not.leader": {Error: failed, notLeader: true},
+	}), "failures not affecting the health")
+}
+
+func TestScore(t *testing.T) {
+	leader := int32(1)
+	h := New(WithLeaderElection(func() bool { return atomic.LoadInt32(&leader) == 1 }))
+	defer h.DeregisterAll()
+
+	assert.Equal(t, float64(100), h.Score(), "score with no checks")
+
+	register := func(name string, passing bool, weight float64, policy LeaderPolicy) {
+		err := h.RegisterCheck(&Config{
+			Check: &checks.CustomCheck{
+				CheckName: name,
+				CheckFunc: func() (details interface{}, err error) {
+					if !passing {
+						err = errors.New(failedMsg)
+					}
+					return
+				},
+			},
+			ExecutionPeriod: time.Hour,
+			Weight:          weight,
+			LeaderPolicy:    policy,
+		})
+		assert.NoError(t, err)
+	}
+	register("passing.heavy", true, 3, AnyInstance)
+	register("failing.light", false, 0, AnyInstance)
+	register("failing.leader", false, 4, LeaderOnlyHealth)
+
+	// await first execution
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, float64(3*100)/8, h.Score(), "score on the leader")
+	atomic.StoreInt32(&leader, 0)
+	assert.Equal(t, float64(75), h.Score(), "score on non leaders should ignore leader only checks")
+
+	err := h.RegisterCheck(&Config{Check: &checks.CustomCheck{CheckName: "negative"}, ExecutionPeriod: time.Minute, Weight: -1})
+	assert.Error(t, err, "negative weight should fail the registration")
+}
+
+func TestScore_matchesHealth(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterChecks(
+		&Config{
+			Check:           checks.NewScriptedCheck("warning.check", checks.FailResult(errors.New(failedMsg))),
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+			Severity:        Warning,
+		},
+		&Config{
+			Check:           checks.NewScriptedCheck("new.check", checks.FailResult(errors.New(failedMsg))),
+			ExecutionPeriod: time.Hour,
+			InitialDelay:    time.Hour,
+			GracePeriod:     time.Hour,
+		},
+	))
+	_, _ = h.Trigger("warning.check")
+	_, _ = h.Trigger("new.check")
+
+	assert.True(t, h.IsHealthy())
+	assert.Equal(t, float64(100), h.Score(), "failures not affecting the health don't lower the score")
+	results, _ := h.Results()
+	assert.Equal(t, float64(100), ScoreOf(results))
+}
@@ -0,0 +1,274 @@
+// Package sentry reports check failures and recoveries to Sentry compatible error trackers,
+// so failing checks appear next to the application errors.
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	eventsBuffer   = 64
+)
+
+// Event levels
+const (
+	LevelError = "error"
+	LevelInfo  = "info"
+)
+
+// Event is a check failure, or recovery, event.
+type Event struct {
+	// Check is the name of the check
+	Check string
+	// Level is LevelError for failures, and LevelInfo for recoveries
+	Level string
+	// Message is the event message, e.g. "check db failed: connection refused"
+	Message string
+	// Fingerprint groups the events of the same check into a single issue
+	Fingerprint []string
+	// Tags are the check classification and owner, when configured
+	Tags map[string]string
+	// Extra are the check details, the number of contiguous failures, and the runbook URL when configured
+	Extra map[string]interface{}
+	// Timestamp is the time of the check result
+	Timestamp time.Time
+}
+
+// Reporter reports events to the error tracker.
+// NewDSNReporter returns a Reporter of the Sentry HTTP API; other trackers (or the sentry-go client) can be plugged in using a thin adapter.
+type Reporter interface {
+	Report(ctx context.Context, event Event) error
+}
+
+// ReporterFunc is an adapter to allow the use of ordinary functions as Reporters.
+type ReporterFunc func(ctx context.Context, event Event) error
+
+// Report calls f(ctx, event)
+func (f ReporterFunc) Report(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+type dsnReporter struct {
+	endpoint string
+	auth     string
+	client   *http.Client
+}
+
+// NewDSNReporter returns a Reporter that sends the events to the store API of the project identified by the given DSN,
+// e.g. "https://public-key@sentry.example.org/42". A nil client defaults to http.DefaultClient.
+func NewDSNReporter(dsn string, client *http.Client) (Reporter, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid DSN")
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, errors.Errorf("DSN must include the public key")
+	}
+	slash := strings.LastIndex(parsed.Path, "/")
+	if slash < 0 || parsed.Path[slash+1:] == "" {
+		return nil, errors.Errorf("DSN must include the project ID")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &dsnReporter{
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, parsed.Path[:slash], parsed.Path[slash+1:]),
+		auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-sundheit, sentry_key=%s", parsed.User.Username()),
+		client:   client,
+	}, nil
+}
+
+func (r *dsnReporter) Report(ctx context.Context, event Event) error {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return errors.Wrap(err, "failed to generate the event ID")
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"event_id":    hex.EncodeToString(id[:]),
+		"timestamp":   event.Timestamp.UTC().Format(time.RFC3339Nano),
+		"platform":    "go",
+		"logger":      "go-sundheit",
+		"level":       event.Level,
+		"message":     event.Message,
+		"fingerprint": event.Fingerprint,
+		"tags":        event.Tags,
+		"extra":       event.Extra,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the event")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send the event")
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("event rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Option configures the Listener
+type Option func(*Listener)
+
+// WithTimeout sets the timeout of reporting an event; defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(l *Listener) {
+		l.timeout = timeout
+	}
+}
+
+// WithErrorHandler sets a handler of the reporting errors, which are discarded by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Listener) {
+		l.onError = handler
+	}
+}
+
+// Listener is a gosundheit.HealthListener that reports the first failure of each check, and its recovery.
+// Silenced failures, and failures within the grace period, aren't reported. Events are reported in the background,
+// so the checks are never blocked by the error tracker; once the events buffer is full, the oldest events are dropped.
+type Listener struct {
+	reporter Reporter
+	timeout  time.Duration
+	onError  func(error)
+
+	lock    sync.Mutex
+	failing map[string]bool
+	events  chan Event
+	closed  bool
+	done    chan struct{}
+}
+
+// NewListener returns a Listener reporting using the given reporter, which should be closed once the health is no longer used.
+func NewListener(reporter Reporter, opts ...Option) *Listener {
+	l := &Listener{
+		reporter: reporter,
+		timeout:  defaultTimeout,
+		onError:  func(error) {},
+		failing:  make(map[string]bool),
+		events:   make(chan Event, eventsBuffer),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	go l.report()
+	return l
+}
+
+// OnResultsUpdated enqueues the failure events of newly failing checks, and the recovery events of recovered checks.
+func (l *Listener) OnResultsUpdated(results map[string]gosundheit.Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+
+	for name := range l.failing {
+		if _, ok := results[name]; !ok {
+			delete(l.failing, name)
+		}
+	}
+	for name, result := range results {
+		if !result.Executed() {
+			continue
+		}
+		failing := !result.IsHealthy() && !result.Silenced && !result.InGracePeriod
+		switch {
+		case failing && !l.failing[name]:
+			l.failing[name] = true
+			l.enqueue(eventOf(name, result, LevelError, fmt.Sprintf("check %s failed: %v", name, result.Error)))
+		case !failing && l.failing[name]:
+			delete(l.failing, name)
+			l.enqueue(eventOf(name, result, LevelInfo, fmt.Sprintf("check %s recovered", name)))
+		}
+	}
+}
+
+func eventOf(name string, result gosundheit.Result, level, message string) Event {
+	event := Event{
+		Check:       name,
+		Level:       level,
+		Message:     message,
+		Fingerprint: []string{"go-sundheit", name},
+		Tags:        map[string]string{"check": name},
+		Extra:       map[string]interface{}{"contiguousFailures": result.ContiguousFailures},
+		Timestamp:   result.Timestamp,
+	}
+	if result.Classification != "" {
+		event.Tags["classification"] = result.Classification
+	}
+	if result.Owner != "" {
+		event.Tags["owner"] = result.Owner
+	}
+	if result.Details != nil {
+		event.Extra["details"] = result.Details
+	}
+	if result.RunbookURL != "" {
+		event.Extra["runbookUrl"] = result.RunbookURL
+	}
+	return event
+}
+
+// enqueue adds the event to the queue, dropping the oldest event when the queue is full. Callers must hold the lock.
+func (l *Listener) enqueue(event Event) {
+	for {
+		select {
+		case l.events <- event:
+			return
+		default:
+			select {
+			case <-l.events:
+			default:
+			}
+		}
+	}
+}
+
+func (l *Listener) report() {
+	defer close(l.done)
+	for event := range l.events {
+		ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+		if err := l.reporter.Report(ctx, event); err != nil {
+			l.onError(err)
+		}
+		cancel()
+	}
+}
+
+// Close stops accepting results updates, and returns once the pending events are reported.
+func (l *Listener) Close() {
+	l.lock.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.events)
+	}
+	l.lock.Unlock()
+	<-l.done
+}
@@ -0,0 +1,132 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+func TestListener(t *testing.T) {
+	var events []Event
+	listener := NewListener(ReporterFunc(func(_ context.Context, event Event) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	now := time.Now()
+	down := errors.New("down")
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Details: "didn't run yet", Error: down},
+		"cache": {Timestamp: now},
+	})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 1, Timestamp: now, Classification: "storage", Owner: "team-db"},
+		"cache": {Error: down, Silenced: true, ContiguousFailures: 1},
+	})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Error: down, ContiguousFailures: 2},
+		"cache": {Error: down, InGracePeriod: true, ContiguousFailures: 2},
+	})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{
+		"db":    {Details: "ok", Timestamp: now},
+		"cache": {},
+	})
+	listener.Close()
+
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: down}})
+
+	if assert.Len(t, events, 2, "only the first failure and the recovery are reported") {
+		assert.Equal(t, Event{
+			Check:       "db",
+			Level:       LevelError,
+			Message:     "check db failed: down",
+			Fingerprint: []string{"go-sundheit", "db"},
+			Tags:        map[string]string{"check": "db", "classification": "storage", "owner": "team-db"},
+			Extra:       map[string]interface{}{"contiguousFailures": int64(1)},
+			Timestamp:   now,
+		}, events[0])
+		assert.Equal(t, LevelInfo, events[1].Level)
+		assert.Equal(t, "check db recovered", events[1].Message)
+		assert.Equal(t, events[0].Fingerprint, events[1].Fingerprint)
+		assert.Equal(t, "ok", events[1].Extra["details"])
+	}
+}
+
+func TestListener_deregisteredCheck(t *testing.T) {
+	var events []Event
+	listener := NewListener(ReporterFunc(func(_ context.Context, event Event) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("down")}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("down")}})
+	listener.Close()
+
+	assert.Len(t, events, 2, "the failure of a re-registered check is reported again")
+}
+
+func TestListener_errors(t *testing.T) {
+	errs := make(chan error, 1)
+	listener := NewListener(ReporterFunc(func(context.Context, Event) error {
+		return errors.New("tracker unavailable")
+	}), WithErrorHandler(func(err error) { errs <- err }))
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Error: errors.New("down")}})
+	listener.Close()
+
+	assert.EqualError(t, <-errs, "tracker unavailable")
+}
+
+func TestNewDSNReporter(t *testing.T) {
+	for _, dsn := range []string{"https://sentry.example.org/42", "https://key@sentry.example.org/", "://"} {
+		_, err := NewDSNReporter(dsn, nil)
+		assert.Error(t, err, dsn)
+	}
+
+	var request *http.Request
+	var body map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		payload, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(payload, &body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	reporter, err := NewDSNReporter("http://public-key@"+server.Listener.Addr().String()+"/sentry/42", nil)
+	assert.NoError(t, err)
+	event := Event{
+		Check:       "db",
+		Level:       LevelError,
+		Message:     "check db failed: down",
+		Fingerprint: []string{"go-sundheit", "db"},
+		Tags:        map[string]string{"check": "db"},
+		Extra:       map[string]interface{}{"contiguousFailures": 1},
+		Timestamp:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	assert.NoError(t, reporter.Report(context.Background(), event))
+
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "/sentry/api/42/store/", request.URL.Path)
+	assert.Equal(t, "Sentry sentry_version=7, sentry_client=go-sundheit, sentry_key=public-key", request.Header.Get("X-Sentry-Auth"))
+	assert.Len(t, body["event_id"], 32)
+	assert.Equal(t, "2020-01-01T00:00:00Z", body["timestamp"])
+	assert.Equal(t, "error", body["level"])
+	assert.Equal(t, "check db failed: down", body["message"])
+	assert.Equal(t, []interface{}{"go-sundheit", "db"}, body["fingerprint"])
+	assert.Equal(t, map[string]interface{}{"check": "db"}, body["tags"])
+
+	status = http.StatusTooManyRequests
+	assert.EqualError(t, reporter.Report(context.Background(), event), "event rejected with status 429")
+}
@@ -0,0 +1,212 @@
+package gosundheit
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// sharedScheduler executes all check tasks from a single scheduling goroutine,
+// which dispatches due tasks to a bounded number of concurrent executions.
+// The scheduling goroutine runs only while there are scheduled tasks.
+// When shedding, due non Critical tasks are shed instead of waiting for a worker, while all workers are busy.
+type sharedScheduler struct {
+	h        *health
+	shedding bool
+	lock     sync.Mutex
+	queue    taskQueue
+	running  bool
+	wake     chan struct{}
+	workers  chan struct{}
+}
+
+func newSharedScheduler(h *health, workers int, shedding bool) *sharedScheduler {
+	return &sharedScheduler{
+		h:        h,
+		shedding: shedding,
+		wake:     make(chan struct{}, 1),
+		workers:  make(chan struct{}, workers),
+	}
+}
+
+func (s *sharedScheduler) schedule(task *checkTask) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the task may have been unscheduled before it was scheduled
+	if task.cancelled {
+		return
+	}
+	task.nextRun = time.Now().Add(task.cfg.InitialDelay)
+	s.enqueue(task)
+}
+
+func (s *sharedScheduler) unschedule(task *checkTask) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if task.cancelled {
+		return
+	}
+	task.cancelled = true
+	task.stop()
+	// executing tasks are not requeued, and are marked done, once their current execution completes
+	if !task.executing {
+		if task.queueIndex >= 0 {
+			heap.Remove(&s.queue, task.queueIndex)
+			// wake the scheduling goroutine, so it stops once the queue is empty
+			s.wakeUp()
+		}
+		task.active.Done()
+	}
+}
+
+// enqueue adds the task to the queue, and makes sure the scheduling goroutine is running.
+// Callers must hold the lock.
+func (s *sharedScheduler) enqueue(task *checkTask) {
+	heap.Push(&s.queue, task)
+	if !s.running {
+		s.running = true
+		go s.loop()
+	}
+	s.wakeUp()
+}
+
+// wakeUp wakes the scheduling goroutine, to re-evaluate the queue.
+func (s *sharedScheduler) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *sharedScheduler) loop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.lock.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.lock.Unlock()
+			return
+		}
+
+		task := s.queue[0]
+		wait := time.Until(task.nextRun)
+		if wait <= 0 {
+			s.h.schedulerLag.record(-wait)
+			heap.Pop(&s.queue)
+			task.executing = true
+			s.lock.Unlock()
+
+			s.dispatch(task)
+			continue
+		}
+		s.lock.Unlock()
+
+		stopTimer(timer)
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// dispatch executes the task once a worker is available, and reschedules it after the execution.
+// Non Critical tasks are shed instead, when shedding while all workers are busy.
+// Tasks whose concurrency group is busy don't take a worker, and are requeued once their group is released,
+// so waiting group members never hold the workers needed by unrelated tasks.
+func (s *sharedScheduler) dispatch(task *checkTask) {
+	group := task.cfg.ConcurrencyGroup
+	if group != "" && !s.h.concurrencyGroups.tryAcquire(group, func() { s.requeue(task) }) {
+		return
+	}
+	release := func() {
+		if group != "" {
+			s.h.concurrencyGroups.release(group)
+		}
+	}
+
+	if s.shedding && task.cfg.Severity != Critical {
+		select {
+		case s.workers <- struct{}{}:
+		default:
+			release()
+			s.h.shedTask(task)
+			s.reschedule(task)
+			return
+		}
+	} else {
+		s.workers <- struct{}{}
+	}
+
+	go func() {
+		defer func() { <-s.workers }()
+
+		s.h.executeAndUpdateResult(context.Background(), task, s.h.now())
+		release()
+		s.h.reportResults()
+		s.reschedule(task)
+	}()
+}
+
+// requeue makes the task due again, after waiting for its concurrency group.
+func (s *sharedScheduler) requeue(task *checkTask) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	task.executing = false
+	if task.cancelled {
+		task.active.Done()
+		return
+	}
+
+	task.nextRun = time.Now()
+	s.enqueue(task)
+}
+
+func (s *sharedScheduler) reschedule(task *checkTask) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	task.executing = false
+	if task.cancelled {
+		task.active.Done()
+		return
+	}
+
+	task.nextRun = nextRun(task.cfg, task.period(), task.nextRun, time.Now())
+	s.enqueue(task)
+}
+
+// taskQueue is a min heap of check tasks ordered by their next execution time.
+type taskQueue []*checkTask
+
+func (q taskQueue) Len() int { return len(q) }
+
+func (q taskQueue) Less(i, j int) bool { return q[i].nextRun.Before(q[j].nextRun) }
+
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].queueIndex = i
+	q[j].queueIndex = j
+}
+
+func (q *taskQueue) Push(x interface{}) {
+	task := x.(*checkTask)
+	task.queueIndex = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.queueIndex = -1
+	*q = old[:n-1]
+	return task
+}
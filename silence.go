@@ -0,0 +1,55 @@
+package gosundheit
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const day = 24 * time.Hour
+
+// SilenceWindow is a daily time window (e.g. a nightly maintenance window from 02:00 to 03:00 in a given time zone),
+// during which the failures of a check are recorded, but don't affect the health.
+type SilenceWindow struct {
+	// Start is the time of day the window starts at, as the offset from midnight, e.g. `2 * time.Hour` for 02:00
+	Start time.Duration
+	// Duration is the length of the window, e.g. `time.Hour`; windows may cross midnight
+	Duration time.Duration
+	// Location is the time zone of the window; defaults to UTC
+	Location *time.Location
+}
+
+// Contains returns true iff the given time is within the window
+func (w SilenceWindow) Contains(t time.Time) bool {
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+	t = t.In(location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	// a window crossing midnight may have started the day before
+	for _, date := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+		start := date.Add(w.Start)
+		if !t.Before(start) && t.Before(start.Add(w.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w SilenceWindow) validate() error {
+	if w.Start < 0 || w.Start >= day || w.Duration <= 0 || w.Duration > day {
+		return errors.Errorf("start %v duration %v, start must be within a day, and duration must be positive and at most a day", w.Start, w.Duration)
+	}
+	return nil
+}
+
+// silenced returns true iff the given time is within any of the windows
+func silenced(windows []SilenceWindow, t time.Time) bool {
+	for _, window := range windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
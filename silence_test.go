@@ -0,0 +1,70 @@
+package gosundheit
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestSilenceWindowContains(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	nightly := SilenceWindow{Start: 2 * time.Hour, Duration: time.Hour, Location: newYork}
+	assert.True(t, nightly.Contains(time.Date(2020, 3, 1, 2, 0, 0, 0, newYork)), "window start")
+	assert.True(t, nightly.Contains(time.Date(2020, 3, 1, 7, 30, 0, 0, time.UTC)), "02:30 in New York")
+	assert.False(t, nightly.Contains(time.Date(2020, 3, 1, 3, 0, 0, 0, newYork)), "window end")
+	assert.False(t, nightly.Contains(time.Date(2020, 3, 1, 2, 30, 0, 0, time.UTC)), "02:30 UTC")
+
+	crossing := SilenceWindow{Start: 23 * time.Hour, Duration: 2 * time.Hour}
+	assert.True(t, crossing.Contains(time.Date(2020, 3, 1, 23, 30, 0, 0, time.UTC)), "before midnight")
+	assert.True(t, crossing.Contains(time.Date(2020, 3, 2, 0, 30, 0, 0, time.UTC)), "after midnight")
+	assert.False(t, crossing.Contains(time.Date(2020, 3, 2, 1, 0, 0, 0, time.UTC)), "window end after midnight")
+}
+
+func TestSilences(t *testing.T) {
+	var clock atomic.Value
+	clock.Store(time.Date(2020, 3, 1, 2, 30, 0, 0, time.UTC))
+	recorder := &transitionsRecorder{}
+	h := New(
+		WithTimestampSource(func() time.Time { return clock.Load().(time.Time) }),
+		WithHealthListeners(NewTransitionHealthListener(recorder)),
+	)
+	defer h.DeregisterAll()
+
+	_ = h.RegisterCheck(&Config{
+		Check: checks.NewScriptedCheck("maintained.check",
+			checks.PassResult("ok"),
+			checks.FailResult(errors.New("under maintenance")),
+		),
+		ExecutionPeriod: 10 * time.Millisecond,
+		Silences:        []SilenceWindow{{Start: 2 * time.Hour, Duration: time.Hour}},
+	})
+	time.Sleep(25 * time.Millisecond)
+
+	results, healthy := h.Results()
+	assert.False(t, results["maintained.check"].IsHealthy(), "the failure is recorded within the window")
+	assert.True(t, results["maintained.check"].Silenced, "the failure is silenced within the window")
+	assert.True(t, healthy, "silenced failures don't affect the health")
+	assert.True(t, h.IsHealthy(), "silenced failures don't affect the health")
+	assert.Equal(t, []string{"maintained.check Added"}, recorder.recorded(), "silenced failures don't trigger transitions")
+
+	clock.Store(time.Date(2020, 3, 1, 3, 30, 0, 0, time.UTC))
+	time.Sleep(15 * time.Millisecond)
+	assert.False(t, h.IsHealthy(), "failures affect the health after the window")
+	assert.Equal(t, []string{"maintained.check Added", "maintained.check Failed"}, recorder.recorded())
+
+	err := h.RegisterCheck(&Config{
+		Check:           &checks.CustomCheck{CheckName: "invalid.window"},
+		ExecutionPeriod: time.Minute,
+		Silences:        []SilenceWindow{{Start: 25 * time.Hour, Duration: time.Hour}},
+	})
+	assert.Error(t, err, "invalid silence window")
+}
@@ -0,0 +1,206 @@
+// Package sink publishes the health results to a message bus (e.g. Kafka or NATS), feeding centralized health pipelines.
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	transitionsBufferSize = 256
+	messageTypeSnapshot   = "snapshot"
+	messageTypeTransition = "transition"
+)
+
+// Publisher publishes a message to the message bus.
+// Implementations are usually thin adapters over a Kafka producer or a NATS connection.
+type Publisher interface {
+	// Publish publishes the payload; the key identifies the message subject (the source for snapshots,
+	// and the check name for transitions), e.g. for partitioning.
+	Publish(ctx context.Context, key string, payload []byte) error
+}
+
+// PublisherFunc is an adapter to allow the use of ordinary functions as Publishers.
+type PublisherFunc func(ctx context.Context, key string, payload []byte) error
+
+// Publish calls f(ctx, key, payload)
+func (f PublisherFunc) Publish(ctx context.Context, key string, payload []byte) error {
+	return f(ctx, key, payload)
+}
+
+// Snapshot is the message published for each results update.
+type Snapshot struct {
+	Type      string                       `json:"type"`
+	Source    string                       `json:"source,omitempty"`
+	Timestamp time.Time                    `json:"timestamp"`
+	Results   map[string]gosundheit.Result `json:"results"`
+}
+
+// Transition is the message published for each check transition, in the transitions only mode.
+type Transition struct {
+	Type      string    `json:"type"`
+	Source    string    `json:"source,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Check     string    `json:"check"`
+	// Change is one of "Added", "Removed", "Failed" and "Recovered", see gosundheit.ChangeType
+	Change string `json:"change"`
+	// Result is the current result of the check; nil when the check was removed
+	Result *gosundheit.Result `json:"result,omitempty"`
+}
+
+// Option configures the Listener
+type Option func(*Listener)
+
+// WithSource sets the source of the messages, e.g. the service and instance names.
+func WithSource(source string) Option {
+	return func(l *Listener) {
+		l.source = source
+	}
+}
+
+// WithTransitionsOnly publishes a message per check transition (see gosundheit.Diff), instead of a snapshot per results update.
+func WithTransitionsOnly() Option {
+	return func(l *Listener) {
+		l.transitionsOnly = true
+	}
+}
+
+// WithTimeout sets the timeout of publishing a message; defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(l *Listener) {
+		l.timeout = timeout
+	}
+}
+
+// WithErrorHandler sets a handler of the publishing errors, which are discarded by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Listener) {
+		l.onError = handler
+	}
+}
+
+type message struct {
+	key     string
+	payload []byte
+}
+
+// Listener is a gosundheit.HealthListener that publishes the results snapshots (or the check transitions only)
+// using a Publisher. Messages are published in the background, so the checks are never blocked by the message bus:
+// when snapshots are published slower than the results are updated, only the latest snapshot is published,
+// and when the transitions buffer is full, the oldest transitions are dropped.
+type Listener struct {
+	publisher       Publisher
+	source          string
+	transitionsOnly bool
+	timeout         time.Duration
+	onError         func(error)
+	now             func() time.Time
+
+	lock     sync.Mutex
+	prev     map[string]gosundheit.Result
+	messages chan message
+	closed   bool
+	done     chan struct{}
+}
+
+// NewListener returns a Listener publishing using the given publisher, which should be closed once the health is no longer used.
+func NewListener(publisher Publisher, opts ...Option) *Listener {
+	l := &Listener{
+		publisher: publisher,
+		timeout:   defaultTimeout,
+		onError:   func(error) {},
+		now:       time.Now,
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.transitionsOnly {
+		l.messages = make(chan message, transitionsBufferSize)
+	} else {
+		l.messages = make(chan message, 1)
+	}
+
+	go l.publish()
+	return l
+}
+
+// OnResultsUpdated enqueues the snapshot, or the transitions, for publishing.
+func (l *Listener) OnResultsUpdated(results map[string]gosundheit.Result) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.closed {
+		return
+	}
+
+	if !l.transitionsOnly {
+		l.enqueue(l.source, Snapshot{Type: messageTypeSnapshot, Source: l.source, Timestamp: l.now(), Results: results})
+		return
+	}
+
+	changes := gosundheit.Diff(l.prev, results)
+	l.prev = results
+	for _, change := range changes {
+		transition := Transition{
+			Type:      messageTypeTransition,
+			Source:    l.source,
+			Timestamp: l.now(),
+			Check:     change.Check,
+			Change:    change.Type.String(),
+		}
+		if change.Type != gosundheit.ChangeRemoved {
+			current := change.Current
+			transition.Result = &current
+		}
+		l.enqueue(change.Check, transition)
+	}
+}
+
+// enqueue encodes the message, and adds it to the queue, dropping the oldest message when the queue is full.
+// Callers must hold the lock.
+func (l *Listener) enqueue(key string, msg interface{}) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		l.onError(err)
+		return
+	}
+
+	for {
+		select {
+		case l.messages <- message{key: key, payload: payload}:
+			return
+		default:
+			select {
+			case <-l.messages:
+			default:
+			}
+		}
+	}
+}
+
+func (l *Listener) publish() {
+	defer close(l.done)
+	for msg := range l.messages {
+		ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+		if err := l.publisher.Publish(ctx, msg.key, msg.payload); err != nil {
+			l.onError(err)
+		}
+		cancel()
+	}
+}
+
+// Close stops accepting results updates, and returns once the pending messages are published.
+func (l *Listener) Close() {
+	l.lock.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.messages)
+	}
+	l.lock.Unlock()
+	<-l.done
+}
@@ -0,0 +1,122 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+type recordingPublisher struct {
+	lock     sync.Mutex
+	keys     []string
+	payloads []string
+	picked   chan struct{}
+	release  chan struct{}
+	err      error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, key string, payload []byte) error {
+	if p.release != nil {
+		p.picked <- struct{}{}
+		<-p.release
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.keys = append(p.keys, key)
+	p.payloads = append(p.payloads, string(payload))
+	return p.err
+}
+
+func TestListener_snapshots(t *testing.T) {
+	publisher := &recordingPublisher{picked: make(chan struct{}, 2), release: make(chan struct{})}
+	listener := NewListener(publisher, WithSource("orders-1"))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	listener.now = func() time.Time { return now }
+
+	// the first snapshot is picked by the publisher, which blocks until released
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Details: "first"}})
+	<-publisher.picked
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Details: "second"}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Details: "third"}})
+	close(publisher.release)
+	listener.Close()
+
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {Details: "closed"}})
+
+	assert.Equal(t, []string{"orders-1", "orders-1"}, publisher.keys)
+	if assert.Len(t, publisher.payloads, 2) {
+		var snapshot Snapshot
+		assert.NoError(t, json.Unmarshal([]byte(publisher.payloads[1]), &snapshot))
+		assert.Equal(t, "snapshot", snapshot.Type)
+		assert.Equal(t, "orders-1", snapshot.Source)
+		assert.Equal(t, now, snapshot.Timestamp)
+		assert.Equal(t, "third", snapshot.Results["db"].Details, "only the latest pending snapshot is published")
+	}
+}
+
+func TestListener_transitionsOnly(t *testing.T) {
+	publisher := &recordingPublisher{}
+	var errs []error
+	listener := NewListener(publisher, WithTransitionsOnly(), WithErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {}, "cache": {}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {}, "cache": {}})
+	listener.OnResultsUpdated(map[string]gosundheit.Result{"db": {ContiguousFailures: 1, Error: failure("down")}})
+	listener.Close()
+
+	assert.Equal(t, []string{"cache", "db", "cache", "db"}, publisher.keys)
+	var changes []string
+	for _, payload := range publisher.payloads {
+		var transition struct {
+			Type   string                 `json:"type"`
+			Change string                 `json:"change"`
+			Result map[string]interface{} `json:"result"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(payload), &transition))
+		assert.Equal(t, "transition", transition.Type)
+		changes = append(changes, transition.Change)
+		if transition.Change == "Removed" {
+			assert.Nil(t, transition.Result)
+		} else {
+			assert.NotNil(t, transition.Result)
+		}
+	}
+	assert.Equal(t, []string{"Added", "Added", "Removed", "Failed"}, changes)
+	assert.Empty(t, errs)
+}
+
+func TestListener_errors(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("broker unavailable")}
+	errs := make(chan error, 1)
+	listener := NewListener(publisher, WithErrorHandler(func(err error) { errs <- err }))
+	listener.OnResultsUpdated(map[string]gosundheit.Result{})
+	listener.Close()
+
+	assert.EqualError(t, <-errs, "broker unavailable")
+}
+
+// failure is an error that can be encoded, as the results errors are
+type failure string
+
+func (f failure) Error() string { return string(f) }
+
+func (f failure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"message": string(f)})
+}
+
+func TestPublisherFunc(t *testing.T) {
+	var published string
+	publisher := PublisherFunc(func(_ context.Context, key string, _ []byte) error {
+		published = key
+		return nil
+	})
+	assert.NoError(t, publisher.Publish(context.Background(), "key", nil))
+	assert.Equal(t, "key", published)
+}
@@ -0,0 +1,396 @@
+// Package slack notifies Slack channels of the check transitions, using rich Block Kit messages.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultAPIURL        = "https://slack.com/api/chat.postMessage"
+	defaultRateLimit     = 10
+	defaultRatePeriod    = time.Minute
+	defaultTimeout       = 5 * time.Second
+	messagesBufferSize   = 64
+	tagCheck             = "check"
+	tagClassification    = "classification"
+	tagOwner             = "owner"
+	emojiFailed          = ":red_circle:"
+	emojiRecovered       = ":large_green_circle:"
+	suppressedNoticeText = "%d notification(s) were suppressed by rate limiting"
+)
+
+// Route routes the transitions of the checks matching all of its tags to a channel.
+// The tags of a check are its annotations, its "classification" and "owner" when configured, and its name as "check".
+type Route struct {
+	Channel string
+	Tags    map[string]string
+}
+
+func (r Route) matches(tags map[string]string) bool {
+	for name, value := range r.Tags {
+		if tags[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Option configures the Notifier
+type Option func(*Notifier)
+
+// WithRoutes adds routes of the transitions to channels. Transitions matching no route are sent to the default channel.
+func WithRoutes(routes ...Route) Option {
+	return func(n *Notifier) {
+		n.routes = append(n.routes, routes...)
+	}
+}
+
+// WithSource sets the source of the notifications, e.g. the service and instance names, which is included in the messages.
+func WithSource(source string) Option {
+	return func(n *Notifier) {
+		n.source = source
+	}
+}
+
+// WithRateLimit sets the maximal number of messages sent to each channel per period; defaults to 10 per minute.
+// Transitions beyond the limit are dropped, and counted in the next message sent to the channel.
+func WithRateLimit(messages int, period time.Duration) Option {
+	return func(n *Notifier) {
+		n.rateLimit = messages
+		n.ratePeriod = period
+	}
+}
+
+// WithAPIURL sets the URL of the chat.postMessage Slack API method, e.g. for Slack compatible servers.
+func WithAPIURL(url string) Option {
+	return func(n *Notifier) {
+		n.apiURL = url
+	}
+}
+
+// WithClient sets the HTTP client used to post the messages; defaults to http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithTimeout sets the timeout of posting a message; defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(n *Notifier) {
+		n.timeout = timeout
+	}
+}
+
+// WithErrorHandler sets a handler of the posting errors, which are discarded by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(n *Notifier) {
+		n.onError = handler
+	}
+}
+
+// Message is a Slack message, as posted to the chat.postMessage API method.
+type Message struct {
+	Channel string  `json:"channel"`
+	Text    string  `json:"text"`
+	Blocks  []Block `json:"blocks"`
+}
+
+// Block is a Block Kit layout block.
+type Block struct {
+	Type     string  `json:"type"`
+	Text     *Text   `json:"text,omitempty"`
+	Elements []*Text `json:"elements,omitempty"`
+}
+
+// Text is a Block Kit text object.
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type bucket struct {
+	tokens     float64
+	updated    time.Time
+	suppressed int
+}
+
+// Notifier is a gosundheit.TransitionListener that posts a Block Kit message per channel on check failures and recoveries,
+// and should be registered using gosundheit.NewTransitionHealthListener.
+// Each channel is rate limited, to avoid flooding it while checks are flapping.
+// Messages are posted in the background, so the checks are never blocked by Slack.
+type Notifier struct {
+	token      string
+	channel    string
+	routes     []Route
+	source     string
+	rateLimit  int
+	ratePeriod time.Duration
+	apiURL     string
+	client     *http.Client
+	timeout    time.Duration
+	onError    func(error)
+	now        func() time.Time
+
+	lock     sync.Mutex
+	buckets  map[string]*bucket
+	messages chan Message
+	closed   bool
+	done     chan struct{}
+}
+
+// NewNotifier returns a Notifier posting with the given bot token to the given default channel,
+// which should be closed once the health is no longer used.
+func NewNotifier(token, channel string, opts ...Option) *Notifier {
+	n := &Notifier{
+		token:      token,
+		channel:    channel,
+		rateLimit:  defaultRateLimit,
+		ratePeriod: defaultRatePeriod,
+		apiURL:     defaultAPIURL,
+		client:     http.DefaultClient,
+		timeout:    defaultTimeout,
+		onError:    func(error) {},
+		now:        time.Now,
+		buckets:    make(map[string]*bucket),
+		messages:   make(chan Message, messagesBufferSize),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	go n.post()
+	return n
+}
+
+// OnTransitions routes the failures and recoveries to channels, and enqueues a message per channel, unless it is rate limited.
+func (n *Notifier) OnTransitions(changes []gosundheit.Change) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.closed {
+		return
+	}
+
+	byChannel := make(map[string][]gosundheit.Change)
+	for _, change := range changes {
+		if change.Type != gosundheit.ChangeFailed && change.Type != gosundheit.ChangeRecovered {
+			continue
+		}
+		for _, channel := range n.channelsOf(change) {
+			byChannel[channel] = append(byChannel[channel], change)
+		}
+	}
+
+	channels := make([]string, 0, len(byChannel))
+	for channel := range byChannel {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	now := n.now()
+	for _, channel := range channels {
+		suppressed, allowed := n.allow(channel, now)
+		if !allowed {
+			continue
+		}
+		n.enqueue(n.messageOf(channel, byChannel[channel], suppressed, now))
+	}
+}
+
+func (n *Notifier) channelsOf(change gosundheit.Change) []string {
+	result := change.Current
+	tags := make(map[string]string, len(result.Annotations)+3)
+	for name, value := range result.Annotations {
+		tags[name] = value
+	}
+	tags[tagCheck] = change.Check
+	if result.Classification != "" {
+		tags[tagClassification] = result.Classification
+	}
+	if result.Owner != "" {
+		tags[tagOwner] = result.Owner
+	}
+
+	var channels []string
+	seen := make(map[string]bool)
+	for _, route := range n.routes {
+		if route.matches(tags) && !seen[route.Channel] {
+			seen[route.Channel] = true
+			channels = append(channels, route.Channel)
+		}
+	}
+	if len(channels) == 0 {
+		channels = append(channels, n.channel)
+	}
+	return channels
+}
+
+// allow takes a token from the bucket of the channel, returning the number of messages suppressed since the last allowed one.
+// Callers must hold the lock.
+func (n *Notifier) allow(channel string, now time.Time) (int, bool) {
+	b, ok := n.buckets[channel]
+	if !ok {
+		b = &bucket{tokens: float64(n.rateLimit), updated: now}
+		n.buckets[channel] = b
+	}
+	if n.ratePeriod > 0 {
+		b.tokens += float64(n.rateLimit) * float64(now.Sub(b.updated)) / float64(n.ratePeriod)
+		if b.tokens > float64(n.rateLimit) {
+			b.tokens = float64(n.rateLimit)
+		}
+	}
+	b.updated = now
+
+	if b.tokens < 1 {
+		b.suppressed++
+		return 0, false
+	}
+	b.tokens--
+	suppressed := b.suppressed
+	b.suppressed = 0
+	return suppressed, true
+}
+
+func (n *Notifier) messageOf(channel string, changes []gosundheit.Change, suppressed int, now time.Time) Message {
+	var failed, recovered int
+	for _, change := range changes {
+		if change.Type == gosundheit.ChangeFailed {
+			failed++
+		} else {
+			recovered++
+		}
+	}
+	var summary []string
+	if failed > 0 {
+		summary = append(summary, fmt.Sprintf("%d check(s) failed", failed))
+	}
+	if recovered > 0 {
+		summary = append(summary, fmt.Sprintf("%d check(s) recovered", recovered))
+	}
+	title := strings.Join(summary, ", ")
+	if n.source != "" {
+		title = n.source + ": " + title
+	}
+
+	blocks := []Block{{Type: "header", Text: &Text{Type: "plain_text", Text: title}}}
+	for _, change := range changes {
+		blocks = append(blocks, Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: changeText(change)}})
+	}
+	footer := []*Text{{Type: "mrkdwn", Text: fmt.Sprintf("<!date^%d^{date_short_pretty} {time_secs}|%s>", now.Unix(), now.UTC().Format(time.RFC3339))}}
+	if suppressed > 0 {
+		footer = append(footer, &Text{Type: "mrkdwn", Text: fmt.Sprintf(suppressedNoticeText, suppressed)})
+	}
+	blocks = append(blocks, Block{Type: "context", Elements: footer})
+
+	return Message{Channel: channel, Text: title, Blocks: blocks}
+}
+
+func changeText(change gosundheit.Change) string {
+	result := change.Current
+	if change.Type == gosundheit.ChangeRecovered {
+		return fmt.Sprintf("%s *%s* recovered", emojiRecovered, escape(change.Check))
+	}
+
+	text := fmt.Sprintf("%s *%s* failed", emojiFailed, escape(change.Check))
+	if result.Error != nil {
+		text += ": " + escape(result.Error.Error())
+	}
+	if result.Description != "" {
+		text += "\n" + escape(result.Description)
+	}
+	if result.RunbookURL != "" {
+		text += fmt.Sprintf("\n<%s|Runbook>", result.RunbookURL)
+	}
+	return text
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// enqueue adds the message to the queue, dropping the oldest message when the queue is full. Callers must hold the lock.
+func (n *Notifier) enqueue(msg Message) {
+	for {
+		select {
+		case n.messages <- msg:
+			return
+		default:
+			select {
+			case <-n.messages:
+			default:
+			}
+		}
+	}
+}
+
+func (n *Notifier) post() {
+	defer close(n.done)
+	for msg := range n.messages {
+		if err := n.postMessage(msg); err != nil {
+			n.onError(err)
+		}
+	}
+}
+
+func (n *Notifier) postMessage(msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, n.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post the message")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("message rejected with status %d", resp.StatusCode)
+	}
+
+	// the Slack API reports errors in the response body
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrap(err, "failed to decode the response")
+	}
+	if !response.OK {
+		return errors.Errorf("message rejected: %s", response.Error)
+	}
+	return nil
+}
+
+// Close stops accepting transitions, and returns once the pending messages are posted.
+func (n *Notifier) Close() {
+	n.lock.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.messages)
+	}
+	n.lock.Unlock()
+	<-n.done
+}
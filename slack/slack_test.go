@@ -0,0 +1,130 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+type fakeSlack struct {
+	lock     sync.Mutex
+	messages []Message
+	tokens   []string
+	response string
+}
+
+func (s *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.messages = append(s.messages, msg)
+	s.tokens = append(s.tokens, r.Header.Get("Authorization"))
+	if s.response == "" {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	} else {
+		_, _ = w.Write([]byte(s.response))
+	}
+}
+
+func failed(check string, result gosundheit.Result) gosundheit.Change {
+	return gosundheit.Change{Check: check, Type: gosundheit.ChangeFailed, Current: result}
+}
+
+func recovered(check string, result gosundheit.Result) gosundheit.Change {
+	return gosundheit.Change{Check: check, Type: gosundheit.ChangeRecovered, Current: result}
+}
+
+func TestNotifier(t *testing.T) {
+	slack := &fakeSlack{}
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	notifier := NewNotifier("xoxb-token", "#health", WithAPIURL(server.URL), WithSource("orders-1"), WithRoutes(
+		Route{Channel: "#db-team", Tags: map[string]string{"owner": "db"}},
+		Route{Channel: "#payments", Tags: map[string]string{"domain": "payments", "classification": "critical"}},
+	))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	notifier.OnTransitions([]gosundheit.Change{
+		{Check: "cache", Type: gosundheit.ChangeAdded},
+		failed("db", gosundheit.Result{Error: errors.New("<down>"), Owner: "db", RunbookURL: "https://runbooks/db"}),
+		recovered("gateway", gosundheit.Result{Annotations: map[string]string{"domain": "payments"}, Classification: "critical"}),
+		failed("queue", gosundheit.Result{Error: errors.New("down"), Annotations: map[string]string{"domain": "payments"}}),
+	})
+	notifier.Close()
+
+	assert.Equal(t, []string{"Bearer xoxb-token", "Bearer xoxb-token", "Bearer xoxb-token"}, slack.tokens)
+	if assert.Len(t, slack.messages, 3) {
+		assert.Equal(t, Message{
+			Channel: "#db-team",
+			Text:    "orders-1: 1 check(s) failed",
+			Blocks: []Block{
+				{Type: "header", Text: &Text{Type: "plain_text", Text: "orders-1: 1 check(s) failed"}},
+				{Type: "section", Text: &Text{Type: "mrkdwn", Text: ":red_circle: *db* failed: &lt;down&gt;\n<https://runbooks/db|Runbook>"}},
+				{Type: "context", Elements: []*Text{{Type: "mrkdwn", Text: "<!date^1577836800^{date_short_pretty} {time_secs}|2020-01-01T00:00:00Z>"}}},
+			},
+		}, slack.messages[0])
+
+		assert.Equal(t, "#health", slack.messages[1].Channel, "unrouted transitions are sent to the default channel")
+		assert.Equal(t, ":red_circle: *queue* failed: down", slack.messages[1].Blocks[1].Text.Text)
+
+		assert.Equal(t, "#payments", slack.messages[2].Channel)
+		assert.Equal(t, ":large_green_circle: *gateway* recovered", slack.messages[2].Blocks[1].Text.Text)
+	}
+}
+
+func TestNotifier_rateLimit(t *testing.T) {
+	slack := &fakeSlack{}
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	notifier := NewNotifier("xoxb-token", "#health", WithAPIURL(server.URL), WithRateLimit(2, time.Minute))
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	flap := func() {
+		notifier.OnTransitions([]gosundheit.Change{failed("db", gosundheit.Result{Error: errors.New("down")})})
+		notifier.OnTransitions([]gosundheit.Change{recovered("db", gosundheit.Result{})})
+	}
+	flap()
+	flap()
+	// a single token is refilled after half of the period
+	now = now.Add(30 * time.Second)
+	flap()
+	notifier.OnTransitions([]gosundheit.Change{{Check: "db", Type: gosundheit.ChangeRemoved}})
+	notifier.Close()
+
+	if assert.Len(t, slack.messages, 3) {
+		assert.Len(t, slack.messages[1].Blocks, 3)
+		footer := slack.messages[2].Blocks[2]
+		if assert.Len(t, footer.Elements, 2) {
+			assert.Equal(t, "2 notification(s) were suppressed by rate limiting", footer.Elements[1].Text)
+		}
+	}
+}
+
+func TestNotifier_errors(t *testing.T) {
+	slack := &fakeSlack{response: `{"ok":false,"error":"channel_not_found"}`}
+	server := httptest.NewServer(slack)
+	defer server.Close()
+
+	errs := make(chan error, 1)
+	notifier := NewNotifier("xoxb-token", "#missing", WithAPIURL(server.URL), WithErrorHandler(func(err error) { errs <- err }))
+	notifier.OnTransitions([]gosundheit.Change{failed("db", gosundheit.Result{Error: errors.New("down")})})
+	notifier.Close()
+
+	assert.EqualError(t, <-errs, "message rejected: channel_not_found")
+}
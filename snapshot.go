@@ -0,0 +1,137 @@
+package gosundheit
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+var encodeBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// resultsSnapshot is an immutable view of the results, and of the health aggregated from them.
+type resultsSnapshot struct {
+	// generation is the results generation the snapshot was built at
+	generation        uint64
+	results           map[string]Result
+	failing           int64
+	failingLeaderOnly int64
+	weights           weights
+	// capabilities impacted by the failing checks
+	impacted           map[string]bool
+	impactedLeaderOnly map[string]bool
+	// leaderOnly are the names of the checks affecting only the health of the leader
+	leaderOnly []string
+
+	jsonOnce sync.Once
+	json     []byte
+
+	followerOnce    sync.Once
+	followerResults map[string]Result
+}
+
+// encodeJSON returns the indented JSON encoding of the snapshot results, which is encoded once per snapshot.
+// Returns nil when the results can't be encoded.
+func (s *resultsSnapshot) encodeJSON() []byte {
+	s.jsonOnce.Do(func() {
+		buf := encodeBufferPool.Get().(*bytes.Buffer)
+		defer encodeBufferPool.Put(buf)
+		buf.Reset()
+
+		encoder := json.NewEncoder(buf)
+		encoder.SetIndent("", "\t")
+		if err := encoder.Encode(s.results); err != nil {
+			return
+		}
+		s.json = append([]byte(nil), buf.Bytes()...)
+	})
+	return s.json
+}
+
+// resultsOf returns the results reported by an instance of the given leadership:
+// on non leader instances, the results of the leader only checks are marked as not affecting the health.
+func (s *resultsSnapshot) resultsOf(leader bool) map[string]Result {
+	if leader || len(s.leaderOnly) == 0 {
+		return s.results
+	}
+
+	s.followerOnce.Do(func() {
+		s.followerResults = make(map[string]Result, len(s.results))
+		for name, result := range s.results {
+			s.followerResults[name] = result
+		}
+		for _, name := range s.leaderOnly {
+			result := s.followerResults[name]
+			result.notLeader = true
+			s.followerResults[name] = result
+		}
+	})
+	return s.followerResults
+}
+
+func (s *resultsSnapshot) healthy(leader bool) bool {
+	return s.failing == 0 && (!leader || s.failingLeaderOnly == 0)
+}
+
+// invalidateSnapshot marks the current results snapshot as stale, and returns the new results generation.
+// It must be called on each results change, and callers must notify h.changes once the change is applied.
+func (h *health) invalidateSnapshot() uint64 {
+	return atomic.AddUint64(&h.generation, 1)
+}
+
+// currentSnapshot returns the latest results snapshot, and rebuilds it only when results have changed since it was built.
+func (h *health) currentSnapshot() *resultsSnapshot {
+	generation := atomic.LoadUint64(&h.generation)
+	if snapshot := h.snapshot.Load().(*resultsSnapshot); snapshot.generation == generation {
+		return snapshot
+	}
+
+	h.snapshotLock.Lock()
+	defer h.snapshotLock.Unlock()
+
+	// the snapshot may have been rebuilt while waiting for the lock
+	generation = atomic.LoadUint64(&h.generation)
+	if snapshot := h.snapshot.Load().(*resultsSnapshot); snapshot.generation == generation {
+		return snapshot
+	}
+
+	snapshot := h.buildSnapshot(generation)
+	h.snapshot.Store(snapshot)
+	return snapshot
+}
+
+func (h *health) buildSnapshot(generation uint64) *resultsSnapshot {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	snapshot := &resultsSnapshot{
+		generation: generation,
+		results:    h.results.copy(),
+	}
+	for name, result := range snapshot.results {
+		task, ok := h.checkTasks[name]
+		leaderOnly := ok && task.cfg.LeaderPolicy != AnyInstance
+		critical := !ok || task.cfg.Severity != Warning
+		snapshot.weights.add(result, leaderOnly)
+		if leaderOnly {
+			snapshot.leaderOnly = append(snapshot.leaderOnly, name)
+		}
+		if !result.affectsHealth() {
+			continue
+		}
+		if leaderOnly {
+			if critical {
+				snapshot.failingLeaderOnly++
+			}
+			snapshot.impactedLeaderOnly = appendCapabilities(snapshot.impactedLeaderOnly, result.Capabilities)
+		} else {
+			if critical {
+				snapshot.failing++
+			}
+			snapshot.impacted = appendCapabilities(snapshot.impacted, result.Capabilities)
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,85 @@
+package gosundheit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestResultsSnapshot(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	registerCheck(h, passingCheckName, true, false)
+	results1, _ := h.Results()
+	results2, _ := h.Results()
+	assert.Equal(t, reflect.ValueOf(results1).Pointer(), reflect.ValueOf(results2).Pointer(),
+		"results snapshot should be reused while results are unchanged")
+	assert.Zero(t, testing.AllocsPerRun(100, func() { h.Results() }), "reading an unchanged snapshot should not allocate")
+
+	// await first execution
+	time.Sleep(30 * time.Millisecond)
+
+	results3, healthy := h.Results()
+	assert.NotEqual(t, reflect.ValueOf(results1).Pointer(), reflect.ValueOf(results3).Pointer(),
+		"results snapshot should be replaced once results change")
+	assert.True(t, healthy, "health of the new snapshot")
+	assert.False(t, results1[passingCheckName].IsHealthy(), "previous snapshot should not change")
+	assert.True(t, results3[passingCheckName].IsHealthy(), "new snapshot should contain the updated result")
+}
+
+func TestResultsJSON(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.Equal(t, "{}\n", string(h.ResultsJSON()), "JSON of empty results")
+
+	registerCheck(h, passingCheckName, true, true)
+	encoded := h.ResultsJSON()
+	assert.Contains(t, string(encoded), "\"passing.check\": {\n\t\t\"message\": \"didn't run yet\"", "indented results JSON")
+	assert.Equal(t, &encoded[0], &h.ResultsJSON()[0], "JSON encoding should be cached while results are unchanged")
+	assert.Zero(t, testing.AllocsPerRun(100, func() { h.ResultsJSON() }), "cached JSON encoding should not allocate")
+
+	_ = h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "bad.details.check",
+			CheckFunc: func() (details interface{}, err error) { return make(chan int), nil },
+		},
+		ExecutionPeriod: time.Millisecond,
+	})
+	// await first execution
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, h.ResultsJSON(), "JSON encoding of details that can't be encoded")
+}
+
+func TestGeneration(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.Equal(t, uint64(0), h.Generation(), "generation before any change")
+
+	registerCheck(h, passingCheckName, true, false)
+	registered := h.Generation()
+	assert.Equal(t, uint64(1), registered, "generation after registration")
+	results, _ := h.Results()
+	assert.Equal(t, registered, results[passingCheckName].Generation, "generation of the initial result")
+
+	// await first execution
+	time.Sleep(30 * time.Millisecond)
+
+	executed := h.Generation()
+	assert.True(t, executed > registered, "generation should increase after execution")
+	results, _ = h.Results()
+	assert.True(t, results[passingCheckName].Generation > registered, "generation of the executed result")
+	assert.True(t, results[passingCheckName].Generation <= executed, "result generation should not exceed the current generation")
+	assert.Contains(t, string(h.ResultsJSON()), "\"generation\": ", "generation in results JSON")
+
+	h.Deregister(passingCheckName)
+	// await stop
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, h.Generation() > executed, "generation should increase after deregistration")
+}
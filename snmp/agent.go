@@ -0,0 +1,52 @@
+package snmp
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/posteo/go-agentx"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	defaultTimeout           = time.Minute
+	defaultReconnectInterval = time.Second
+	registrationPriority     = 127
+)
+
+// Register connects to the AgentX master agent (e.g. snmpd with `master agentx`) at the given address,
+// and registers the health subtree under the given base OID, e.g.:
+//
+//	client, err := snmp.Register("unix", "/var/agentx/master", h, "1.3.6.1.4.1.99999.1")
+//	...
+//	defer client.Close()
+//
+// The client reconnects to the master agent when disconnected. Close the client to stop serving the subtree.
+func Register(network, address string, h gosundheit.Health, baseOID string) (*agentx.Client, error) {
+	handler, err := NewHandler(h, baseOID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := agentx.Dial(network, address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the master agent")
+	}
+	client.Timeout = defaultTimeout
+	client.ReconnectInterval = defaultReconnectInterval
+	client.NameOID = handler.base
+	client.Name = "go-sundheit"
+
+	session, err := client.Session()
+	if err != nil {
+		_ = client.Close()
+		return nil, errors.Wrapf(err, "failed to open an agentx session")
+	}
+	session.Handler = handler
+	if err := session.Register(registrationPriority, handler.base); err != nil {
+		_ = client.Close()
+		return nil, errors.Wrapf(err, "failed to register the subtree %s", baseOID)
+	}
+	return client, nil
+}
@@ -0,0 +1,12 @@
+module github.com/AppsFlyer/go-sundheit/snmp
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/pkg/errors v0.8.1
+	github.com/posteo/go-agentx v0.2.1
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
@@ -0,0 +1,124 @@
+// Package snmp exposes the health through an SNMP agent subtree, using the AgentX protocol (RFC 2741),
+// for NOC tooling which is SNMP driven. The subtree under the base OID is:
+//
+//	<base>.1.0         healthStatus        INTEGER   1 = healthy, 2 = unhealthy
+//	<base>.2.0         checksCount         Gauge32   the number of checks
+//	<base>.3.0         failingChecksCount  Gauge32   the number of failing checks
+//	<base>.4.1.1.<i>   checkName           OCTET STRING
+//	<base>.4.1.2.<i>   checkStatus         INTEGER   1 = passing, 2 = failing
+//	<base>.4.1.3.<i>   checkFailures       Gauge32   the number of contiguous failures
+//	<base>.4.1.4.<i>   checkDurationMs     Gauge32   the duration of the last execution, in milliseconds
+//
+// The checks table is indexed (i) from 1, by the check names order, so the index of a check changes as checks are (de)registered.
+package snmp
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/posteo/go-agentx/pdu"
+	"github.com/posteo/go-agentx/value"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+// SNMP values of the health and check statuses
+const (
+	StatusHealthy   = int32(1)
+	StatusUnhealthy = int32(2)
+)
+
+// Handler is an agentx.Handler serving the health subtree under its base OID.
+// The values are taken from the current results on each request.
+type Handler struct {
+	health gosundheit.Health
+	base   value.OID
+}
+
+// NewHandler returns a Handler of the given Health, serving the subtree under the given base OID, e.g. "1.3.6.1.4.1.99999.1".
+func NewHandler(h gosundheit.Health, baseOID string) (*Handler, error) {
+	base, err := value.ParseOID(baseOID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid base OID %s", baseOID)
+	}
+	return &Handler{health: h, base: base}, nil
+}
+
+type variable struct {
+	oid   value.OID
+	typ   pdu.VariableType
+	value interface{}
+}
+
+// Get returns the value of the given OID
+func (h *Handler) Get(oid value.OID) (value.OID, pdu.VariableType, interface{}, error) {
+	for _, v := range h.variables() {
+		if value.CompareOIDs(v.oid, oid) == 0 {
+			return v.oid, v.typ, v.value, nil
+		}
+	}
+	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+}
+
+// GetNext returns the value of the first OID following the given one (or the given one, when includeFrom is true),
+// and preceding the given upper bound, if any.
+func (h *Handler) GetNext(from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, interface{}, error) {
+	for _, v := range h.variables() {
+		fromCompare := value.CompareOIDs(v.oid, from)
+		if fromCompare < 0 || (fromCompare == 0 && !includeFrom) {
+			continue
+		}
+		if len(to) > 0 && value.CompareOIDs(v.oid, to) >= 0 {
+			break
+		}
+		return v.oid, v.typ, v.value, nil
+	}
+	return nil, pdu.VariableTypeEndOfMIBView, nil, nil
+}
+
+// variables returns the subtree variables of the current results, sorted by OID
+func (h *Handler) variables() []variable {
+	results, healthy := h.health.Results()
+	names := make([]string, 0, len(results))
+	failing := 0
+	for name, result := range results {
+		names = append(names, name)
+		if result.AffectsHealth() {
+			failing++
+		}
+	}
+	sort.Strings(names)
+
+	variables := []variable{
+		{oid: h.oid(1, 0), typ: pdu.VariableTypeInteger, value: status(healthy)},
+		{oid: h.oid(2, 0), typ: pdu.VariableTypeGauge32, value: uint32(len(results))},
+		{oid: h.oid(3, 0), typ: pdu.VariableTypeGauge32, value: uint32(failing)},
+	}
+	// the table is walked column by column
+	for i, name := range names {
+		variables = append(variables, variable{oid: h.oid(4, 1, 1, uint32(i+1)), typ: pdu.VariableTypeOctetString, value: name})
+	}
+	for i, name := range names {
+		variables = append(variables, variable{oid: h.oid(4, 1, 2, uint32(i+1)), typ: pdu.VariableTypeInteger, value: status(results[name].IsHealthy())})
+	}
+	for i, name := range names {
+		variables = append(variables, variable{oid: h.oid(4, 1, 3, uint32(i+1)), typ: pdu.VariableTypeGauge32, value: uint32(results[name].ContiguousFailures)})
+	}
+	for i, name := range names {
+		variables = append(variables, variable{oid: h.oid(4, 1, 4, uint32(i+1)), typ: pdu.VariableTypeGauge32, value: uint32(results[name].Duration.Milliseconds())})
+	}
+	return variables
+}
+
+func (h *Handler) oid(suffix ...uint32) value.OID {
+	oid := make(value.OID, 0, len(h.base)+len(suffix))
+	oid = append(oid, h.base...)
+	return append(oid, suffix...)
+}
+
+func status(healthy bool) int32 {
+	if healthy {
+		return StatusHealthy
+	}
+	return StatusUnhealthy
+}
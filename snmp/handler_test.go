@@ -0,0 +1,84 @@
+package snmp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/posteo/go-agentx/pdu"
+	"github.com/posteo/go-agentx/value"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+const base = "1.3.6.1.4.1.99999.1"
+
+func TestNewHandlerInvalidOID(t *testing.T) {
+	_, err := NewHandler(gosundheittest.NewFakeHealth(), "1.3.x")
+	assert.Error(t, err)
+}
+
+func TestHandlerGet(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("cache")
+	h.SetFailing("db", errors.New("down"))
+	handler, err := NewHandler(h, base)
+	assert.NoError(t, err)
+
+	for oid, expected := range map[string]interface{}{
+		base + ".1.0":     StatusUnhealthy,
+		base + ".2.0":     uint32(2),
+		base + ".3.0":     uint32(1),
+		base + ".4.1.1.1": "cache",
+		base + ".4.1.1.2": "db",
+		base + ".4.1.2.1": StatusHealthy,
+		base + ".4.1.2.2": StatusUnhealthy,
+		base + ".4.1.3.2": uint32(1),
+	} {
+		_, _, actual, err := handler.Get(value.MustParseOID(oid))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, oid)
+	}
+
+	_, typ, _, err := handler.Get(value.MustParseOID(base + ".4.1.1.3"))
+	assert.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeNoSuchObject, typ, "missing table row")
+}
+
+func TestHandlerGetNext(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetResult("db", gosundheit.Result{Details: "ok"})
+	handler, err := NewHandler(h, base)
+	assert.NoError(t, err)
+
+	// walk the whole subtree
+	var walked []string
+	oid, includeFrom := value.MustParseOID(base), false
+	for {
+		next, typ, _, err := handler.GetNext(oid, includeFrom, nil)
+		assert.NoError(t, err)
+		if typ == pdu.VariableTypeEndOfMIBView {
+			break
+		}
+		walked = append(walked, next.String())
+		oid = next
+	}
+	assert.Equal(t, []string{
+		base + ".1.0",
+		base + ".2.0",
+		base + ".3.0",
+		base + ".4.1.1.1",
+		base + ".4.1.2.1",
+		base + ".4.1.3.1",
+		base + ".4.1.4.1",
+	}, walked)
+
+	next, _, _, err := handler.GetNext(value.MustParseOID(base+".2.0"), true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, base+".2.0", next.String(), "including the from OID")
+
+	_, typ, _, err := handler.GetNext(value.MustParseOID(base+".1.0"), false, value.MustParseOID(base+".2.0"))
+	assert.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeEndOfMIBView, typ, "no OID before the upper bound")
+}
@@ -0,0 +1,58 @@
+// Package systemd integrates the health with the systemd service notifications (sd_notify),
+// so services managed by systemd report readiness and status, and get restarted by the watchdog when unhealthy.
+package systemd
+
+import (
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Notification states, see sd_notify(3)
+const (
+	StateReady    = "READY=1"
+	StateWatchdog = "WATCHDOG=1"
+	StateStopping = "STOPPING=1"
+	statusPrefix  = "STATUS="
+)
+
+// Notifier sends service state notifications to the service manager.
+type Notifier interface {
+	// Notify sends the given newline separated state assignments, e.g. "READY=1\nSTATUS=healthy".
+	Notify(state string) error
+}
+
+// SocketNotifier sends notifications over the systemd notification socket.
+type SocketNotifier struct {
+	// Socket is the path of the notification socket. Abstract sockets are prefixed with '@'.
+	// Notifications are silently dropped when the socket is empty, i.e. when not running under systemd.
+	Socket string
+}
+
+// NewSocketNotifier returns a SocketNotifier using the socket from the NOTIFY_SOCKET environment variable, as set by systemd.
+func NewSocketNotifier() *SocketNotifier {
+	return &SocketNotifier{Socket: os.Getenv("NOTIFY_SOCKET")}
+}
+
+// Notify sends the state to the notification socket.
+func (n *SocketNotifier) Notify(state string) error {
+	if n.Socket == "" {
+		return nil
+	}
+
+	socket := n.Socket
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the notification socket")
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return errors.Wrap(err, "failed to send notification")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package systemd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocketNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "systemd")
+	if err != nil {
+		t.Fatal("Failed to create temp dir: ", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal("Failed to listen: ", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	notifier := &SocketNotifier{Socket: socket}
+	assert.NoError(t, notifier.Notify(StateReady+"\nSTATUS=healthy"))
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "READY=1\nSTATUS=healthy", string(buf[:n]))
+}
+
+func TestSocketNotifier_noSocket(t *testing.T) {
+	assert.NoError(t, (&SocketNotifier{}).Notify(StateReady), "notifications should be dropped when not running under systemd")
+	assert.Error(t, (&SocketNotifier{Socket: "/nonexistent/notify.sock"}).Notify(StateReady))
+}
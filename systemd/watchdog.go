@@ -0,0 +1,130 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const defaultStatusInterval = 10 * time.Second
+
+// Option configures Run
+type Option func(*config)
+
+type config struct {
+	notifier         Notifier
+	interval         time.Duration
+	watchdogInterval time.Duration
+}
+
+// WithNotifier sets the notifier; defaults to NewSocketNotifier().
+func WithNotifier(notifier Notifier) Option {
+	return func(cfg *config) {
+		cfg.notifier = notifier
+	}
+}
+
+// WithInterval sets the interval of the status (and watchdog) notifications.
+// Defaults to half the watchdog timeout when the watchdog is enabled, and to 10 seconds otherwise.
+func WithInterval(interval time.Duration) Option {
+	return func(cfg *config) {
+		cfg.interval = interval
+	}
+}
+
+// WithWatchdog enables the watchdog notifications with the given watchdog timeout.
+// Defaults to the timeout from the WATCHDOG_USEC environment variable, as set by systemd when `WatchdogSec` is configured.
+func WithWatchdog(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.watchdogInterval = timeout
+	}
+}
+
+// Run reports the health to systemd until the context is done:
+//   - READY=1 is sent once the system becomes healthy for the first time
+//   - STATUS= is sent on each interval with the current health, and the failing checks
+//   - WATCHDOG=1 is sent on each interval while the system is healthy, when the watchdog is enabled,
+//     so systemd restarts the service once it stays unhealthy for longer than the watchdog timeout
+//
+// STOPPING=1 is sent once the context is done. Run returns the context error, or the error of a failed notification.
+func Run(ctx context.Context, h gosundheit.Health, opts ...Option) error {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.notifier == nil {
+		cfg.notifier = NewSocketNotifier()
+	}
+	if cfg.watchdogInterval == 0 {
+		cfg.watchdogInterval = watchdogFromEnv()
+	}
+	if cfg.interval <= 0 {
+		if cfg.watchdogInterval > 0 {
+			cfg.interval = cfg.watchdogInterval / 2
+		} else {
+			cfg.interval = defaultStatusInterval
+		}
+	}
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	ready := false
+	for {
+		state, healthy := statusOf(h)
+		if healthy {
+			if !ready {
+				ready = true
+				state = StateReady + "\n" + state
+			}
+			if cfg.watchdogInterval > 0 {
+				state = state + "\n" + StateWatchdog
+			}
+		}
+		if err := cfg.notifier.Notify(state); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = cfg.notifier.Notify(StateStopping)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusOf returns the STATUS= assignment of the current health, and the current health
+func statusOf(h gosundheit.Health) (string, bool) {
+	results, healthy := h.Results()
+	if healthy {
+		return fmt.Sprintf("%shealthy (%d checks)", statusPrefix, len(results)), true
+	}
+
+	var failing []string
+	for name, result := range results {
+		if result.AffectsHealth() {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return fmt.Sprintf("%sunhealthy, failing checks: %s", statusPrefix, strings.Join(failing, ", ")), false
+}
+
+// watchdogFromEnv returns the watchdog timeout set by systemd for this process, or zero when the watchdog is disabled.
+func watchdogFromEnv() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
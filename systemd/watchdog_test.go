@@ -0,0 +1,80 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+type notificationsRecorder struct {
+	lock   sync.Mutex
+	states []string
+}
+
+func (r *notificationsRecorder) Notify(state string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.states = append(r.states, state)
+	return nil
+}
+
+func (r *notificationsRecorder) recorded() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.states...)
+}
+
+func TestRun(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetFailing("db", errors.New("down"))
+	h.SetFailing("cache", errors.New("down"))
+
+	recorder := &notificationsRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, h, WithNotifier(recorder), WithWatchdog(time.Minute), WithInterval(10*time.Millisecond))
+	}()
+
+	time.Sleep(15 * time.Millisecond)
+	h.SetPassing("db")
+	h.SetPassing("cache")
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	states := recorder.recorded()
+	assert.Equal(t, "STATUS=unhealthy, failing checks: cache, db", states[0], "status while unhealthy, without watchdog")
+	var ready []string
+	for _, state := range states {
+		if state == "READY=1\nSTATUS=healthy (2 checks)\nWATCHDOG=1" {
+			ready = append(ready, state)
+		}
+	}
+	assert.Len(t, ready, 1, "READY=1 should be sent once")
+	assert.Contains(t, states, "STATUS=healthy (2 checks)\nWATCHDOG=1", "watchdog while healthy")
+	assert.Equal(t, StateStopping, states[len(states)-1], "stopping notification")
+}
+
+func TestWatchdogFromEnv(t *testing.T) {
+	defer func() {
+		_ = os.Unsetenv("WATCHDOG_USEC")
+		_ = os.Unsetenv("WATCHDOG_PID")
+	}()
+
+	assert.Equal(t, time.Duration(0), watchdogFromEnv(), "watchdog disabled")
+
+	_ = os.Setenv("WATCHDOG_USEC", "30000000")
+	assert.Equal(t, 30*time.Second, watchdogFromEnv(), "watchdog timeout")
+
+	_ = os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	assert.Equal(t, time.Duration(0), watchdogFromEnv(), "watchdog of another process")
+}
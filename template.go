@@ -0,0 +1,179 @@
+package gosundheit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+const (
+	defaultReconcilePeriod = time.Minute
+	defaultResolveTimeout  = time.Second
+)
+
+// CheckTemplateConfig configures a CheckTemplate.
+type CheckTemplateConfig struct {
+	// Targets is required, and resolves the current targets on each reconcile, e.g. using checks.NewSRVResolver.
+	Targets checks.TargetResolver
+	// NewConfig is required, and creates the configuration of the check of a single target.
+	// The check names must be unique per target, e.g. `replicas.<target>`.
+	NewConfig func(target string) (*Config, error)
+	// ReconcilePeriod is the period of resolving the targets, and reconciling their checks; defaults to 1m.
+	ReconcilePeriod time.Duration
+	// ResolveTimeout is the timeout used for resolving the targets; defaults to 1s.
+	ResolveTimeout time.Duration
+	// OnError is optional, and is called with the errors of the periodic reconciles.
+	OnError func(error)
+}
+
+// CheckTemplate expands a parameterized check definition over a dynamic list of targets, e.g. the replicas of a service:
+// it periodically resolves the targets, registers a check per new target, and deregisters the checks of removed targets.
+// The checks of the remaining targets are left as is, keeping their results.
+type CheckTemplate struct {
+	h      Health
+	cfg    CheckTemplateConfig
+	lock   sync.Mutex
+	checks map[string]string
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewCheckTemplate returns a CheckTemplate registering its checks with the given Health instance,
+// which reconciles the checks right away, and then periodically, until it is closed.
+func NewCheckTemplate(h Health, cfg CheckTemplateConfig) (*CheckTemplate, error) {
+	if cfg.Targets == nil {
+		return nil, errors.Errorf("Targets must not be nil")
+	}
+	if cfg.NewConfig == nil {
+		return nil, errors.Errorf("NewConfig must not be nil")
+	}
+	if cfg.ReconcilePeriod <= 0 {
+		cfg.ReconcilePeriod = defaultReconcilePeriod
+	}
+	if cfg.ResolveTimeout <= 0 {
+		cfg.ResolveTimeout = defaultResolveTimeout
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(error) {}
+	}
+
+	t := &CheckTemplate{
+		h:      h,
+		cfg:    cfg,
+		checks: make(map[string]string),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if err := t.Reconcile(); err != nil {
+		cfg.OnError(err)
+	}
+	go t.run()
+	return t, nil
+}
+
+func (t *CheckTemplate) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(t.cfg.ReconcilePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			if err := t.Reconcile(); err != nil {
+				t.cfg.OnError(err)
+			}
+		}
+	}
+}
+
+// Reconcile resolves the targets right away, registers the checks of the new targets, and deregisters the checks of
+// the removed targets. When the targets can't be resolved, the checks are left as is.
+// Returns the resolving error, or the errors of the targets whose checks failed to be created or registered,
+// which are retried on the next reconcile.
+func (t *CheckTemplate) Reconcile() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.ResolveTimeout)
+	defer cancel()
+	targets, err := t.cfg.Targets(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve the targets")
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	select {
+	case <-t.stop:
+		// closed templates don't register checks
+		return nil
+	default:
+	}
+
+	current := make(map[string]bool, len(targets))
+	var errs MultiError
+	for _, target := range targets {
+		current[target] = true
+		if _, ok := t.checks[target]; ok {
+			continue
+		}
+		cfg, err := t.cfg.NewConfig(target)
+		if err == nil && cfg == nil {
+			err = errors.Errorf("no configuration was created")
+		}
+		if err == nil {
+			err = t.h.RegisterCheck(cfg)
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to register the check of target %s", target))
+			continue
+		}
+		t.checks[target] = cfg.Check.Name()
+	}
+	for target, name := range t.checks {
+		if !current[target] {
+			t.h.Deregister(name)
+			delete(t.checks, target)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Targets returns the targets whose checks are registered, sorted.
+func (t *CheckTemplate) Targets() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	targets := make([]string, 0, len(t.checks))
+	for target := range t.checks {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Close stops reconciling the checks, and deregisters them.
+func (t *CheckTemplate) Close() {
+	t.lock.Lock()
+	select {
+	case <-t.stop:
+	default:
+		close(t.stop)
+	}
+	t.lock.Unlock()
+	<-t.done
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for target, name := range t.checks {
+		t.h.Deregister(name)
+		delete(t.checks, target)
+	}
+}
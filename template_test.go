@@ -0,0 +1,120 @@
+package gosundheit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+type fakeTargets struct {
+	lock    sync.Mutex
+	targets []string
+	err     error
+}
+
+func (f *fakeTargets) set(err error, targets ...string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.targets, f.err = targets, err
+}
+
+func (f *fakeTargets) resolve(context.Context) ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.targets, f.err
+}
+
+func replicaConfig(target string) (*Config, error) {
+	if target == "bogus" {
+		return nil, errors.New("bogus target")
+	}
+	return &Config{
+		Check:           checks.NewScriptedCheck("replicas." + target),
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}, nil
+}
+
+func TestCheckTemplate(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	targets := &fakeTargets{}
+	targets.set(nil, "a", "b")
+	tmpl, err := NewCheckTemplate(h, CheckTemplateConfig{
+		Targets:         targets.resolve,
+		NewConfig:       replicaConfig,
+		ReconcilePeriod: time.Hour,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tmpl.Targets())
+	assert.ElementsMatch(t, []string{"replicas.a", "replicas.b"}, checkNames(h))
+
+	result, err := h.Trigger("replicas.a")
+	assert.NoError(t, err)
+	assert.True(t, result.Executed())
+
+	targets.set(nil, "a", "c", "bogus")
+	err = tmpl.Reconcile()
+	assert.EqualError(t, err, "1 errors occurred: failed to register the check of target bogus: bogus target")
+	assert.Equal(t, []string{"a", "c"}, tmpl.Targets())
+	assert.ElementsMatch(t, []string{"replicas.a", "replicas.c"}, checkNames(h))
+	results, _ := h.Results()
+	assert.True(t, results["replicas.a"].Executed(), "the checks of the remaining targets are left as is")
+
+	targets.set(errors.New("no DNS"))
+	assert.EqualError(t, tmpl.Reconcile(), "failed to resolve the targets: no DNS")
+	assert.Equal(t, []string{"a", "c"}, tmpl.Targets(), "the checks are left as is when the targets can't be resolved")
+
+	tmpl.Close()
+	assert.Empty(t, tmpl.Targets())
+	assert.Empty(t, h.Checks())
+	targets.set(nil, "a")
+	assert.NoError(t, tmpl.Reconcile())
+	assert.Empty(t, h.Checks(), "closed templates don't register checks")
+}
+
+func TestCheckTemplate_periodicReconcile(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	targets := &fakeTargets{}
+	targets.set(nil, "a")
+	errs := make(chan error, 1)
+	tmpl, err := NewCheckTemplate(h, CheckTemplateConfig{
+		Targets:         targets.resolve,
+		NewConfig:       replicaConfig,
+		ReconcilePeriod: 5 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	assert.NoError(t, err)
+	defer tmpl.Close()
+
+	targets.set(nil, "a", "bogus")
+	select {
+	case err := <-errs:
+		assert.Contains(t, err.Error(), "bogus target")
+	case <-time.After(time.Second):
+		t.Fatal("the targets weren't reconciled")
+	}
+	assert.Equal(t, []string{"a"}, tmpl.Targets())
+}
+
+func TestNewCheckTemplate_validation(t *testing.T) {
+	h := New()
+	_, err := NewCheckTemplate(h, CheckTemplateConfig{NewConfig: replicaConfig})
+	assert.Error(t, err)
+	_, err = NewCheckTemplate(h, CheckTemplateConfig{Targets: (&fakeTargets{}).resolve})
+	assert.Error(t, err)
+}
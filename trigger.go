@@ -0,0 +1,43 @@
+package gosundheit
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+func (h *health) Trigger(name string) (Result, error) {
+	return h.TriggerContext(context.Background(), name)
+}
+
+func (h *health) TriggerContext(ctx context.Context, name string) (Result, error) {
+	h.lock.RLock()
+	task, ok := h.checkTasks[name]
+	if ok {
+		// DeregisterAll waits for the triggered execution as well; the task is still registered, so it is still active
+		task.active.Add(1)
+	}
+	h.lock.RUnlock()
+	if !ok {
+		return Result{}, errors.Errorf("check %s is not registered", name)
+	}
+	if task.isQuarantined() {
+		task.active.Done()
+		return Result{}, errors.Errorf("check %s is quarantined", name)
+	}
+
+	func() {
+		defer task.active.Done()
+		h.checkAndUpdateResult(ctx, task, h.now())
+	}()
+	if err := ctx.Err(); err != nil {
+		return Result{}, errors.Wrapf(err, "check %s execution abandoned", name)
+	}
+	h.reportResults()
+
+	result, ok := h.currentSnapshot().resultsOf(h.isLeader())[name]
+	if !ok {
+		return Result{}, errors.Errorf("check %s was deregistered during its execution", name)
+	}
+	return result, nil
+}
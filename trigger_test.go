@@ -0,0 +1,96 @@
+package gosundheit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestTrigger(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	_, err := h.Trigger("db")
+	assert.EqualError(t, err, "check db is not registered")
+
+	check := checks.NewScriptedCheck("db", checks.PassResult("first"), checks.FailResult(errors.New("second")))
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check:           check,
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+	generation := h.Generation()
+
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.True(t, result.IsHealthy())
+	assert.Equal(t, "first", result.Details)
+	assert.True(t, h.Generation() > generation, "triggered executions update the results")
+	assert.True(t, h.IsHealthy())
+
+	result, err = h.Trigger("db")
+	assert.NoError(t, err)
+	assert.EqualError(t, result.Error, "second")
+	assert.Equal(t, int64(1), result.ContiguousFailures)
+	assert.False(t, h.IsHealthy())
+}
+
+func TestTrigger_deregisteredDuringExecution(t *testing.T) {
+	h := New()
+	var triggered bool
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "db",
+			CheckFunc: func() (details interface{}, err error) {
+				if triggered {
+					h.Deregister("db")
+				}
+				return nil, nil
+			},
+		},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+
+	triggered = true
+	_, err := h.Trigger("db")
+	assert.EqualError(t, err, "check db was deregistered during its execution")
+}
+
+func TestTriggerContext(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	release := make(chan struct{})
+	deadlines := make(chan bool, 2)
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check: &contextCheck{name: "db", execute: func(ctx context.Context) checks.ExecutionResult {
+			_, ok := ctx.Deadline()
+			deadlines <- ok
+			<-release
+			return checks.ExecutionResult{Details: "ok"}
+		}},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := h.TriggerContext(ctx, "db")
+	assert.EqualError(t, err, "check db execution abandoned: context deadline exceeded")
+	assert.True(t, <-deadlines, "the deadline is propagated to the check")
+
+	results, _ := h.Results()
+	assert.False(t, results["db"].Executed(), "abandoned executions don't update the result")
+
+	close(release)
+	result, err := h.TriggerContext(context.Background(), "db")
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Details)
+	assert.False(t, <-deadlines)
+}
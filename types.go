@@ -2,14 +2,14 @@ package gosundheit
 
 import (
 	"fmt"
+	"strings"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
 const (
 	maxExpectedChecks = 16
 	initialResultMsg  = "didn't run yet"
+	notLeaderMsg      = "execution skipped - this instance is not the leader"
 	// ValAllChecks is the value used for the check tags when tagging all tests
 	ValAllChecks = "all_checks"
 )
@@ -24,10 +24,48 @@ type Result struct {
 	Timestamp time.Time `json:"timestamp"`
 	// the execution duration of the last check
 	Duration time.Duration `json:"duration,omitempty"`
+	// the breakdown of the execution duration by phase (e.g. DNS lookup, connect, TLS handshake), when reported by the check
+	// (see checks.ExecutionResult) - must not be modified
+	Timings map[string]time.Duration `json:"timings,omitempty"`
+	// true when the check passed, but reported a degraded status (see checks.ContextCheck)
+	Degraded bool `json:"degraded,omitempty"`
+	// true when the check failed within one of its silence windows, so the failure doesn't affect the health
+	Silenced bool `json:"silenced,omitempty"`
+	// true when the check failed within its grace period after registration, so the failure doesn't affect the health
+	InGracePeriod bool `json:"inGracePeriod,omitempty"`
+	// the ratio of passing executions among the recent executions of the check,
+	// when the health is evaluated by the recent results (see WithHistoryEvaluation) - nil otherwise
+	RecentPassRatio *float64 `json:"recentPassRatio,omitempty"`
 	// the number of failures that occurred in a row
 	ContiguousFailures int64 `json:"contiguousFailures"`
 	// the time of the initial transitional failure
 	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure"`
+	// the classification of the check, as configured in the check Config
+	Classification string `json:"classification,omitempty"`
+	// the description of the check, as configured in the check Config
+	Description string `json:"description,omitempty"`
+	// the remediation instructions link of the check, as configured in the check Config
+	RunbookURL string `json:"runbookUrl,omitempty"`
+	// the owner of the check, as configured in the check Config
+	Owner string `json:"owner,omitempty"`
+	// the annotations of the check, as configured in the check Config - must not be modified
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// the capabilities gated by the check, as configured in the check Config - must not be modified
+	Capabilities []string `json:"capabilities,omitempty"`
+	// the weight of the check in the health score, as configured in the check Config - zero means the default weight of 1
+	Weight float64 `json:"weight,omitempty"`
+	// true when the check was quarantined after panicking repeatedly, and is no longer executed (see WithPanicQuarantine)
+	Quarantined bool `json:"quarantined,omitempty"`
+	// true when the last due execution of the check was shed (see WithProbeShedding), and the result is of the previous execution
+	Shed bool `json:"shed,omitempty"`
+	// the results generation at which this result was recorded, see Health.Generation()
+	Generation uint64 `json:"generation"`
+	// the recent executions history, when the health is evaluated by the recent results
+	history resultHistory
+	// the severity of the check, as configured in the check Config
+	severity Severity
+	// true when the check affects only the health of the leader, and the result is reported by a non leader instance
+	notLeader bool
 }
 
 // IsHealthy returns true iff the check result snapshot was a success
@@ -35,14 +73,43 @@ func (r Result) IsHealthy() bool {
 	return r.Error == nil
 }
 
+// Executed returns false for the initial result, which is recorded when the check is registered, before its first execution
+func (r Result) Executed() bool {
+	return r.Details != initialResultMsg
+}
+
+// AffectsHealth returns true when the result fails the health reported along with it: i.e. when the result is failing,
+// not silenced nor within the grace period (or too few of the recent executions passed, when the health is evaluated by the recent results),
+// the check is Critical, and it affects the health of this instance (see Config.LeaderPolicy).
+// Failing results that don't affect the health are still reported, but must not fail the health, e.g. the response status.
+func (r Result) AffectsHealth() bool {
+	return r.severity != Warning && !r.notLeader && r.affectsHealth()
+}
+
+// affectsHealth returns true when the result is failing, and not silenced nor within the grace period.
+// When the health is evaluated by the recent results, the result affects the health when too few of the recent executions passed.
+func (r Result) affectsHealth() bool {
+	if r.Silenced || r.InGracePeriod {
+		return false
+	}
+	if r.history.size > 0 {
+		return !r.history.healthy
+	}
+	return !r.IsHealthy()
+}
+
 func (r Result) String() string {
 	return fmt.Sprintf("Result{details: %s, err: %s, time: %s, contiguousFailures: %d, timeOfFirstFailure:%s}",
 		r.Details, r.Error, r.Timestamp, r.ContiguousFailures, r.TimeOfFirstFailure)
 }
 
+// marshalableError encodes the error chain: the message of each layer, and the layer it wraps as its cause,
+// so the root cause (e.g. `context deadline exceeded`) is visible under the wrapping messages.
 type marshalableError struct {
 	Message string `json:"message,omitempty"`
 	Cause   error  `json:"cause,omitempty"`
+	// err is the original error, for errors.Is and errors.As
+	err error
 }
 
 func newMarshalableError(err error) error {
@@ -52,16 +119,49 @@ func newMarshalableError(err error) error {
 
 	mr := &marshalableError{
 		Message: err.Error(),
+		err:     err,
 	}
 
-	cause := errors.Cause(err)
-	if cause != err {
+	// layers that only annotate the error (e.g. with a stack trace) have the same message, and are skipped
+	cause := unwrapLayer(err)
+	for cause != nil && cause.Error() == mr.Message {
+		cause = unwrapLayer(cause)
+	}
+	if cause != nil {
 		mr.Cause = newMarshalableError(cause)
 	}
 
 	return mr
 }
 
+// unwrapLayer returns the error wrapped by err, using either errors.Unwrap or errors.Cause conventions, or nil
+func unwrapLayer(err error) error {
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return wrapper.Unwrap()
+	case interface{ Cause() error }:
+		return wrapper.Cause()
+	default:
+		return nil
+	}
+}
+
 func (e *marshalableError) Error() string {
 	return e.Message
 }
+
+// Unwrap returns the original error, so the result errors may be inspected using errors.Is and errors.As
+func (e *marshalableError) Unwrap() error {
+	return e.err
+}
+
+// MultiError holds several errors, e.g. the errors of all the misconfigured checks passed to RegisterChecks
+type MultiError []error
+
+func (e MultiError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e), strings.Join(messages, "; "))
+}
@@ -0,0 +1,73 @@
+package gosundheit
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+func TestMarshalableError(t *testing.T) {
+	assert.Nil(t, newMarshalableError(nil))
+
+	err := errors.Wrap(fmt.Errorf("query failed: %w", context.DeadlineExceeded), "db check failed")
+	encoded, marshalErr := json.Marshal(newMarshalableError(err))
+	assert.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"message": "db check failed: query failed: context deadline exceeded",
+		"cause": {
+			"message": "query failed: context deadline exceeded",
+			"cause": {"message": "context deadline exceeded"}
+		}
+	}`, string(encoded))
+
+	encoded, marshalErr = json.Marshal(newMarshalableError(errors.New("down")))
+	assert.NoError(t, marshalErr)
+	assert.JSONEq(t, `{"message": "down"}`, string(encoded), "the stack trace layer is skipped")
+}
+
+func TestResult_AffectsHealth(t *testing.T) {
+	failed := errors.New("failed")
+	assert.False(t, Result{}.AffectsHealth(), "passing")
+	assert.True(t, Result{Error: failed}.AffectsHealth(), "failing")
+	assert.False(t, Result{Error: failed, Silenced: true}.AffectsHealth(), "silenced")
+	assert.False(t, Result{Error: failed, InGracePeriod: true}.AffectsHealth(), "within the grace period")
+	assert.False(t, Result{Error: failed, severity: Warning}.AffectsHealth(), "warning check")
+	assert.False(t, Result{Error: failed, notLeader: true}.AffectsHealth(), "leader only check on a non leader")
+	assert.False(t, Result{Error: failed, history: resultHistory{size: 4, healthy: true}}.AffectsHealth(), "healthy history")
+}
+
+func TestResultErrorChain(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterCheck(&Config{
+		Check: &checks.CustomCheck{
+			CheckName: "db",
+			CheckFunc: func() (details interface{}, err error) {
+				return nil, fmt.Errorf("ping failed: %w", context.DeadlineExceeded)
+			},
+		},
+		ExecutionPeriod: time.Hour,
+		InitialDelay:    time.Hour,
+	}))
+
+	result, err := h.Trigger("db")
+	assert.NoError(t, err)
+	assert.EqualError(t, result.Error, "ping failed: context deadline exceeded")
+	assert.True(t, stderrors.Is(result.Error, context.DeadlineExceeded), "the original error is kept")
+
+	var results map[string]struct {
+		Error json.RawMessage `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(h.ResultsJSON(), &results))
+	assert.JSONEq(t, `{"message": "ping failed: context deadline exceeded", "cause": {"message": "context deadline exceeded"}}`,
+		string(results["db"].Error))
+}
@@ -0,0 +1,50 @@
+package gosundheit
+
+import (
+	"context"
+	"sync"
+)
+
+// changeNotifier wakes up waiters whenever the results change.
+type changeNotifier struct {
+	lock    sync.Mutex
+	changed chan struct{}
+}
+
+// wait returns a channel that is closed on the next change.
+func (n *changeNotifier) wait() <-chan struct{} {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.changed == nil {
+		n.changed = make(chan struct{})
+	}
+	return n.changed
+}
+
+// notify wakes up all the current waiters.
+func (n *changeNotifier) notify() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.changed != nil {
+		close(n.changed)
+		n.changed = nil
+	}
+}
+
+func (h *health) WaitForHealthy(ctx context.Context) error {
+	for {
+		// the change channel must be taken before checking the health, so no change is missed in between
+		changed := h.changes.wait()
+		if h.IsHealthy() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
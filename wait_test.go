@@ -0,0 +1,55 @@
+package gosundheit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForHealthy(t *testing.T) {
+	h := New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.WaitForHealthy(context.Background()), "no checks are registered")
+
+	registerCheck(h, "passing.check", true, false)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	assert.NoError(t, h.WaitForHealthy(ctx), "wait for the check to pass")
+	assert.True(t, time.Since(start) >= 15*time.Millisecond, "should wait for the first execution")
+	assert.True(t, h.IsHealthy())
+
+	registerCheck(h, "failing.check", false, false)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, h.WaitForHealthy(ctx), "wait for a failing check")
+
+	h.Deregister("failing.check")
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.WaitForHealthy(ctx), "wait after deregistering the failing check")
+}
+
+func TestChangeNotifier(t *testing.T) {
+	var notifier changeNotifier
+	notifier.notify()
+
+	changed := notifier.wait()
+	assert.True(t, changed == notifier.wait(), "waiters share the change channel")
+	select {
+	case <-changed:
+		t.Fatal("should not be notified before a change")
+	default:
+	}
+
+	notifier.notify()
+	select {
+	case <-changed:
+	default:
+		t.Fatal("should be notified after a change")
+	}
+	assert.False(t, changed == notifier.wait(), "a new change channel is used after notifying")
+}
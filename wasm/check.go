@@ -0,0 +1,176 @@
+// Package wasm is an experimental module for health checks implemented by WebAssembly modules,
+// so checks can be shipped and updated independently of the service binary.
+//
+// A check module implements the check ABI: it exports a `sundheit_check` function, taking no parameters and returning an i64,
+// which packs the offset (high 32 bits) and the length (low 32 bits) of the check result in the module memory.
+// The result is JSON encoded like a checks.PluginResponse (without the ID), e.g. `{"status":"degraded","details":{"queue":12}}`.
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// CheckFunction is the name of the function exported by check modules.
+const CheckFunction = "sundheit_check"
+
+// Runtime compiles and instantiates WebAssembly modules.
+// Implementations are usually thin adapters over a WebAssembly runtime (e.g. `wazero.Runtime`).
+type Runtime interface {
+	// Instantiate compiles and instantiates the given module binary.
+	Instantiate(ctx context.Context, code []byte) (Instance, error)
+}
+
+// Instance is an instantiated WebAssembly module.
+type Instance interface {
+	// Call calls the exported function with the given parameters, and returns its results.
+	Call(ctx context.Context, function string, params ...uint64) ([]uint64, error)
+	// Read reads the given range of the module memory; returns false when the range is out of bounds.
+	Read(offset, length uint32) ([]byte, bool)
+	// Close releases the instance.
+	Close(ctx context.Context) error
+}
+
+// CheckConfig configures a check implemented by a WebAssembly module.
+type CheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Runtime is required, and is used for instantiating the module.
+	Runtime Runtime
+	// Path is required, and is the path of the module binary. The module is reloaded whenever the file changes.
+	Path string
+	// Timeout is the timeout of the check execution, defaults to "1s".
+	Timeout time.Duration
+}
+
+type moduleCheck struct {
+	config *CheckConfig
+
+	lock     sync.Mutex
+	instance Instance
+	modTime  time.Time
+	size     int64
+}
+
+// NewCheck creates a new check implemented by the WebAssembly module defined by the given config.
+// The module is loaded on the first execution, and executions are serialized, as module instances aren't safe for concurrent use.
+// The returned check implements io.Closer, which releases the module instance.
+func NewCheck(config CheckConfig) (checks.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.Errorf("CheckName must not be empty")
+	}
+	if config.Runtime == nil {
+		return nil, errors.Errorf("Runtime must not be nil")
+	}
+	if config.Path == "" {
+		return nil, errors.Errorf("Path must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &moduleCheck{config: &config}, nil
+}
+
+func (check *moduleCheck) Name() string {
+	return check.config.CheckName
+}
+
+func (check *moduleCheck) Execute() (details interface{}, err error) {
+	result := check.ExecuteWithContext(context.Background())
+	return result.Details, result.Err
+}
+
+func (check *moduleCheck) ExecuteWithContext(ctx context.Context) checks.ExecutionResult {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, check.config.Timeout)
+	defer cancel()
+
+	if err := check.load(ctx); err != nil {
+		return checks.ExecutionResult{Err: err}
+	}
+
+	results, err := check.instance.Call(ctx, CheckFunction)
+	if err != nil {
+		return checks.ExecutionResult{Err: errors.Wrap(err, "module check failed")}
+	}
+	if len(results) != 1 {
+		return checks.ExecutionResult{Err: errors.Errorf("%s returned %d results, expected 1", CheckFunction, len(results))}
+	}
+	data, ok := check.instance.Read(uint32(results[0]>>32), uint32(results[0]))
+	if !ok {
+		return checks.ExecutionResult{Err: errors.Errorf("%s returned a result out of the module memory", CheckFunction)}
+	}
+
+	var response checks.PluginResponse
+	if err = json.Unmarshal(data, &response); err != nil {
+		return checks.ExecutionResult{Err: errors.Wrap(err, "module returned a malformed result")}
+	}
+	result := checks.ExecutionResult{Details: response.Details}
+	switch response.Status {
+	case "", "passing":
+		result.Status = checks.StatusPassing
+	case "degraded":
+		result.Status = checks.StatusDegraded
+	case "failing":
+		result.Status = checks.StatusFailing
+	default:
+		return checks.ExecutionResult{Err: errors.Errorf("module returned an unknown status: %s", response.Status)}
+	}
+	if response.Error != "" {
+		result.Err = errors.New(response.Error)
+	}
+	return result
+}
+
+// load instantiates the module, when not loaded yet or when its file changed. Callers must hold the lock.
+func (check *moduleCheck) load(ctx context.Context) error {
+	info, err := os.Stat(check.config.Path)
+	if err != nil {
+		return errors.Wrap(err, "failed to stat the module")
+	}
+	if check.instance != nil && info.ModTime().Equal(check.modTime) && info.Size() == check.size {
+		return nil
+	}
+
+	code, err := ioutil.ReadFile(check.config.Path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the module")
+	}
+	instance, err := check.config.Runtime.Instantiate(ctx, code)
+	if err != nil {
+		return errors.Wrap(err, "failed to instantiate the module")
+	}
+
+	if check.instance != nil {
+		_ = check.instance.Close(ctx)
+	}
+	check.instance = instance
+	check.modTime = info.ModTime()
+	check.size = info.Size()
+	return nil
+}
+
+// Close releases the module instance.
+func (check *moduleCheck) Close() error {
+	check.lock.Lock()
+	defer check.lock.Unlock()
+
+	if check.instance == nil {
+		return nil
+	}
+	err := check.instance.Close(context.Background())
+	check.instance = nil
+	return err
+}
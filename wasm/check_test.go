@@ -0,0 +1,138 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// fakeRuntime instantiates "modules" whose check result is the module binary itself
+type fakeRuntime struct {
+	instantiations int
+	closed         int
+	err            error
+}
+
+func (r *fakeRuntime) Instantiate(_ context.Context, code []byte) (Instance, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.instantiations++
+	return &fakeInstance{runtime: r, memory: code}, nil
+}
+
+type fakeInstance struct {
+	runtime *fakeRuntime
+	memory  []byte
+}
+
+func (i *fakeInstance) Call(_ context.Context, function string, params ...uint64) ([]uint64, error) {
+	if function != CheckFunction || len(params) != 0 {
+		return nil, errors.New("unexpected call")
+	}
+	if string(i.memory) == "trap" {
+		return nil, errors.New("unreachable")
+	}
+	if string(i.memory) == "oob" {
+		return []uint64{1<<32 | 100}, nil
+	}
+	return []uint64{uint64(len(i.memory))}, nil
+}
+
+func (i *fakeInstance) Read(offset, length uint32) ([]byte, bool) {
+	if uint64(offset)+uint64(length) > uint64(len(i.memory)) {
+		return nil, false
+	}
+	return i.memory[offset : offset+length], true
+}
+
+func (i *fakeInstance) Close(context.Context) error {
+	i.runtime.closed++
+	return nil
+}
+
+func writeModule(t *testing.T, path, content string, modTime time.Time) {
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal("Failed to write module: ", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal("Failed to set module time: ", err)
+	}
+}
+
+func TestNewCheck(t *testing.T) {
+	_, err := NewCheck(CheckConfig{Runtime: &fakeRuntime{}, Path: "check.wasm"})
+	assert.Error(t, err, "missing name")
+	_, err = NewCheck(CheckConfig{CheckName: "wasm.check", Path: "check.wasm"})
+	assert.Error(t, err, "missing runtime")
+	_, err = NewCheck(CheckConfig{CheckName: "wasm.check", Runtime: &fakeRuntime{}})
+	assert.Error(t, err, "missing path")
+
+	check, err := NewCheck(CheckConfig{CheckName: "wasm.check", Runtime: &fakeRuntime{}, Path: "check.wasm"})
+	assert.NoError(t, err)
+	assert.Equal(t, "wasm.check", check.Name())
+}
+
+func TestModuleCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasm")
+	if err != nil {
+		t.Fatal("Failed to create temp dir: ", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "check.wasm")
+
+	runtime := &fakeRuntime{}
+	check, err := NewCheck(CheckConfig{CheckName: "wasm.check", Runtime: runtime, Path: path})
+	assert.NoError(t, err)
+	contextCheck := check.(checks.ContextCheck)
+
+	_, err = check.Execute()
+	assert.Error(t, err, "missing module")
+
+	start := time.Now().Add(-time.Hour)
+	writeModule(t, path, `{"status":"degraded","details":"slow"}`, start)
+	result := contextCheck.ExecuteWithContext(context.Background())
+	assert.NoError(t, result.Err)
+	assert.Equal(t, checks.StatusDegraded, result.Status)
+	assert.Equal(t, "slow", result.Details)
+
+	_, err = check.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, runtime.instantiations, "unchanged module isn't reloaded")
+
+	writeModule(t, path, `{"status":"failing","error":"down"}`, start.Add(time.Minute))
+	result = contextCheck.ExecuteWithContext(context.Background())
+	assert.EqualError(t, result.Err, "down")
+	assert.Equal(t, checks.StatusFailing, result.Status)
+	assert.Equal(t, 2, runtime.instantiations, "changed module is reloaded")
+	assert.Equal(t, 1, runtime.closed, "previous instance is closed")
+
+	for content, expected := range map[string]string{
+		"trap":                  "module check failed: unreachable",
+		"oob":                   "sundheit_check returned a result out of the module memory",
+		"{":                     "module returned a malformed result: unexpected end of JSON input",
+		`{"status":"confused"}`: "module returned an unknown status: confused",
+	} {
+		start = start.Add(time.Minute)
+		writeModule(t, path, content, start)
+		_, err = check.Execute()
+		assert.EqualError(t, err, expected, content)
+	}
+
+	runtime.err = errors.New("invalid module")
+	writeModule(t, path, "{}", start.Add(time.Minute))
+	_, err = check.Execute()
+	assert.EqualError(t, err, "failed to instantiate the module: invalid module")
+
+	assert.NoError(t, check.(io.Closer).Close())
+	assert.Equal(t, runtime.instantiations, runtime.closed, "all instances are closed")
+}
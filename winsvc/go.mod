@@ -0,0 +1,11 @@
+module github.com/AppsFlyer/go-sundheit/winsvc
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.0.0
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/sys v0.0.0-20200323222414-85ca7c5b95cd
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
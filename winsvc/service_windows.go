@@ -0,0 +1,47 @@
+//go:build windows
+// +build windows
+
+package winsvc
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+var _ EventLog = (*eventlog.Log)(nil)
+
+// ChangesReporter reports the service status on the changes channel handed to `svc.Handler.Execute`.
+type ChangesReporter struct {
+	changes chan<- svc.Status
+	accepts svc.Accepted
+}
+
+// NewChangesReporter returns a StatusReporter sending on the given changes channel,
+// accepting the given commands once running.
+func NewChangesReporter(changes chan<- svc.Status, accepts svc.Accepted) *ChangesReporter {
+	return &ChangesReporter{
+		changes: changes,
+		accepts: accepts,
+	}
+}
+
+// StartPending sends a START_PENDING status
+func (r *ChangesReporter) StartPending(checkpoint uint32, waitHint time.Duration) error {
+	r.changes <- svc.Status{
+		State:      svc.StartPending,
+		CheckPoint: checkpoint,
+		WaitHint:   uint32(waitHint / time.Millisecond),
+	}
+	return nil
+}
+
+// Running sends a RUNNING status
+func (r *ChangesReporter) Running() error {
+	r.changes <- svc.Status{
+		State:   svc.Running,
+		Accepts: r.accepts,
+	}
+	return nil
+}
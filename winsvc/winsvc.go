@@ -0,0 +1,137 @@
+// Package winsvc integrates the health with the Windows Service Control Manager (SCM) and the Windows event log,
+// so services deployed on Windows hosts report start progress until healthy, and log their health transitions.
+package winsvc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AppsFlyer/go-sundheit"
+)
+
+const defaultInterval = 5 * time.Second
+
+// Event IDs of the entries written to the event log
+const (
+	EventHealthy   uint32 = 1
+	EventUnhealthy uint32 = 2
+)
+
+// StatusReporter reports the service status to the SCM.
+type StatusReporter interface {
+	// StartPending reports the service is still starting, with an increasing checkpoint and the time to wait for the next one.
+	StartPending(checkpoint uint32, waitHint time.Duration) error
+	// Running reports the service has started.
+	Running() error
+}
+
+// EventLog writes entries to the Windows event log; satisfied by `*eventlog.Log` from golang.org/x/sys/windows/svc/eventlog.
+type EventLog interface {
+	Info(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+}
+
+// Option configures Run
+type Option func(*config)
+
+type config struct {
+	eventLog EventLog
+	interval time.Duration
+}
+
+// WithEventLog sets the event log to write the health transitions to; transitions aren't logged by default.
+func WithEventLog(eventLog EventLog) Option {
+	return func(cfg *config) {
+		cfg.eventLog = eventLog
+	}
+}
+
+// WithInterval sets the interval the health is polled at, defaults to 5 seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(cfg *config) {
+		cfg.interval = interval
+	}
+}
+
+// Run reports the health to the SCM until the context is done:
+//   - START_PENDING is reported on each interval until the system becomes healthy for the first time
+//   - RUNNING is reported once the system becomes healthy for the first time
+//   - each health transition is written to the event log, when configured, as an information entry (EventHealthy)
+//     or an error entry (EventUnhealthy) listing the failing checks
+//
+// Run returns the context error, or the error of a failed status report.
+// Reporting the stop is left to the service handler, which owns the service lifecycle.
+func Run(ctx context.Context, h gosundheit.Health, status StatusReporter, opts ...Option) error {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	running := false
+	var checkpoint uint32
+	var lastHealthy *bool
+	for {
+		msg, healthy := statusOf(h)
+		if lastHealthy == nil || *lastHealthy != healthy {
+			lastHealthy = &healthy
+			logTransition(cfg.eventLog, msg, healthy)
+		}
+
+		var err error
+		switch {
+		case healthy && !running:
+			running = true
+			err = status.Running()
+		case !running:
+			checkpoint++
+			err = status.StartPending(checkpoint, 2*cfg.interval)
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func logTransition(eventLog EventLog, msg string, healthy bool) {
+	if eventLog == nil {
+		return
+	}
+	// failing to write to the event log must not fail the service
+	if healthy {
+		_ = eventLog.Info(EventHealthy, msg)
+	} else {
+		_ = eventLog.Error(EventUnhealthy, msg)
+	}
+}
+
+// statusOf returns a description of the current health, and the current health
+func statusOf(h gosundheit.Health) (string, bool) {
+	results, healthy := h.Results()
+	if healthy {
+		return fmt.Sprintf("healthy (%d checks)", len(results)), true
+	}
+
+	var failing []string
+	for name, result := range results {
+		if result.AffectsHealth() {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return fmt.Sprintf("unhealthy, failing checks: %s", strings.Join(failing, ", ")), false
+}
@@ -0,0 +1,95 @@
+package winsvc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AppsFlyer/go-sundheit/gosundheittest"
+)
+
+type recorder struct {
+	lock    sync.Mutex
+	entries []string
+}
+
+func (r *recorder) record(entry string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *recorder) recorded() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.entries...)
+}
+
+func (r *recorder) StartPending(checkpoint uint32, waitHint time.Duration) error {
+	return r.record("start pending")
+}
+
+func (r *recorder) Running() error {
+	return r.record("running")
+}
+
+func (r *recorder) Info(eid uint32, msg string) error {
+	return r.record("info: " + msg)
+}
+
+func (r *recorder) Error(eid uint32, msg string) error {
+	return r.record("error: " + msg)
+}
+
+func TestRun(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetFailing("db", errors.New("down"))
+
+	status := &recorder{}
+	eventLog := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, h, status, WithEventLog(eventLog), WithInterval(10*time.Millisecond))
+	}()
+
+	time.Sleep(25 * time.Millisecond)
+	h.SetPassing("db")
+	time.Sleep(20 * time.Millisecond)
+	h.SetFailing("db", errors.New("down"))
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	statuses := status.recorded()
+	assert.Equal(t, "start pending", statuses[0], "start is pending while unhealthy")
+	assert.Equal(t, "running", statuses[len(statuses)-1], "running once healthy, and stays running when unhealthy again")
+	assert.Equal(t, []string{
+		"error: unhealthy, failing checks: db",
+		"info: healthy (1 checks)",
+		"error: unhealthy, failing checks: db",
+	}, eventLog.recorded(), "only transitions are logged")
+}
+
+func TestRunStatusError(t *testing.T) {
+	h := gosundheittest.NewFakeHealth()
+	h.SetPassing("db")
+
+	err := Run(context.Background(), h, failingReporter{})
+	assert.EqualError(t, err, "status failed")
+}
+
+type failingReporter struct{}
+
+func (failingReporter) StartPending(uint32, time.Duration) error {
+	return errors.New("status failed")
+}
+
+func (failingReporter) Running() error {
+	return errors.New("status failed")
+}